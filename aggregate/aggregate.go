@@ -0,0 +1,521 @@
+// Package aggregate presents several controllers, as on a large property, as
+// one. Each controller is a Member with a short name, and its groups and
+// themes are addressed by paths such as "front/Path Lights" or "back/C".
+// Operations spanning controllers, such as ExtinguishAll, run on all of them
+// in parallel, and failures are reported per controller in an *Error.
+package aggregate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/scottlamb/luxor/protocol"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Separator separates a member's name from a group or theme in a path.
+const Separator = "/"
+
+// Member is one controller of an aggregate.
+type Member struct {
+	// Name prefixes the paths of the controller's groups and themes. It
+	// must be non-empty, unique, and free of Separator.
+	Name string
+
+	Controller protocol.Controller
+}
+
+// Controller is a namespace over several controllers.
+type Controller struct {
+	members []Member
+	byName  map[string]protocol.Controller
+}
+
+// New returns a Controller over the given members.
+func New(members ...Member) (*Controller, error) {
+	c := &Controller{byName: make(map[string]protocol.Controller, len(members))}
+	for _, m := range members {
+		switch {
+		case m.Name == "":
+			return nil, errors.New("member with empty name")
+		case strings.Contains(m.Name, Separator):
+			return nil, fmt.Errorf("member name %q contains %q", m.Name, Separator)
+		case c.byName[m.Name] != nil:
+			return nil, fmt.Errorf("duplicate member name %q", m.Name)
+		case m.Controller == nil:
+			return nil, fmt.Errorf("member %q has no controller", m.Name)
+		}
+		c.byName[m.Name] = m.Controller
+		c.members = append(c.members, m)
+	}
+	return c, nil
+}
+
+// Members returns the members' names, in the order given to New.
+func (c *Controller) Members() []string {
+	names := make([]string, len(c.members))
+	for i, m := range c.members {
+		names[i] = m.Name
+	}
+	return names
+}
+
+// Member returns the named member's controller, or nil.
+func (c *Controller) Member(name string) protocol.Controller {
+	return c.byName[name]
+}
+
+// Path returns the path of a member's group or theme.
+func Path(member, name string) string {
+	return member + Separator + name
+}
+
+// SplitPath splits a path into a member name and a group or theme name,
+// which may itself contain Separator.
+func SplitPath(path string) (member, name string, err error) {
+	member, name, ok := strings.Cut(path, Separator)
+	if !ok || member == "" || name == "" {
+		return "", "", fmt.Errorf("bad path %q; want CONTROLLER%sNAME", path, Separator)
+	}
+	return member, name, nil
+}
+
+// Error holds the errors from the members for which an operation failed.
+type Error struct {
+	Errs map[string]error
+}
+
+func (e *Error) Error() string {
+	names := make([]string, 0, len(e.Errs))
+	for name := range e.Errs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	msgs := make([]string, len(names))
+	for i, name := range names {
+		msgs[i] = name + ": " + e.Errs[name].Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap supports errors.Is and errors.As over the members' errors.
+func (e *Error) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errs))
+	for _, err := range e.Errs {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// Failed returns the names of the members for which err, as returned by a
+// Controller method, records a failure.
+func Failed(err error) []string {
+	var e *Error
+	if !errors.As(err, &e) {
+		return nil
+	}
+	names := make([]string, 0, len(e.Errs))
+	for name := range e.Errs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// each calls f for every member in parallel, returning an *Error if any
+// calls fail.
+func (c *Controller) each(ctx context.Context, f func(ctx context.Context, i int, m Member) error) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs map[string]error
+	)
+	for i, m := range c.members {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := f(ctx, i, m); err != nil {
+				mu.Lock()
+				if errs == nil {
+					errs = make(map[string]error)
+				}
+				errs[m.Name] = err
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if errs != nil {
+		return &Error{errs}
+	}
+	return nil
+}
+
+// ExtinguishAll turns off every group of every member.
+func (c *Controller) ExtinguishAll(ctx context.Context) error {
+	return c.each(ctx, func(ctx context.Context, _ int, m Member) error {
+		_, err := m.Controller.ExtinguishAll(ctx, &protocol.ExtinguishAllRequest{})
+		return err
+	})
+}
+
+// IlluminateAll turns on every group of every member.
+func (c *Controller) IlluminateAll(ctx context.Context) error {
+	return c.each(ctx, func(ctx context.Context, _ int, m Member) error {
+		_, err := m.Controller.IlluminateAll(ctx, &protocol.IlluminateAllRequest{})
+		return err
+	})
+}
+
+// Group is a member's group.
+type Group struct {
+	Member string
+	Path   string
+
+	GroupNumber uint8
+	Name        string
+	Intensity   uint8
+
+	// Type and Color are as in protocol.Group.
+	Type  uint8 `json:",omitempty"`
+	Color uint8 `json:",omitempty"`
+
+	// Order is the group's zero-based position in its controller's UI.
+	Order int
+}
+
+// Theme is a member's theme.
+type Theme struct {
+	Member string
+	Path   string
+
+	ThemeIndex uint8
+	Letter     string
+	Name       string
+	OnOff      uint8
+
+	// Order is the theme's zero-based position in its controller's UI.
+	Order int
+}
+
+func toGroups(member string, list []protocol.Group) []Group {
+	groups := make([]Group, len(list))
+	for i, g := range list {
+		groups[i] = Group{
+			Member:      member,
+			Path:        Path(member, g.Name),
+			GroupNumber: g.GroupNumber,
+			Name:        g.Name,
+			Intensity:   g.Intensity,
+			Type:        g.Type,
+			Color:       g.Color,
+			Order:       i,
+		}
+	}
+	return groups
+}
+
+func toThemes(member string, list []protocol.Theme) []Theme {
+	themes := make([]Theme, len(list))
+	for i, t := range list {
+		themes[i] = Theme{
+			Member:     member,
+			Path:       Path(member, t.Name),
+			ThemeIndex: t.ThemeIndex,
+			Letter:     protocol.ThemeLetter(t.ThemeIndex),
+			Name:       t.Name,
+			OnOff:      t.OnOff,
+			Order:      i,
+		}
+	}
+	return themes
+}
+
+// memberState is what Snapshot learns of one member.
+type memberState struct {
+	name       string
+	groups     []Group
+	themes     []Theme
+	restricted bool
+}
+
+func read(ctx context.Context, m Member) (*memberState, error) {
+	s := &memberState{}
+	name, err := m.Controller.ControllerName(ctx, &protocol.ControllerNameRequest{})
+	if err != nil {
+		return nil, err
+	}
+	s.name = name.Controller
+	groups, err := m.Controller.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	if err != nil {
+		return nil, err
+	}
+	themes, err := m.Controller.ThemeListGet(ctx, &protocol.ThemeListGetRequest{})
+	if err != nil {
+		return nil, err
+	}
+	s.groups = toGroups(m.Name, groups.GroupList)
+	s.themes = toThemes(m.Name, themes.ThemeList)
+	s.restricted = themes.Restricted != 0
+	return s, nil
+}
+
+// MemberStatus is a member's entry in a Snapshot.
+type MemberStatus struct {
+	Member         string
+	ControllerName string `json:",omitempty"`
+	Restricted     bool   `json:",omitempty"`
+
+	// Error is why the member couldn't be read, if it couldn't.
+	Error string `json:",omitempty"`
+}
+
+// Snapshot is the merged state of all members.
+type Snapshot struct {
+	Taken   time.Time
+	Members []MemberStatus
+
+	// Groups and Themes are in member order, then each controller's UI
+	// order.
+	Groups []Group
+	Themes []Theme
+}
+
+// Snapshot reads every member's groups and themes in parallel. Members which
+// can't be read are described in Snapshot.Members, and also in the returned
+// *Error, alongside the snapshot of the rest.
+func (c *Controller) Snapshot(ctx context.Context) (*Snapshot, error) {
+	states := make([]*memberState, len(c.members))
+	err := c.each(ctx, func(ctx context.Context, i int, m Member) error {
+		s, err := read(ctx, m)
+		states[i] = s
+		return err
+	})
+	snap := &Snapshot{Taken: time.Now()}
+	var e *Error
+	errors.As(err, &e)
+	for i, m := range c.members {
+		st := MemberStatus{Member: m.Name}
+		if s := states[i]; s != nil {
+			st.ControllerName = s.name
+			st.Restricted = s.restricted
+			snap.Groups = append(snap.Groups, s.groups...)
+			snap.Themes = append(snap.Themes, s.themes...)
+		} else if e != nil {
+			st.Error = e.Errs[m.Name].Error()
+		}
+		snap.Members = append(snap.Members, st)
+	}
+	return snap, err
+}
+
+// Groups returns every member's groups, as in Snapshot.
+func (c *Controller) Groups(ctx context.Context) ([]Group, error) {
+	groups := make([][]Group, len(c.members))
+	err := c.each(ctx, func(ctx context.Context, i int, m Member) error {
+		resp, err := m.Controller.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+		if err == nil {
+			groups[i] = toGroups(m.Name, resp.GroupList)
+		}
+		return err
+	})
+	var all []Group
+	for _, g := range groups {
+		all = append(all, g...)
+	}
+	return all, err
+}
+
+// Themes returns every member's themes, as in Snapshot.
+func (c *Controller) Themes(ctx context.Context) ([]Theme, error) {
+	themes := make([][]Theme, len(c.members))
+	err := c.each(ctx, func(ctx context.Context, i int, m Member) error {
+		resp, err := m.Controller.ThemeListGet(ctx, &protocol.ThemeListGetRequest{})
+		if err == nil {
+			themes[i] = toThemes(m.Name, resp.ThemeList)
+		}
+		return err
+	})
+	var all []Theme
+	for _, t := range themes {
+		all = append(all, t...)
+	}
+	return all, err
+}
+
+// Colors returns the named member's palette. It fails with
+// StatusUnknownMethod if the member's controller doesn't implement
+// protocol.ColorController or is a ZD controller.
+func (c *Controller) Colors(ctx context.Context, member string) ([]protocol.Color, error) {
+	mc := c.byName[member]
+	if mc == nil {
+		return nil, fmt.Errorf("no controller %q; have %q", member, c.Members())
+	}
+	cc, ok := mc.(protocol.ColorController)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", member, protocol.ErrorForStatus(protocol.StatusUnknownMethod))
+	}
+	resp, err := cc.ColorListGet(ctx, &protocol.ColorListGetRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", member, err)
+	}
+	return resp.ColorList, nil
+}
+
+// FindGroup finds a group by path. The part after the member's name is a
+// group name, matched exactly and then case-insensitively, or failing that a
+// group number.
+func (c *Controller) FindGroup(ctx context.Context, path string) (*Group, error) {
+	member, name, err := SplitPath(path)
+	if err != nil {
+		return nil, err
+	}
+	mc := c.byName[member]
+	if mc == nil {
+		return nil, fmt.Errorf("%s: no controller %q; have %q", path, member, c.Members())
+	}
+	resp, err := mc.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", member, err)
+	}
+	i := findGroup(resp.GroupList, name)
+	if i < 0 {
+		return nil, fmt.Errorf("%s: no such group: %w", path, protocol.ErrorForStatus(protocol.StatusPreconditionFailed))
+	}
+	return &toGroups(member, resp.GroupList)[i], nil
+}
+
+// FindTheme finds a theme by path. The part after the member's name is a
+// theme name, matched exactly and then case-insensitively, or failing that a
+// theme letter.
+func (c *Controller) FindTheme(ctx context.Context, path string) (*Theme, error) {
+	member, name, err := SplitPath(path)
+	if err != nil {
+		return nil, err
+	}
+	mc := c.byName[member]
+	if mc == nil {
+		return nil, fmt.Errorf("%s: no controller %q; have %q", path, member, c.Members())
+	}
+	resp, err := mc.ThemeListGet(ctx, &protocol.ThemeListGetRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", member, err)
+	}
+	i := find(len(resp.ThemeList), name,
+		func(i int) string { return resp.ThemeList[i].Name },
+		func(i int) bool {
+			index, err := protocol.ParseThemeLetter(name)
+			return err == nil && resp.ThemeList[i].ThemeIndex == index
+		})
+	if i < 0 {
+		return nil, fmt.Errorf("%s: no such theme: %w", path, protocol.ErrorForStatus(protocol.StatusPreconditionFailed))
+	}
+	return &toThemes(member, resp.ThemeList)[i], nil
+}
+
+func findGroup(groups []protocol.Group, name string) int {
+	return find(len(groups), name,
+		func(i int) string { return groups[i].Name },
+		func(i int) bool {
+			n, err := strconv.ParseUint(name, 10, 8)
+			return err == nil && groups[i].GroupNumber == uint8(n)
+		})
+}
+
+// find returns the index of the first of n items whose name is name, then
+// whose name matches case-insensitively, then which matches by other means,
+// or -1.
+func find(n int, name string, nameOf func(int) string, other func(int) bool) int {
+	for _, match := range []func(int) bool{
+		func(i int) bool { return nameOf(i) == name },
+		func(i int) bool { return strings.EqualFold(nameOf(i), name) },
+		other,
+	} {
+		for i := 0; i < n; i++ {
+			if match(i) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// IlluminateGroup sets the intensity of the group at path.
+func (c *Controller) IlluminateGroup(ctx context.Context, path string, intensity uint8) error {
+	return c.IlluminateGroupColor(ctx, path, intensity, 0)
+}
+
+// IlluminateGroupColor sets the intensity of the group at path and, if color
+// is non-zero, its color number.
+func (c *Controller) IlluminateGroupColor(ctx context.Context, path string, intensity, color uint8) error {
+	g, err := c.FindGroup(ctx, path)
+	if err != nil {
+		return err
+	}
+	_, err = c.byName[g.Member].IlluminateGroup(ctx, &protocol.IlluminateGroupRequest{GroupNumber: g.GroupNumber, Intensity: intensity, Color: color})
+	if err != nil {
+		return fmt.Errorf("%s: %w", g.Path, err)
+	}
+	return nil
+}
+
+// IlluminateTheme turns the theme at path on or off.
+func (c *Controller) IlluminateTheme(ctx context.Context, path string, on bool) error {
+	t, err := c.FindTheme(ctx, path)
+	if err != nil {
+		return err
+	}
+	req := &protocol.IlluminateThemeRequest{ThemeIndex: t.ThemeIndex}
+	if on {
+		req.OnOff = 1
+	}
+	if _, err := c.byName[t.Member].IlluminateTheme(ctx, req); err != nil {
+		return fmt.Errorf("%s: %w", t.Path, err)
+	}
+	return nil
+}
+
+// IlluminateGroups sets the intensities of several groups, given by path,
+// in parallel across members. Paths which can't be resolved fail the whole
+// call before any group is changed.
+func (c *Controller) IlluminateGroups(ctx context.Context, intensities map[string]uint8) error {
+	groups, err := c.Groups(ctx)
+	if err != nil {
+		return err
+	}
+	byMember := make(map[string][]protocol.IlluminateGroupRequest)
+	for path, intensity := range intensities {
+		member, name, err := SplitPath(path)
+		if err != nil {
+			return err
+		}
+		if c.byName[member] == nil {
+			return fmt.Errorf("%s: no controller %q; have %q", path, member, c.Members())
+		}
+		var mine []protocol.Group
+		for _, g := range groups {
+			if g.Member == member {
+				mine = append(mine, protocol.Group{GroupNumber: g.GroupNumber, Name: g.Name})
+			}
+		}
+		i := findGroup(mine, name)
+		if i < 0 {
+			return fmt.Errorf("%s: no such group: %w", path, protocol.ErrorForStatus(protocol.StatusPreconditionFailed))
+		}
+		byMember[member] = append(byMember[member], protocol.IlluminateGroupRequest{GroupNumber: mine[i].GroupNumber, Intensity: intensity})
+	}
+	return c.each(ctx, func(ctx context.Context, _ int, m Member) error {
+		reqs := byMember[m.Name]
+		sort.Slice(reqs, func(i, j int) bool { return reqs[i].GroupNumber < reqs[j].GroupNumber })
+		for _, req := range reqs {
+			if _, err := m.Controller.IlluminateGroup(ctx, &req); err != nil {
+				return fmt.Errorf("group %d: %w", req.GroupNumber, err)
+			}
+		}
+		return nil
+	})
+}
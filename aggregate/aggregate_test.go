@@ -0,0 +1,181 @@
+package aggregate_test
+
+import (
+	"context"
+	"errors"
+	"github.com/scottlamb/luxor/aggregate"
+	"github.com/scottlamb/luxor/emulator"
+	"github.com/scottlamb/luxor/protocol"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// down is a controller which can't be reached.
+type down struct {
+	protocol.Controller
+}
+
+var errDown = errors.New("connection refused")
+
+func (down) ControllerName(context.Context, *protocol.ControllerNameRequest) (*protocol.ControllerNameResponse, error) {
+	return nil, errDown
+}
+
+func (down) GroupListGet(context.Context, *protocol.GroupListGetRequest) (*protocol.GroupListGetResponse, error) {
+	return nil, errDown
+}
+
+func (down) ExtinguishAll(context.Context, *protocol.ExtinguishAllRequest) (*protocol.ExtinguishAllResponse, error) {
+	return nil, errDown
+}
+
+func newEmulator(t *testing.T, name string, groups ...string) *emulator.Controller {
+	t.Helper()
+	ctx := context.Background()
+	e := emulator.New(name)
+	for i, g := range groups {
+		if _, err := e.GroupListAdd(ctx, &protocol.GroupListAddRequest{GroupNumber: uint8(i + 1), Name: g}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := e.ThemeListAdd(ctx, &protocol.ThemeListAddRequest{ThemeIndex: 2, Name: "Evening"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.ThemeSet(ctx, &protocol.ThemeSetRequest{ThemeIndex: 2, Groups: []protocol.ThemeGroup{{GroupNumber: 1, Intensity: 70}}}); err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestNew(t *testing.T) {
+	e := emulator.New("x")
+	for _, members := range [][]aggregate.Member{
+		{{Name: "", Controller: e}},
+		{{Name: "a/b", Controller: e}},
+		{{Name: "a", Controller: e}, {Name: "a", Controller: e}},
+		{{Name: "a"}},
+	} {
+		if _, err := aggregate.New(members...); err == nil {
+			t.Errorf("New(%+v): no error", members)
+		}
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	ctx := context.Background()
+	front := newEmulator(t, "Front Yard", "Path", "Trees")
+	back := newEmulator(t, "Back Yard", "Patio", "Pool")
+	a, err := aggregate.New(aggregate.Member{Name: "front", Controller: front}, aggregate.Member{Name: "back", Controller: back})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.IlluminateGroup(ctx, "front/path", 40); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.IlluminateGroup(ctx, "back/2", 60); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.IlluminateTheme(ctx, "back/C", true); err != nil {
+		t.Fatal(err)
+	}
+	snap, err := a.Snapshot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var groups []string
+	for _, g := range snap.Groups {
+		groups = append(groups, g.Path+"="+strconv.Itoa(int(g.Intensity)))
+	}
+	if want := []string{"front/Path=40", "front/Trees=0", "back/Patio=70", "back/Pool=60"}; !reflect.DeepEqual(groups, want) {
+		t.Errorf("snapshot groups = %q; want %q", groups, want)
+	}
+	if len(snap.Themes) != 2 || snap.Themes[1].Path != "back/Evening" || snap.Themes[1].OnOff == 0 || snap.Themes[0].OnOff != 0 {
+		t.Errorf("snapshot themes = %+v", snap.Themes)
+	}
+	if snap.Members[0].ControllerName != "Front Yard" || snap.Members[1].ControllerName != "Back Yard" {
+		t.Errorf("snapshot members = %+v", snap.Members)
+	}
+
+	if err := a.IlluminateGroups(ctx, map[string]uint8{"front/Trees": 10, "back/Pool": 20}); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.IlluminateGroups(ctx, map[string]uint8{"front/Trees": 30, "back/Shed": 20}); protocol.StatusOf(err) != protocol.StatusPreconditionFailed {
+		t.Errorf("IlluminateGroups with unknown group: %v", err)
+	}
+	all, err := a.Groups(ctx)
+	if err != nil || all[1].Intensity != 10 || all[3].Intensity != 20 {
+		t.Errorf("Groups = %+v, %v", all, err)
+	}
+
+	for _, path := range []string{"side/Path", "front", "front/Shed"} {
+		if err := a.IlluminateGroup(ctx, path, 10); err == nil {
+			t.Errorf("IlluminateGroup(%q): no error", path)
+		}
+	}
+
+	if err := a.ExtinguishAll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if all, _ := a.Groups(ctx); all[0].Intensity != 0 || all[2].Intensity != 0 {
+		t.Errorf("after ExtinguishAll: %+v", all)
+	}
+}
+
+func TestPartialFailure(t *testing.T) {
+	ctx := context.Background()
+	front := newEmulator(t, "Front Yard", "Path")
+	a, err := aggregate.New(aggregate.Member{Name: "front", Controller: front}, aggregate.Member{Name: "side", Controller: down{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := front.IlluminateGroup(ctx, &protocol.IlluminateGroupRequest{GroupNumber: 1, Intensity: 50}); err != nil {
+		t.Fatal(err)
+	}
+	err = a.ExtinguishAll(ctx)
+	if !errors.Is(err, errDown) || !reflect.DeepEqual(aggregate.Failed(err), []string{"side"}) {
+		t.Errorf("ExtinguishAll = %v", err)
+	}
+	all, err := a.Groups(ctx)
+	if len(all) != 1 || all[0].Intensity != 0 || !reflect.DeepEqual(aggregate.Failed(err), []string{"side"}) {
+		t.Errorf("Groups = %+v, %v", all, err)
+	}
+
+	snap, err := a.Snapshot(ctx)
+	if err == nil || err.Error() != "side: connection refused" {
+		t.Errorf("Snapshot error = %v", err)
+	}
+	if len(snap.Groups) != 1 || snap.Members[0].Error != "" || snap.Members[1].Error != "connection refused" {
+		t.Errorf("snapshot = %+v", snap)
+	}
+}
+
+func TestColors(t *testing.T) {
+	ctx := context.Background()
+	zdc := emulator.NewZDC("Front Yard")
+	if _, err := zdc.ColorListSet(ctx, &protocol.ColorListSetRequest{ColorNumber: 4, Hue: 30, Saturation: 100}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := zdc.GroupListAdd(ctx, &protocol.GroupListAddRequest{GroupNumber: 1, Name: "Trees", Type: protocol.GroupTypeColor}); err != nil {
+		t.Fatal(err)
+	}
+	a, err := aggregate.New(aggregate.Member{Name: "front", Controller: zdc}, aggregate.Member{Name: "back", Controller: down{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	colors, err := a.Colors(ctx, "front")
+	if want := []protocol.Color{{ColorNumber: 4, Hue: 30, Saturation: 100}}; err != nil || !reflect.DeepEqual(colors, want) {
+		t.Errorf("Colors(front) = %+v, %v", colors, err)
+	}
+	if _, err := a.Colors(ctx, "back"); protocol.StatusOf(err) != protocol.StatusUnknownMethod {
+		t.Errorf("Colors(back): %v", err)
+	}
+	if err := a.IlluminateGroupColor(ctx, "front/Trees", 50, 4); err != nil {
+		t.Fatal(err)
+	}
+	g, err := a.FindGroup(ctx, "front/Trees")
+	if err != nil || g.Type != protocol.GroupTypeColor || g.Color != 4 || g.Intensity != 50 {
+		t.Errorf("FindGroup = %+v, %v", g, err)
+	}
+}
@@ -6,26 +6,371 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/scottlamb/luxor/protocol"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"io/ioutil"
+	"log"
+	"net"
 	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
 )
 
-// *Controller implements protocol.Controller
+// *Controller implements protocol.ColorController. Against a ZD controller,
+// which lacks colors, requests carrying colors have them removed; see
+// SupportsColor.
 type Controller struct {
 	BaseURL string
+
+	// Observer, if non-nil, is notified of every RPC's outcome.
+	Observer Observer
+
+	// TracerProvider supplies the tracer for RPC spans. If nil, the global
+	// provider from otel.GetTracerProvider is used. Each RPC's span is a
+	// child of any span in the caller's context, with sub-spans for the
+	// phases of the HTTP request.
+	TracerProvider trace.TracerProvider
+
+	// Timeout, if non-zero, bounds each RPC, in addition to any deadline on
+	// the caller's context.
+	Timeout time.Duration
+
+	// Name, if non-empty, identifies the controller by its ControllerName
+	// rather than by BaseURL alone. The client checks the name before first
+	// use and after losing contact, and fails with a *WrongControllerError
+	// rather than talk to a different controller.
+	Name string
+
+	// Resolver, if non-nil, finds the controller named Name when it stops
+	// answering at its address, as after the router assigns it a new one.
+	// After ResolveAfter consecutive connection failures, and at most once
+	// per MinResolveInterval, the client asks Resolver for the controller's
+	// address and, if found, switches to it and logs the change.
+	Resolver Resolver
+
+	// ResolveAfter defaults to DefaultResolveAfter.
+	ResolveAfter int
+
+	// MinResolveInterval defaults to DefaultMinResolveInterval.
+	MinResolveInterval time.Duration
+
+	// mu protects the fields below, which track the controller named Name.
+	mu          sync.Mutex
+	endpoint    string // current base URL, if it differs from BaseURL.
+	verified    bool   // the controller at the endpoint has been checked.
+	failures    int    // consecutive connection failures.
+	resolving   bool
+	lastResolve time.Time
+
+	// colorMu protects color, which is colorUnknown until the controller
+	// has answered a color method.
+	colorMu sync.Mutex
+	color   int
+}
+
+// Defaults for Controller fields.
+const (
+	DefaultResolveAfter       = 3
+	DefaultMinResolveInterval = time.Minute
+)
+
+// Resolver finds a controller's base URL by its ControllerName.
+// discovery.Resolver implements it by scanning the local network.
+type Resolver interface {
+	// Resolve returns the base URL of the controller named name. It must
+	// check the name, and fail if it finds no such controller or several.
+	Resolve(ctx context.Context, name string) (baseURL string, err error)
+}
+
+// WrongControllerError means the controller at the client's address isn't
+// the one named Controller.Name.
+type WrongControllerError struct {
+	BaseURL string
+	Want    string
+	Got     string
+}
+
+func (e *WrongControllerError) Error() string {
+	return fmt.Sprintf("controller at %s is %q, not %q", e.BaseURL, e.Got, e.Want)
+}
+
+// Observer receives the outcome of each RPC, for metrics or logging.
+type Observer interface {
+	// ObserveRPC is called after each RPC completes. status is the
+	// response's Status field, or -1 if no response was parsed. err is the
+	// error the method returns; see ErrorClass.
+	ObserveRPC(method string, elapsed time.Duration, status int, err error)
+}
+
+// Error classes, as returned by ErrorClass.
+const (
+	ClassNone      = ""          // no error.
+	ClassStatus    = "status"    // the controller returned a non-zero Status.
+	ClassCanceled  = "canceled"  // the context was canceled.
+	ClassTimeout   = "timeout"   // the context deadline or a network timeout was hit.
+	ClassDNS       = "dns"       // the controller's hostname could not be resolved.
+	ClassConnect   = "connect"   // the TCP connection could not be established.
+	ClassHTTP      = "http"      // the controller returned a non-200 HTTP status.
+	ClassMalformed = "malformed" // the response had the wrong content type or bad JSON.
+	ClassOther     = "other"     // anything else, such as a reset connection.
+)
+
+// httpStatusError is returned when the controller responds with a non-200
+// HTTP status.
+type httpStatusError struct {
+	msg string
+}
+
+func (e *httpStatusError) Error() string { return e.msg }
+
+// malformedError is returned when the controller's response can't be parsed.
+type malformedError struct {
+	msg string
+}
+
+func (e *malformedError) Error() string { return e.msg }
+
+// ErrorClass classifies an error returned by a Controller method into one of
+// the Class constants, for metrics and tracing.
+func ErrorClass(err error) string {
+	if err == nil {
+		return ClassNone
+	}
+	var (
+		statusErr    *protocol.StatusError
+		httpErr      *httpStatusError
+		malformedErr *malformedError
+		dnsErr       *net.DNSError
+		opErr        *net.OpError
+		netErr       net.Error
+	)
+	switch {
+	case errors.As(err, &statusErr):
+		return ClassStatus
+	case errors.As(err, &httpErr):
+		return ClassHTTP
+	case errors.As(err, &malformedErr):
+		return ClassMalformed
+	case errors.Is(err, context.Canceled):
+		return ClassCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return ClassTimeout
+	case errors.As(err, &dnsErr):
+		return ClassDNS
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return ClassTimeout
+	case errors.As(err, &opErr) && opErr.Op == "dial":
+		return ClassConnect
+	}
+	return ClassOther
+}
+
+// Endpoint returns the base URL the client is using: BaseURL, or where the
+// controller named Name was last found.
+func (c *Controller) Endpoint() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.endpointLocked()
+}
+
+func (c *Controller) endpointLocked() string {
+	if c.endpoint != "" {
+		return c.endpoint
+	}
+	return c.BaseURL
 }
 
 // request issues a request for method with prefilled request and ready-to-fill
 // response. It returns error on JSON- or HTTP-level problems; it does not
 // check the Status field in the response.
-func (c *Controller) request(ctx context.Context, method string, request interface{}, response interface{}) (err error) {
+func (c *Controller) request(ctx context.Context, method string, request interface{}, response interface{}) error {
+	if c.Name == "" {
+		return c.send(ctx, c.BaseURL, method, request, response)
+	}
+	baseURL, err := c.verify(ctx)
+	if err != nil {
+		return err
+	}
+	err = c.send(ctx, baseURL, method, request, response)
+	if moved, ok := c.observe(ctx, baseURL, err); ok && neverSent(err) {
+		// The request didn't reach the old address, so it's safe to send
+		// to the new one.
+		return c.send(ctx, moved, method, request, response)
+	}
+	return err
+}
+
+// unreachable is true for errors which suggest the controller is no longer at
+// its address.
+func unreachable(err error) bool {
+	switch ErrorClass(err) {
+	case ClassConnect, ClassDNS, ClassTimeout:
+		return true
+	}
+	return false
+}
+
+// neverSent is true for errors which mean the request can't have reached the
+// controller.
+func neverSent(err error) bool {
+	switch ErrorClass(err) {
+	case ClassConnect, ClassDNS:
+		return true
+	}
+	return false
+}
+
+// verify returns the endpoint once it's known to be the controller named
+// Name, checking if necessary.
+func (c *Controller) verify(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	baseURL, verified := c.endpointLocked(), c.verified
+	c.mu.Unlock()
+	if verified {
+		return baseURL, nil
+	}
+	err := c.checkName(ctx, baseURL)
+	var wrong *WrongControllerError
+	if errors.As(err, &wrong) {
+		if moved, ok := c.resolve(ctx, baseURL, err); ok {
+			return moved, nil
+		}
+		return "", err
+	}
+	if moved, ok := c.observe(ctx, baseURL, err); ok {
+		return moved, nil
+	} else if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	if c.endpointLocked() == baseURL {
+		c.verified = true
+	}
+	c.mu.Unlock()
+	return baseURL, nil
+}
+
+// checkName checks that the controller at baseURL is the one named Name.
+func (c *Controller) checkName(ctx context.Context, baseURL string) error {
+	resp := &protocol.ControllerNameResponse{}
+	if err := c.send(ctx, baseURL, "ControllerName", &protocol.ControllerNameRequest{}, resp); err != nil {
+		return err
+	}
+	if err := protocol.ErrorForStatus(resp.Status); err != nil {
+		return err
+	}
+	if resp.Controller != c.Name {
+		return &WrongControllerError{BaseURL: baseURL, Want: c.Name, Got: resp.Controller}
+	}
+	return nil
+}
+
+// observe notes the outcome of a request to baseURL. After enough connection
+// failures, it tries to find the controller elsewhere, returning its new
+// endpoint.
+func (c *Controller) observe(ctx context.Context, baseURL string, err error) (string, bool) {
+	c.mu.Lock()
+	if c.endpointLocked() != baseURL {
+		c.mu.Unlock()
+		return "", false // the endpoint changed while the request ran.
+	}
+	if !unreachable(err) {
+		c.failures = 0
+		c.mu.Unlock()
+		return "", false
+	}
+	c.verified = false // check the name when contact resumes.
+	c.failures++
+	threshold := c.ResolveAfter
+	if threshold <= 0 {
+		threshold = DefaultResolveAfter
+	}
+	due := c.failures >= threshold
+	c.mu.Unlock()
+	if !due {
+		return "", false
+	}
+	return c.resolve(ctx, baseURL, err)
+}
+
+// resolve asks the Resolver where the controller is, because of cause, unless
+// it's been asked too recently or another request is already asking.
+func (c *Controller) resolve(ctx context.Context, baseURL string, cause error) (string, bool) {
+	if c.Resolver == nil {
+		return "", false
+	}
+	interval := c.MinResolveInterval
+	if interval <= 0 {
+		interval = DefaultMinResolveInterval
+	}
+	c.mu.Lock()
+	if c.resolving || (!c.lastResolve.IsZero() && time.Since(c.lastResolve) < interval) {
+		c.mu.Unlock()
+		return "", false
+	}
+	c.resolving = true
+	c.mu.Unlock()
+
+	log.Printf("client: looking for controller %q, as %s failed: %v", c.Name, baseURL, cause)
+	moved, err := c.Resolver.Resolve(ctx, c.Name)
+	moved = strings.TrimSuffix(moved, "/")
+	if err == nil {
+		err = c.checkName(ctx, moved) // rather than trust the Resolver.
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.resolving = false
+	if ctx.Err() == nil {
+		c.lastResolve = time.Now() // an abandoned search doesn't count.
+	}
+	if err != nil {
+		log.Printf("client: can't find controller %q: %v", c.Name, err)
+		return "", false
+	}
+	if moved != baseURL {
+		log.Printf("client: controller %q moved from %s to %s", c.Name, baseURL, moved)
+	}
+	c.endpoint = moved
+	c.verified = true
+	c.forgetColor() // it may be a different model of controller.
+	c.failures = 0
+	return moved, true
+}
+
+// send issues a request to the controller at baseURL.
+func (c *Controller) send(ctx context.Context, baseURL, method string, request interface{}, response interface{}) (err error) {
+	start := time.Now()
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+	ctx, span := c.startSpan(ctx, baseURL, method, request)
+	var timer *httpTimer
+	if span.IsRecording() {
+		ctx, timer = withHTTPTimer(ctx, c.tracer())
+	}
+	defer func() {
+		if timer != nil {
+			timer.finish(err)
+		}
+		status, observedErr := outcome(response, err)
+		endSpan(span, status, observedErr)
+		if c.Observer != nil {
+			c.Observer.ObserveRPC(method, time.Since(start), status, observedErr)
+		}
+	}()
 	serializedReq, err := json.Marshal(request)
 	if err != nil {
 		return err
 	}
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.BaseURL+"/"+method+".json", bytes.NewReader(serializedReq))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/"+method+".json", bytes.NewReader(serializedReq))
 	if err != nil {
 		return err
 	}
@@ -35,24 +380,35 @@ func (c *Controller) request(ctx context.Context, method string, request interfa
 		return err
 	}
 	defer httpResp.Body.Close()
+	span.SetAttributes(attribute.Int("http.response.status_code", httpResp.StatusCode))
+	_, readSpan := c.tracer().Start(ctx, "http.read_body")
 	body, err := ioutil.ReadAll(httpResp.Body)
+	endWithError(readSpan, err)
 	if err != nil {
 		return err
 	}
 	if httpResp.StatusCode != http.StatusOK {
-		return fmt.Errorf("Unexpected HTTP status: %q with body: %q", httpResp.Status, body)
+		return &httpStatusError{fmt.Sprintf("Unexpected HTTP status: %q with body: %q", httpResp.Status, body)}
 	}
 	if contentType := httpResp.Header.Get("Content-Type"); contentType != "application/json" {
-		return fmt.Errorf("Unexpected response content type: %q with body: %q", contentType, body)
+		return &malformedError{fmt.Sprintf("Unexpected response content type: %q with body: %q", contentType, body)}
 	}
 
 	err = json.Unmarshal(body, response)
 	if err != nil {
-		return fmt.Errorf("JSON error: %v while parsing body: %q", err, body)
+		return &malformedError{fmt.Sprintf("JSON error: %v while parsing body: %q", err, body)}
 	}
 	return nil
 }
 
+// statusOf returns the Status field of a pointer to a response struct.
+func statusOf(response interface{}) int {
+	if f := reflect.ValueOf(response).Elem().FieldByName("Status"); f.Kind() == reflect.Int {
+		return int(f.Int())
+	}
+	return -1
+}
+
 // The methods below are all boilerplate.
 
 func (c *Controller) AssignLight(ctx context.Context, req *protocol.AssignLightRequest) (*protocol.AssignLightResponse, error) {
@@ -88,6 +444,17 @@ func (c *Controller) FlashLights(ctx context.Context, req *protocol.FlashLightsR
 }
 
 func (c *Controller) GroupListAdd(ctx context.Context, req *protocol.GroupListAddRequest) (*protocol.GroupListAddResponse, error) {
+	if req.Type != protocol.GroupTypeMonochrome {
+		color, err := c.SupportsColor(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !color {
+			monochrome := *req
+			monochrome.Type = protocol.GroupTypeMonochrome
+			req = &monochrome
+		}
+	}
 	resp := &protocol.GroupListAddResponse{}
 	if err := c.request(ctx, "GroupListAdd", req, resp); err != nil {
 		return nil, err
@@ -144,6 +511,17 @@ func (c *Controller) IlluminateAll(ctx context.Context, req *protocol.Illuminate
 }
 
 func (c *Controller) IlluminateGroup(ctx context.Context, req *protocol.IlluminateGroupRequest) (*protocol.IlluminateGroupResponse, error) {
+	if req.Color != 0 {
+		color, err := c.SupportsColor(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !color {
+			colorless := *req
+			colorless.Color = 0
+			req = &colorless
+		}
+	}
 	resp := &protocol.IlluminateGroupResponse{}
 	if err := c.request(ctx, "IlluminateGroup", req, resp); err != nil {
 		return nil, err
@@ -224,6 +602,19 @@ func (c *Controller) ThemeListReorder(ctx context.Context, req *protocol.ThemeLi
 }
 
 func (c *Controller) ThemeSet(ctx context.Context, req *protocol.ThemeSetRequest) (*protocol.ThemeSetResponse, error) {
+	if hasColor(req.Groups) {
+		color, err := c.SupportsColor(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !color {
+			colorless := &protocol.ThemeSetRequest{ThemeIndex: req.ThemeIndex, Groups: make([]protocol.ThemeGroup, len(req.Groups))}
+			for i, g := range req.Groups {
+				colorless.Groups[i] = protocol.ThemeGroup{GroupNumber: g.GroupNumber, Intensity: g.Intensity}
+			}
+			req = colorless
+		}
+	}
 	resp := &protocol.ThemeSetResponse{}
 	if err := c.request(ctx, "ThemeSet", req, resp); err != nil {
 		return nil, err
@@ -231,5 +622,5 @@ func (c *Controller) ThemeSet(ctx context.Context, req *protocol.ThemeSetRequest
 	return resp, protocol.ErrorForStatus(resp.Status)
 }
 
-// Ensure *Controller implements protocol.Controller.
-var _ protocol.Controller = (*Controller)(nil)
+// Ensure *Controller implements protocol.ColorController.
+var _ protocol.ColorController = (*Controller)(nil)
@@ -2,8 +2,14 @@ package client_test
 
 import (
 	"context"
+	"errors"
 	"github.com/scottlamb/luxor/client"
+	"github.com/scottlamb/luxor/emulator"
 	"github.com/scottlamb/luxor/protocol"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -108,6 +114,25 @@ func TestHttpTimeoutGt0(t *testing.T) {
 	}
 }
 
+func TestControllerTimeout(t *testing.T) {
+	requestDone := make(chan struct{}, 1) // closed when request finishes.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-requestDone:
+		case <-time.After(time.Minute):
+			t.Error("took way too long to finish")
+		}
+	}))
+	defer server.Close()
+	c := &client.Controller{BaseURL: server.URL, Timeout: 50 * time.Millisecond}
+	req := &protocol.ThemeGetRequest{ThemeIndex: 0}
+	_, err := c.ThemeGet(context.Background(), req)
+	close(requestDone)
+	if client.ErrorClass(err) != client.ClassTimeout {
+		t.Errorf("expected timeout; got %v", err)
+	}
+}
+
 func TestCanceledWhileWaiting(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	requestDone := make(chan struct{}, 1) // closed when request finishes.
@@ -215,3 +240,300 @@ func TestBadApplicationStatus(t *testing.T) {
 		t.Errorf("Error should describe status; %v", err)
 	}
 }
+
+type observation struct {
+	method string
+	status int
+	err    error
+}
+
+type recordingObserver struct {
+	observations []observation
+}
+
+func (o *recordingObserver) ObserveRPC(method string, elapsed time.Duration, status int, err error) {
+	o.observations = append(o.observations, observation{method, status, err})
+}
+
+func TestObserver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/ThemeGet.json" {
+			io.WriteString(w, "{\"Status\":243}")
+		} else {
+			io.WriteString(w, "{\"Status\":0,\"Controller\":\"luxor\"}")
+		}
+	}))
+	defer server.Close()
+	o := &recordingObserver{}
+	c := &client.Controller{BaseURL: server.URL, Observer: o}
+	if _, err := c.ControllerName(context.Background(), &protocol.ControllerNameRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := c.ThemeGet(context.Background(), &protocol.ThemeGetRequest{ThemeIndex: 30})
+	if protocol.StatusOf(err) != protocol.StatusThemeIndexOutOfRange || resp == nil {
+		t.Errorf("expected theme index out of range with response; got %+v, %v", resp, err)
+	}
+	if len(o.observations) != 2 {
+		t.Fatalf("expected 2 observations; got %+v", o.observations)
+	}
+	if got := o.observations[0]; got.method != "ControllerName" || got.status != 0 || got.err != nil {
+		t.Errorf("unexpected ControllerName observation %+v", got)
+	}
+	if got := o.observations[1]; got.method != "ThemeGet" || got.status != protocol.StatusThemeIndexOutOfRange ||
+		client.ErrorClass(got.err) != client.ClassStatus {
+		t.Errorf("unexpected ThemeGet observation %+v", got)
+	}
+}
+
+func TestErrorClass(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ThemeGet.json":
+			http.Error(w, "Horrible problem", http.StatusInternalServerError)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			io.WriteString(w, "asdf")
+		}
+	}))
+	defer server.Close()
+	c := &client.Controller{BaseURL: server.URL}
+	ctx := context.Background()
+	if _, err := c.ThemeGet(ctx, &protocol.ThemeGetRequest{}); client.ErrorClass(err) != client.ClassHTTP {
+		t.Errorf("expected http class; got %v", err)
+	}
+	if _, err := c.GroupListGet(ctx, &protocol.GroupListGetRequest{}); client.ErrorClass(err) != client.ClassMalformed {
+		t.Errorf("expected malformed class; got %v", err)
+	}
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := c.GroupListGet(canceled, &protocol.GroupListGetRequest{}); client.ErrorClass(err) != client.ClassCanceled {
+		t.Errorf("expected canceled class; got %v", err)
+	}
+	server.Close()
+	if _, err := c.GroupListGet(ctx, &protocol.GroupListGetRequest{}); client.ErrorClass(err) != client.ClassConnect {
+		t.Errorf("expected connect class; got %v", err)
+	}
+}
+
+func TestTracing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, "{\"Status\":201}")
+	}))
+	defer server.Close()
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	c := &client.Controller{BaseURL: server.URL, TracerProvider: tp}
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	_, err := c.IlluminateGroup(ctx, &protocol.IlluminateGroupRequest{GroupNumber: 3, Intensity: 50})
+	parent.End()
+	if protocol.StatusOf(err) != protocol.StatusPreconditionFailed {
+		t.Fatalf("expected precondition failed; got %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	byName := make(map[string]tracetest.SpanStub)
+	for _, s := range spans {
+		byName[s.Name] = s
+	}
+	rpc, ok := byName["luxor.IlluminateGroup"]
+	if !ok {
+		t.Fatalf("no RPC span in %+v", spans)
+	}
+	if rpc.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Error("RPC span is not a child of the caller's span")
+	}
+	if rpc.Status.Code != codes.Error {
+		t.Errorf("expected error status; got %+v", rpc.Status)
+	}
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, a := range rpc.Attributes {
+		attrs[a.Key] = a.Value
+	}
+	for k, want := range map[attribute.Key]attribute.Value{
+		"rpc.method":                attribute.StringValue("IlluminateGroup"),
+		"luxor.group_number":        attribute.Int64Value(3),
+		"luxor.intensity":           attribute.Int64Value(50),
+		"luxor.status":              attribute.IntValue(protocol.StatusPreconditionFailed),
+		"error.type":                attribute.StringValue(client.ClassStatus),
+		"http.response.status_code": attribute.IntValue(http.StatusOK),
+	} {
+		if got := attrs[k]; got != want {
+			t.Errorf("attribute %s = %v; want %v", k, got.Emit(), want.Emit())
+		}
+	}
+	if _, ok := attrs["luxor.theme_index"]; ok {
+		t.Error("unexpected theme_index attribute on IlluminateGroup")
+	}
+	for _, name := range []string{"http.get_conn", "http.connect", "http.wait_response", "http.read_body"} {
+		s, ok := byName[name]
+		if !ok {
+			t.Errorf("no %s span", name)
+			continue
+		}
+		if s.Parent.SpanID() != rpc.SpanContext.SpanID() {
+			t.Errorf("%s span is not a child of the RPC span", name)
+		}
+	}
+}
+
+// fixedResolver resolves every name to one URL.
+type fixedResolver struct {
+	url   string
+	calls int
+}
+
+func (r *fixedResolver) Resolve(ctx context.Context, name string) (string, error) {
+	r.calls++
+	return r.url, nil
+}
+
+func TestWrongController(t *testing.T) {
+	server := httptest.NewServer(&emulator.Handler{Controller: emulator.New("Neighbor")})
+	defer server.Close()
+	c := &client.Controller{BaseURL: server.URL, Name: "Yard"}
+	_, err := c.GroupListGet(context.Background(), &protocol.GroupListGetRequest{})
+	var wrong *client.WrongControllerError
+	if !errors.As(err, &wrong) || wrong.Got != "Neighbor" || wrong.Want != "Yard" {
+		t.Errorf("got %v; want WrongControllerError", err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	ctx := context.Background()
+	yard := emulator.New("Yard")
+	old := httptest.NewServer(&emulator.Handler{Controller: yard})
+	moved := httptest.NewServer(&emulator.Handler{Controller: yard})
+	defer moved.Close()
+	neighbor := httptest.NewServer(&emulator.Handler{Controller: emulator.New("Neighbor")})
+	defer neighbor.Close()
+
+	resolver := &fixedResolver{url: neighbor.URL + "/"}
+	c := &client.Controller{BaseURL: old.URL, Name: "Yard", Resolver: resolver, ResolveAfter: 2}
+	if _, err := c.GroupListGet(ctx, &protocol.GroupListGetRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	old.Close()
+
+	// The first failure doesn't prompt a search.
+	if _, err := c.GroupListGet(ctx, &protocol.GroupListGetRequest{}); client.ErrorClass(err) != client.ClassConnect || resolver.calls != 0 {
+		t.Fatalf("first failure: %v, %d resolves", err, resolver.calls)
+	}
+
+	// The second does, but the client won't switch to a different controller.
+	if _, err := c.GroupListGet(ctx, &protocol.GroupListGetRequest{}); err == nil || resolver.calls != 1 || c.Endpoint() != old.URL {
+		t.Fatalf("second failure: %v, %d resolves, endpoint %s", err, resolver.calls, c.Endpoint())
+	}
+
+	// Another search waits for MinResolveInterval.
+	if _, err := c.GroupListGet(ctx, &protocol.GroupListGetRequest{}); err == nil || resolver.calls != 1 {
+		t.Fatalf("third failure: %v, %d resolves", err, resolver.calls)
+	}
+
+	// Once found, the failed request is retried at the new address.
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	c = &client.Controller{BaseURL: old.URL, Name: "Yard", Resolver: resolver, ResolveAfter: 1, TracerProvider: tp}
+	resolver.url = moved.URL
+	if _, err := c.GroupListGet(ctx, &protocol.GroupListGetRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if c.Endpoint() != moved.URL {
+		t.Errorf("endpoint is %s; want %s", c.Endpoint(), moved.URL)
+	}
+
+	// Spans name the address each attempt went to.
+	var got []string
+	for _, s := range exporter.GetSpans() {
+		for _, a := range s.Attributes {
+			if a.Key == "server.address" && strings.HasPrefix(s.Name, "luxor.") {
+				got = append(got, s.Name+" http://"+a.Value.AsString())
+			}
+		}
+	}
+	want := []string{
+		"luxor.ControllerName " + old.URL,
+		"luxor.ControllerName " + moved.URL,
+		"luxor.GroupListGet " + moved.URL,
+	}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("spans went to %v; want %v", got, want)
+	}
+}
+
+func TestColor(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(&emulator.Handler{Controller: emulator.NewZDC("Home")})
+	defer server.Close()
+	c := &client.Controller{BaseURL: server.URL}
+	if _, err := c.ColorListSet(ctx, &protocol.ColorListSetRequest{ColorNumber: 3, Hue: 120, Saturation: 100}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ColorListSet(ctx, &protocol.ColorListSetRequest{ColorNumber: 4, Hue: 400}); protocol.StatusOf(err) != protocol.StatusInvalidRequest {
+		t.Errorf("ColorListSet with bad hue: %v", err)
+	}
+	colors, err := c.ColorListGet(ctx, &protocol.ColorListGetRequest{})
+	if err != nil || len(colors.ColorList) != 1 || colors.ColorList[0] != (protocol.Color{ColorNumber: 3, Hue: 120, Saturation: 100}) {
+		t.Errorf("ColorListGet = %+v, %v", colors, err)
+	}
+	if _, err := c.GroupListAdd(ctx, &protocol.GroupListAddRequest{GroupNumber: 1, Name: "Trees", Type: protocol.GroupTypeColor}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.IlluminateGroup(ctx, &protocol.IlluminateGroupRequest{GroupNumber: 1, Intensity: 60, Color: 3}); err != nil {
+		t.Fatal(err)
+	}
+	groups, err := c.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (protocol.Group{GroupNumber: 1, Intensity: 60, Name: "Trees", Type: protocol.GroupTypeColor, Color: 3}); len(groups.GroupList) != 1 || groups.GroupList[0] != want {
+		t.Errorf("GroupListGet = %+v; want %+v", groups.GroupList, want)
+	}
+}
+
+// TestColorZD checks that colors are dropped rather than failing against a
+// controller without color support.
+func TestColorZD(t *testing.T) {
+	ctx := context.Background()
+	var paths []string
+	handler := &emulator.Handler{Controller: emulator.New("Home")}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "Color") || strings.Contains(string(body), "Type") {
+			t.Errorf("%s sent with colors: %s", r.URL.Path, body)
+		}
+		r.Body = io.NopCloser(strings.NewReader(string(body)))
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	c := &client.Controller{BaseURL: server.URL}
+	if _, err := c.ColorListGet(ctx, &protocol.ColorListGetRequest{}); protocol.StatusOf(err) != protocol.StatusUnknownMethod {
+		t.Errorf("ColorListGet = %v", err)
+	}
+	if color, err := c.SupportsColor(ctx); color || err != nil {
+		t.Errorf("SupportsColor = %v, %v", color, err)
+	}
+	if _, err := c.GroupListAdd(ctx, &protocol.GroupListAddRequest{GroupNumber: 1, Name: "Trees", Type: protocol.GroupTypeColor}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.IlluminateGroup(ctx, &protocol.IlluminateGroupRequest{GroupNumber: 1, Intensity: 60, Color: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"/ColorListGet.json", "/GroupListAdd.json", "/IlluminateGroup.json"}; strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Errorf("requests = %q; want %q", paths, want)
+	}
+
+	// A fresh client asks once before its first color request.
+	paths = nil
+	c = &client.Controller{BaseURL: server.URL}
+	for i := 0; i < 2; i++ {
+		if _, err := c.IlluminateGroup(ctx, &protocol.IlluminateGroupRequest{GroupNumber: 1, Intensity: 60, Color: 3}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := []string{"/ColorListGet.json", "/IlluminateGroup.json", "/IlluminateGroup.json"}; strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Errorf("requests = %q; want %q", paths, want)
+	}
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"github.com/scottlamb/luxor/protocol"
+)
+
+// Values of Controller.color.
+const (
+	colorUnknown = iota
+	colorSupported
+	colorUnsupported
+)
+
+// SupportsColor reports whether the controller supports colors, as a ZDC
+// does, asking it with ColorListGet if it hasn't answered a color method yet.
+// IlluminateGroup, ThemeSet, and GroupListAdd call it before sending colors
+// or color group types, and leave them out for a controller which doesn't.
+func (c *Controller) SupportsColor(ctx context.Context) (bool, error) {
+	c.colorMu.Lock()
+	known := c.color
+	c.colorMu.Unlock()
+	if known != colorUnknown {
+		return known == colorSupported, nil
+	}
+	_, err := c.ColorListGet(ctx, &protocol.ColorListGetRequest{})
+	switch status := protocol.StatusOf(err); {
+	case status == protocol.StatusUnknownMethod:
+		return false, nil
+	case status < 0:
+		return false, err
+	}
+	return true, nil
+}
+
+// noteColor records whether the controller knew a color method, from the
+// status of its response.
+func (c *Controller) noteColor(status int) {
+	c.colorMu.Lock()
+	defer c.colorMu.Unlock()
+	if status == protocol.StatusUnknownMethod {
+		c.color = colorUnsupported
+	} else {
+		c.color = colorSupported
+	}
+}
+
+// forgetColor clears what's known of the controller's color support. c.mu
+// may be held.
+func (c *Controller) forgetColor() {
+	c.colorMu.Lock()
+	defer c.colorMu.Unlock()
+	c.color = colorUnknown
+}
+
+func hasColor(groups []protocol.ThemeGroup) bool {
+	for _, g := range groups {
+		if g.Color != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Controller) ColorListGet(ctx context.Context, req *protocol.ColorListGetRequest) (*protocol.ColorListGetResponse, error) {
+	resp := &protocol.ColorListGetResponse{}
+	if err := c.request(ctx, "ColorListGet", req, resp); err != nil {
+		return nil, err
+	}
+	c.noteColor(resp.Status)
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) ColorListSet(ctx context.Context, req *protocol.ColorListSetRequest) (*protocol.ColorListSetResponse, error) {
+	resp := &protocol.ColorListSetResponse{}
+	if err := c.request(ctx, "ColorListSet", req, resp); err != nil {
+		return nil, err
+	}
+	c.noteColor(resp.Status)
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
@@ -0,0 +1,215 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"github.com/scottlamb/luxor/protocol"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"net/http/httptrace"
+	"net/url"
+	"reflect"
+	"sync"
+)
+
+const tracerName = "github.com/scottlamb/luxor/client"
+
+// requestAttributes maps request fields to span attributes. Only fields
+// present in a given request are recorded.
+var requestAttributes = []struct {
+	field string
+	key   attribute.Key
+}{
+	{"GroupNumber", "luxor.group_number"},
+	{"ThemeIndex", "luxor.theme_index"},
+	{"Intensity", "luxor.intensity"},
+	{"OnOff", "luxor.on_off"},
+	{"Name", "luxor.name"},
+	{"OldName", "luxor.old_name"},
+	{"NewName", "luxor.new_name"},
+}
+
+func (c *Controller) tracer() trace.Tracer {
+	tp := c.TracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return tp.Tracer(tracerName)
+}
+
+// startSpan starts the span for one RPC to baseURL, as a child of any span in
+// ctx.
+func (c *Controller) startSpan(ctx context.Context, baseURL, method string, request interface{}) (context.Context, trace.Span) {
+	ctx, span := c.tracer().Start(ctx, "luxor."+method, trace.WithSpanKind(trace.SpanKindClient))
+	if !span.IsRecording() {
+		return ctx, span
+	}
+	attrs := []attribute.KeyValue{
+		attribute.String("rpc.system", "luxor"),
+		attribute.String("rpc.method", method),
+	}
+	if u, err := url.Parse(baseURL); err == nil && u.Host != "" {
+		attrs = append(attrs, attribute.String("server.address", u.Host))
+	}
+	if v := reflect.ValueOf(request); v.Kind() == reflect.Ptr && v.Elem().Kind() == reflect.Struct {
+		v = v.Elem()
+		for _, a := range requestAttributes {
+			switch f := v.FieldByName(a.field); f.Kind() {
+			case reflect.Uint8, reflect.Int:
+				attrs = append(attrs, a.key.Int64(int64(f.Convert(reflect.TypeOf(int64(0))).Int())))
+			case reflect.String:
+				attrs = append(attrs, a.key.String(f.String()))
+			}
+		}
+	}
+	span.SetAttributes(attrs...)
+	return ctx, span
+}
+
+// endSpan records the RPC's outcome on span and ends it.
+func endSpan(span trace.Span, status int, err error) {
+	if status >= 0 {
+		span.SetAttributes(attribute.Int("luxor.status", status))
+	}
+	if err != nil {
+		span.SetAttributes(attribute.String("error.type", ErrorClass(err)))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// httpTimer creates sub-spans for the phases of an HTTP request: waiting for
+// a connection (including any pool queueing), DNS, TCP connect, TLS, and
+// waiting for the controller to respond. Callbacks may come from other
+// goroutines.
+type httpTimer struct {
+	ctx    context.Context
+	tracer trace.Tracer
+
+	mu       sync.Mutex
+	getConn  trace.Span
+	dns      trace.Span
+	connects map[string]trace.Span
+	tls      trace.Span
+	wait     trace.Span
+}
+
+func (t *httpTimer) start(name string) trace.Span {
+	_, span := t.tracer.Start(t.ctx, name)
+	return span
+}
+
+func endWithError(span trace.Span, err error) {
+	if span == nil {
+		return
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// finish ends any sub-spans still open, as when the request fails or is
+// canceled partway.
+func (t *httpTimer) finish(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, span := range []trace.Span{t.getConn, t.dns, t.tls, t.wait} {
+		endWithError(span, err)
+	}
+	for _, span := range t.connects {
+		endWithError(span, err)
+	}
+	t.getConn, t.dns, t.tls, t.wait = nil, nil, nil, nil
+	t.connects = nil
+}
+
+// withHTTPTimer returns ctx with an httptrace.ClientTrace which creates
+// sub-spans of the span in ctx. The caller must call finish on the returned
+// timer when the request is done.
+func withHTTPTimer(ctx context.Context, tracer trace.Tracer) (context.Context, *httpTimer) {
+	t := &httpTimer{ctx: ctx, tracer: tracer, connects: make(map[string]trace.Span)}
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GetConn: func(hostPort string) {
+			t.mu.Lock()
+			t.getConn = t.start("http.get_conn")
+			t.mu.Unlock()
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if t.getConn != nil {
+				t.getConn.SetAttributes(attribute.Bool("http.conn_reused", info.Reused))
+				t.getConn.End()
+				t.getConn = nil
+			}
+		},
+		DNSStart: func(info httptrace.DNSStartInfo) {
+			t.mu.Lock()
+			t.dns = t.start("http.dns")
+			t.dns.SetAttributes(attribute.String("server.address", info.Host))
+			t.mu.Unlock()
+		},
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			endWithError(t.dns, info.Err)
+			t.dns = nil
+			t.mu.Unlock()
+		},
+		ConnectStart: func(network, addr string) {
+			t.mu.Lock()
+			if t.connects == nil {
+				t.mu.Unlock()
+				return // a dial outliving the request.
+			}
+			span := t.start("http.connect")
+			span.SetAttributes(attribute.String("network.peer.address", addr))
+			t.connects[network+" "+addr] = span
+			t.mu.Unlock()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			t.mu.Lock()
+			endWithError(t.connects[network+" "+addr], err)
+			delete(t.connects, network+" "+addr)
+			t.mu.Unlock()
+		},
+		TLSHandshakeStart: func() {
+			t.mu.Lock()
+			t.tls = t.start("http.tls")
+			t.mu.Unlock()
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			t.mu.Lock()
+			endWithError(t.tls, err)
+			t.tls = nil
+			t.mu.Unlock()
+		},
+		WroteRequest: func(info httptrace.WroteRequestInfo) {
+			t.mu.Lock()
+			if info.Err == nil {
+				t.wait = t.start("http.wait_response")
+			}
+			t.mu.Unlock()
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			endWithError(t.wait, nil)
+			t.wait = nil
+			t.mu.Unlock()
+		},
+	}), t
+}
+
+// outcome returns the status and error to record for a finished request,
+// counting a non-zero Status as an error.
+func outcome(response interface{}, err error) (int, error) {
+	if err != nil {
+		return -1, err
+	}
+	status := statusOf(response)
+	return status, protocol.ErrorForStatus(status)
+}
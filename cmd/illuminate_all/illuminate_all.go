@@ -5,22 +5,30 @@ package main
 
 import (
 	"context"
-	"github.com/scottlamb/luxor/client"
+	"flag"
+	"github.com/scottlamb/luxor/config"
 	"github.com/scottlamb/luxor/protocol"
+	"log"
 )
 
+var selectProfile = config.RegisterFlags(flag.CommandLine)
+
 func main() {
-	client := &client.Controller{BaseURL: "http://luxor/"}
+	flag.Parse()
+	profile, err := selectProfile()
+	if err != nil {
+		log.Fatal(err)
+	}
+	client := profile.Client()
 	ctx := context.Background()
 	themes, err := client.ThemeListGet(ctx, &protocol.ThemeListGetRequest{})
 	if err != nil {
-		panic(err)
+		log.Fatal(err)
 	}
 	for _, theme := range themes.ThemeList {
 		request := &protocol.IlluminateThemeRequest{ThemeIndex: theme.ThemeIndex, OnOff: 1}
-		_, err := client.IlluminateTheme(context.Background(), request)
-		if err != nil {
-			panic(err)
+		if _, err := client.IlluminateTheme(ctx, request); err != nil {
+			log.Fatalf("theme %s (%s): %v", protocol.ThemeLetter(theme.ThemeIndex), theme.Name, err)
 		}
 	}
 }
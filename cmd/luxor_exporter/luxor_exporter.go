@@ -0,0 +1,33 @@
+// luxor_exporter serves Prometheus metrics for a controller at /metrics.
+
+package main
+
+import (
+	"flag"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/scottlamb/luxor/config"
+	"github.com/scottlamb/luxor/exporter"
+	"log"
+	"net/http"
+)
+
+var selectProfile = config.RegisterFlags(flag.CommandLine)
+var listen = flag.String("listen", ":9742", "Address on which to serve metrics")
+var timeout = flag.Duration("timeout", exporter.DefaultTimeout, "Bound on each scrape's controller RPCs")
+
+func main() {
+	flag.Parse()
+	profile, err := selectProfile()
+	if err != nil {
+		log.Fatal(err)
+	}
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	c := profile.Client()
+	c.Observer = exporter.NewRPCMetrics(reg)
+	reg.MustRegister(exporter.NewCollector(c, *timeout))
+	http.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	log.Fatal(http.ListenAndServe(*listen, nil))
+}
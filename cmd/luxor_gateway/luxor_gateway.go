@@ -0,0 +1,45 @@
+// luxor_gateway serves a resource-oriented REST API for a controller. Its
+// OpenAPI description is at /openapi.json, or printed with --openapi. A live
+// stream of state changes is served as Server-Sent Events at /events.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"github.com/scottlamb/luxor/config"
+	"github.com/scottlamb/luxor/live"
+	"github.com/scottlamb/luxor/rest"
+	"log"
+	"net/http"
+	"os"
+)
+
+var selectProfile = config.RegisterFlags(flag.CommandLine)
+var listen = flag.String("listen", ":8080", "Address on which to serve the REST API")
+var printOpenAPI = flag.Bool("openapi", false, "Print the OpenAPI document and exit")
+var pollInterval = flag.Duration("poll_interval", live.DefaultPollInterval, "How often to poll for changes made elsewhere")
+
+func main() {
+	flag.Parse()
+	profile, err := selectProfile()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *printOpenAPI {
+		formatted, err := json.MarshalIndent(rest.OpenAPI(), "", "  ")
+		if err != nil {
+			log.Fatal(err)
+		}
+		os.Stdout.Write(append(formatted, '\n'))
+		return
+	}
+	tracker := live.NewTracker(profile.Client(), 0)
+	tracker.PollInterval = *pollInterval
+	go tracker.Run(context.Background())
+	mux := http.NewServeMux()
+	mux.Handle("GET /events", tracker)
+	mux.Handle("/", rest.New(tracker))
+	log.Fatal(http.ListenAndServe(*listen, mux))
+}
@@ -0,0 +1,34 @@
+// luxor_grpc serves a controller over gRPC, using the service defined in
+// luxorpb/luxor.proto. Clients can use luxorgrpc.NewController to get a
+// protocol.Controller backed by it.
+
+package main
+
+import (
+	"flag"
+	"github.com/scottlamb/luxor/config"
+	"github.com/scottlamb/luxor/luxorgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	"log"
+	"net"
+)
+
+var selectProfile = config.RegisterFlags(flag.CommandLine)
+var listen = flag.String("listen", ":50051", "Address on which to serve gRPC")
+
+func main() {
+	flag.Parse()
+	profile, err := selectProfile()
+	if err != nil {
+		log.Fatal(err)
+	}
+	lis, err := net.Listen("tcp", *listen)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s := grpc.NewServer()
+	luxorgrpc.Register(s, profile.Client())
+	reflection.Register(s)
+	log.Fatal(s.Serve(lis))
+}
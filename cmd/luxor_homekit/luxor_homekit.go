@@ -0,0 +1,44 @@
+// luxor_homekit bridges a controller to Apple HomeKit, so groups and themes
+// can be controlled from the Home app and Siri. Add it in the Home app with
+// the setup code given by --pin (001-02-003 by default).
+
+package main
+
+import (
+	"context"
+	"flag"
+	"github.com/scottlamb/luxor/config"
+	"github.com/scottlamb/luxor/homekit"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+var selectProfile = config.RegisterFlags(flag.CommandLine)
+var storePath = flag.String("store", "luxor_homekit", "Directory for the bridge's keys and pairings")
+var pin = flag.String("pin", homekit.DefaultPin, "Eight-digit HomeKit setup code")
+var addr = flag.String("listen", "", "Address on which to serve HAP; defaults to a random port")
+var name = flag.String("name", "", "Bridge name; defaults to the controller name")
+var pollInterval = flag.Duration("poll_interval", homekit.DefaultPollInterval, "How often to poll the controller")
+
+func main() {
+	flag.Parse()
+	profile, err := selectProfile()
+	if err != nil {
+		log.Fatal(err)
+	}
+	b := &homekit.Bridge{
+		Controller:   profile.Client(),
+		StorePath:    *storePath,
+		Pin:          *pin,
+		Addr:         *addr,
+		Name:         *name,
+		PollInterval: *pollInterval,
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := b.Run(ctx); err != nil && err != context.Canceled {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,113 @@
+// luxor_hue emulates a Philips Hue bridge for a controller, so universal
+// remotes and voice assistants which support Hue can control its groups and
+// themes. Most such clients only look for bridges on port 80.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/scottlamb/luxor/config"
+	"github.com/scottlamb/luxor/hue"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+var selectProfile = config.RegisterFlags(flag.CommandLine)
+var listen = flag.String("listen", ":80", "Address on which to serve the Hue API")
+var advertise = flag.String("advertise", "", "URL to advertise through SSDP; defaults to one built from this host's LAN address and --listen's port")
+var ifaceName = flag.String("interface", "", "Interface for SSDP; defaults to the system's choice")
+var serial = flag.String("serial", "", "Twelve hex digit serial number; defaults to the interface's MAC address")
+var name = flag.String("name", "Luxor", "Bridge name")
+
+// lanAddr returns the address this host uses to reach the LAN. Nothing is
+// sent; connecting a UDP socket just picks a route.
+func lanAddr() (net.IP, error) {
+	conn, err := net.Dial("udp4", hue.SSDPAddr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil
+}
+
+// macFor returns the hardware address of ifi or, if nil, of the first
+// interface which has one.
+func macFor(ifi *net.Interface) (string, error) {
+	ifis := []net.Interface{}
+	if ifi != nil {
+		ifis = append(ifis, *ifi)
+	} else {
+		all, err := net.Interfaces()
+		if err != nil {
+			return "", err
+		}
+		ifis = all
+	}
+	for _, i := range ifis {
+		if len(i.HardwareAddr) == 6 && i.Flags&net.FlagLoopback == 0 {
+			return strings.ReplaceAll(i.HardwareAddr.String(), ":", ""), nil
+		}
+	}
+	return "", fmt.Errorf("no interface with a MAC address; use --serial")
+}
+
+func main() {
+	flag.Parse()
+	profile, err := selectProfile()
+	if err != nil {
+		log.Fatal(err)
+	}
+	var ifi *net.Interface
+	if *ifaceName != "" {
+		var err error
+		if ifi, err = net.InterfaceByName(*ifaceName); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *serial == "" {
+		var err error
+		if *serial, err = macFor(ifi); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *advertise == "" {
+		ip, err := lanAddr()
+		if err != nil {
+			log.Fatal(err)
+		}
+		_, port, err := net.SplitHostPort(*listen)
+		if err != nil {
+			log.Fatal(err)
+		}
+		*advertise = "http://" + net.JoinHostPort(ip.String(), port) + "/"
+	}
+	b := &hue.Bridge{
+		Controller:   profile.Client(),
+		Name:         *name,
+		URLBase:      *advertise,
+		SerialNumber: *serial,
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		if err := b.ListenSSDP(ctx, ifi); err != nil && err != context.Canceled {
+			log.Fatal(err)
+		}
+	}()
+	log.Printf("serving Hue bridge %s at %s", b.BridgeID(), *advertise)
+	server := &http.Server{Addr: *listen, Handler: b}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,52 @@
+// luxor_mqtt bridges a controller to an MQTT broker, announcing its groups,
+// themes and an "all off" button through Home Assistant discovery.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"github.com/scottlamb/luxor/config"
+	"github.com/scottlamb/luxor/mqttbridge"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+var selectProfile = config.RegisterFlags(flag.CommandLine)
+var broker = flag.String("broker", "tcp://localhost:1883", "MQTT broker URL")
+var clientID = flag.String("client_id", "", "MQTT client ID; defaults to one derived from the node ID")
+var username = flag.String("username", "", "MQTT username")
+var password = flag.String("password", "", "MQTT password; defaults to $MQTT_PASSWORD")
+var nodeID = flag.String("node_id", "", "Node ID for topics and entity IDs; defaults to one derived from the controller name")
+var topicPrefix = flag.String("topic_prefix", mqttbridge.DefaultTopicPrefix, "Prefix for state and command topics")
+var discoveryPrefix = flag.String("discovery_prefix", mqttbridge.DefaultDiscoveryPrefix, "Home Assistant discovery prefix")
+var pollInterval = flag.Duration("poll_interval", mqttbridge.DefaultPollInterval, "How often to poll the controller")
+
+func main() {
+	flag.Parse()
+	profile, err := selectProfile()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *password == "" {
+		*password = os.Getenv("MQTT_PASSWORD")
+	}
+	b := &mqttbridge.Bridge{
+		Controller:      profile.Client(),
+		Broker:          *broker,
+		ClientID:        *clientID,
+		Username:        *username,
+		Password:        *password,
+		NodeID:          *nodeID,
+		TopicPrefix:     *topicPrefix,
+		DiscoveryPrefix: *discoveryPrefix,
+		PollInterval:    *pollInterval,
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := b.Run(ctx); err != nil && err != context.Canceled {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,96 @@
+// luxor_notify watches a controller and sends notifications when it becomes
+// unreachable, recovers, or has a group renamed. It only polls, so it can't
+// see theme failures or FlashLights left on, and rejects those rules;
+// programs which drive the controller (such as schedulers) can wrap their
+// client in a notify.Monitor to catch them.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"github.com/scottlamb/luxor/config"
+	"github.com/scottlamb/luxor/notify"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+)
+
+var selectProfile = config.RegisterFlags(flag.CommandLine)
+var webhookURL = flag.String("webhook_url", "", "URL to POST notifications to")
+var webhookTemplate = flag.String("webhook_template", "", "File with a template for webhook bodies; defaults to the event as JSON")
+var smtpAddr = flag.String("smtp_addr", "", "SMTP server host:port for email notifications")
+var smtpUsername = flag.String("smtp_username", "", "SMTP username; the password is taken from $SMTP_PASSWORD")
+var smtpFrom = flag.String("smtp_from", "", "Email sender address")
+var smtpTo = flag.String("smtp_to", "", "Comma-separated email recipients")
+var execCommand = flag.String("exec", "", "Shell command to run for each notification")
+var rules = flag.String("rules", "", "Comma-separated rules to evaluate, of "+strings.Join(notify.PolledRules, ", ")+"; defaults to all")
+var pollInterval = flag.Duration("poll_interval", notify.DefaultPollInterval, "How often to poll the controller")
+var unreachableAfter = flag.Duration("unreachable_after", notify.DefaultUnreachableAfter, "How long the controller must fail before notifying")
+var dedupeWindow = flag.Duration("dedupe_window", notify.DefaultDedupeWindow, "How long to suppress repeats of the same notification")
+var rateLimit = flag.Int("rate_limit", notify.DefaultRateLimit, "Maximum notifications per rate_period")
+var ratePeriod = flag.Duration("rate_period", notify.DefaultRatePeriod, "Period for rate_limit")
+
+func main() {
+	flag.Parse()
+	profile, err := selectProfile()
+	if err != nil {
+		log.Fatal(err)
+	}
+	n := &notify.Notifier{DedupeWindow: *dedupeWindow, RateLimit: *rateLimit, RatePeriod: *ratePeriod}
+	if *webhookURL != "" {
+		s := &notify.WebhookSink{URL: *webhookURL}
+		if *webhookTemplate != "" {
+			text, err := os.ReadFile(*webhookTemplate)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if s.Template, err = notify.ParseWebhookTemplate(string(text)); err != nil {
+				log.Fatal(err)
+			}
+		}
+		n.Sinks = append(n.Sinks, s)
+	}
+	if *smtpAddr != "" {
+		s := &notify.SMTPSink{Addr: *smtpAddr, From: *smtpFrom, To: strings.Split(*smtpTo, ",")}
+		if *smtpUsername != "" {
+			host, _, err := net.SplitHostPort(*smtpAddr)
+			if err != nil {
+				log.Fatal(err)
+			}
+			s.Auth = smtp.PlainAuth("", *smtpUsername, os.Getenv("SMTP_PASSWORD"), host)
+		}
+		n.Sinks = append(n.Sinks, s)
+	}
+	if *execCommand != "" {
+		n.Sinks = append(n.Sinks, &notify.ExecSink{Command: []string{"/bin/sh", "-c", *execCommand}})
+	}
+	if len(n.Sinks) == 0 {
+		log.Fatal("no sinks; specify --webhook_url, --smtp_addr, or --exec")
+	}
+	m := &notify.Monitor{
+		Controller:       profile.Client(),
+		Notifier:         n,
+		PollInterval:     *pollInterval,
+		UnreachableAfter: *unreachableAfter,
+	}
+	m.Rules = notify.PolledRules
+	if *rules != "" {
+		m.Rules = strings.Split(*rules, ",")
+		for _, r := range m.Rules {
+			if !slices.Contains(notify.PolledRules, r) {
+				log.Fatalf("rule %q isn't available to luxor_notify; want one of %q", r, notify.PolledRules)
+			}
+		}
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := m.Run(ctx); err != nil && err != context.Canceled {
+		log.Fatal(err)
+	}
+}
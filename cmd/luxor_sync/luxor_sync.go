@@ -0,0 +1,108 @@
+// luxor_sync copies groups and themes from one controller to others, as for
+// identical rental units whose themes are edited on one controller. The
+// source is selected as usual with --profile or --base_url; targets are other
+// profiles in the configuration file. Where a target's wiring differs,
+// --group_map names a JSON file mapping each target profile's source group
+// numbers to its own:
+//
+//	{"unit2": {"1": 4, "4": 1}}
+//
+// With --watch, luxor_sync keeps running, propagating changes to the source
+// every --poll_interval.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/scottlamb/luxor/config"
+	"github.com/scottlamb/luxor/replicate"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+var selectProfile = config.RegisterFlags(flag.CommandLine)
+var targets = flag.String("targets", "", "Comma-separated profiles to copy to")
+var groupMap = flag.String("group_map", "", "JSON file mapping source group numbers to each target's")
+var prune = flag.Bool("prune", false, "Delete targets' groups and themes which the source lacks")
+var dryRun = flag.Bool("dry_run", false, "Print changes without making them")
+var watch = flag.Bool("watch", false, "Keep running, propagating changes to the source")
+var pollInterval = flag.Duration("poll_interval", replicate.DefaultInterval, "How often --watch checks the source")
+
+func main() {
+	flag.Parse()
+	if *targets == "" {
+		log.Fatal("no targets; specify --targets")
+	}
+	source, err := selectProfile()
+	if err != nil {
+		log.Fatal(err)
+	}
+	c, err := config.LoadDefault()
+	if err != nil {
+		log.Fatal(err)
+	}
+	maps := map[string]map[uint8]uint8{}
+	if *groupMap != "" {
+		f, err := os.Open(*groupMap)
+		if err != nil {
+			log.Fatal(err)
+		}
+		d := json.NewDecoder(f)
+		d.DisallowUnknownFields()
+		err = d.Decode(&maps)
+		f.Close()
+		if err != nil {
+			log.Fatalf("%s: %v", *groupMap, err)
+		}
+	}
+	s := &replicate.Syncer{Source: source.Client(), DryRun: *dryRun, Interval: *pollInterval}
+	for _, name := range strings.Split(*targets, ",") {
+		p, err := c.Profile(name)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if p.Name == source.Name {
+			log.Fatalf("target %q is the source", name)
+		}
+		s.Targets = append(s.Targets, replicate.Target{
+			Name:       name,
+			Controller: p.Client(),
+			Options:    replicate.Options{GroupMap: maps[name], Prune: *prune},
+		})
+		delete(maps, name)
+	}
+	for name := range maps {
+		log.Fatalf("%s: %q isn't a target", *groupMap, name)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if *watch {
+		if err := s.Run(ctx); err != nil && err != context.Canceled {
+			log.Fatal(err)
+		}
+		return
+	}
+	results, err := s.Sync(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	failed := false
+	for _, r := range results {
+		for _, change := range r.Changes {
+			fmt.Printf("%s: %s\n", r.Target, change.Description)
+		}
+		if r.Err != nil {
+			log.Printf("%s: %v", r.Target, r.Err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
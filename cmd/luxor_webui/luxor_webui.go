@@ -0,0 +1,54 @@
+// luxor_webui serves a browser control panel for a controller. With
+// --emulate, it fronts an in-memory emulator instead, for trying the UI out.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"github.com/scottlamb/luxor/config"
+	"github.com/scottlamb/luxor/emulator"
+	"github.com/scottlamb/luxor/protocol"
+	"github.com/scottlamb/luxor/webui"
+	"log"
+	"net/http"
+)
+
+var selectProfile = config.RegisterFlags(flag.CommandLine)
+var listen = flag.String("listen", ":8080", "Address on which to serve the UI")
+var emulate = flag.Bool("emulate", false, "Serve an emulated controller with sample groups and themes")
+
+// sampleController returns an emulator populated with a few groups and
+// themes.
+func sampleController() protocol.Controller {
+	ctx := context.Background()
+	e := emulator.New("Luxor Emulator")
+	for i, name := range []string{"Front Path", "Patio", "Pool", "Trees"} {
+		e.GroupListAdd(ctx, &protocol.GroupListAddRequest{GroupNumber: uint8(i + 1), Name: name})
+	}
+	e.ThemeListAdd(ctx, &protocol.ThemeListAddRequest{ThemeIndex: 0, Name: "Evening"})
+	e.ThemeSet(ctx, &protocol.ThemeSetRequest{ThemeIndex: 0, Groups: []protocol.ThemeGroup{
+		{GroupNumber: 1, Intensity: 60},
+		{GroupNumber: 2, Intensity: 40},
+	}})
+	e.ThemeListAdd(ctx, &protocol.ThemeListAddRequest{ThemeIndex: 1, Name: "Party"})
+	e.ThemeSet(ctx, &protocol.ThemeSetRequest{ThemeIndex: 1, Groups: []protocol.ThemeGroup{
+		{GroupNumber: 2, Intensity: 100},
+		{GroupNumber: 3, Intensity: 100},
+		{GroupNumber: 4, Intensity: 80},
+	}})
+	return e
+}
+
+func main() {
+	flag.Parse()
+	profile, err := selectProfile()
+	if err != nil {
+		log.Fatal(err)
+	}
+	var controller protocol.Controller = profile.Client()
+	if *emulate {
+		controller = sampleController()
+	}
+	log.Fatal(http.ListenAndServe(*listen, webui.New(controller)))
+}
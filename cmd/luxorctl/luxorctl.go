@@ -0,0 +1,19 @@
+// luxorctl controls a controller from the command line. Run it without
+// arguments for a list of commands.
+
+package main
+
+import (
+	"context"
+	"github.com/scottlamb/luxor/ctl"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := ctl.Main(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
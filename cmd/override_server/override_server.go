@@ -0,0 +1,37 @@
+// override_server runs an override.Manager against a controller and serves
+// its JSON API, e.g.:
+//
+//     curl -d '{"GroupNumber":3,"Intensity":100,"Seconds":1800}' http://localhost:8080/OverrideSet.json
+//     curl -d '{}' http://localhost:8080/OverrideList.json
+//     curl -d '{"GroupNumber":3,"Restore":true}' http://localhost:8080/OverrideCancel.json
+
+package main
+
+import (
+	"context"
+	"flag"
+	"github.com/scottlamb/luxor/config"
+	"github.com/scottlamb/luxor/override"
+	"log"
+	"net/http"
+)
+
+var selectProfile = config.RegisterFlags(flag.CommandLine)
+var listen = flag.String("listen", ":8080", "Address on which to serve the override API")
+var stateFile = flag.String("state_file", "overrides.json", "File in which to persist overrides")
+var pollInterval = flag.Duration("poll_interval", override.DefaultPollInterval, "How often to check for external changes")
+
+func main() {
+	flag.Parse()
+	profile, err := selectProfile()
+	if err != nil {
+		log.Fatal(err)
+	}
+	m, err := override.NewManager(profile.Client(), *stateFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	m.PollInterval = *pollInterval
+	go m.Run(context.Background())
+	log.Fatal(http.ListenAndServe(*listen, m.Handler()))
+}
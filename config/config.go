@@ -0,0 +1,288 @@
+// Package config loads named controller profiles from a configuration file,
+// so that programs needn't be told each controller's URL every time.
+//
+// The file is JSON, at $LUXOR_CONFIG or, by default, luxor/config.json under
+// the user's configuration directory ($XDG_CONFIG_HOME, usually ~/.config):
+//
+//	{
+//	  "DefaultProfile": "home",
+//	  "Profiles": {
+//	    "home": {
+//	      "BaseURL": "http://luxor.home.example/",
+//	      "Timeout": "5s",
+//	      "GroupAliases": {"path": "Front Path"},
+//	      "ThemeAliases": {"party": "C"}
+//	    },
+//	    "rental": {
+//	      "BaseURL": "http://192.168.7.20/",
+//	      "ControllerName": "Rental",
+//	      "Subnets": ["192.168.7.0/24"]
+//	    }
+//	  }
+//	}
+//
+// A profile with a ControllerName follows the controller should its address
+// change; see client.Controller.Name.
+// The profile is chosen by name (as from a --profile flag), then by
+// $LUXOR_PROFILE, then by DefaultProfile, then as the only profile defined.
+// With no configuration at all, the profile for http://luxor/ is used.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/scottlamb/luxor/client"
+	"github.com/scottlamb/luxor/discovery"
+	"github.com/scottlamb/luxor/protocol"
+	"io/fs"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Environment variables.
+const (
+	EnvConfig  = "LUXOR_CONFIG"  // path to the configuration file.
+	EnvProfile = "LUXOR_PROFILE" // name of the profile to use.
+)
+
+// DefaultBaseURL is the controller's usual address on a home network.
+const DefaultBaseURL = "http://luxor/"
+
+// Config is the contents of a configuration file.
+type Config struct {
+	DefaultProfile string
+	Profiles       map[string]*Profile
+
+	// path is the file the Config was loaded from, for messages.
+	path string
+}
+
+// Profile describes one controller.
+type Profile struct {
+	// Name is the profile's key in Config.Profiles; it's empty for the
+	// implicit profile used without a configuration file.
+	Name string `json:"-"`
+
+	// BaseURL defaults to DefaultBaseURL.
+	BaseURL string
+
+	// Timeout, if non-zero, bounds each RPC.
+	Timeout Duration
+
+	// GroupAliases maps short names to group names or numbers.
+	GroupAliases map[string]string `json:",omitempty"`
+
+	// ThemeAliases maps short names to theme letters or names.
+	ThemeAliases map[string]string `json:",omitempty"`
+
+	// ControllerName, if set, identifies the controller, so that it's
+	// found again by scanning Subnets (or, by default, the local subnets)
+	// if it stops answering at BaseURL.
+	ControllerName string `json:",omitempty"`
+
+	// Subnets are CIDR prefixes such as "192.168.1.0/24".
+	Subnets []string `json:",omitempty"`
+}
+
+// Duration is a time.Duration written in JSON as a string such as "5s".
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"5s\": %s", b)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Path returns the configuration file's path: $LUXOR_CONFIG, or
+// luxor/config.json under the user's configuration directory. It returns ""
+// if neither can be determined.
+func Path() string {
+	if p := os.Getenv(EnvConfig); p != "" {
+		return p
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "luxor", "config.json")
+}
+
+// Load reads the configuration file at path. Unknown fields are errors, to
+// catch typos.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Config{path: path}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(c); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	for name, p := range c.Profiles {
+		if p == nil {
+			return nil, fmt.Errorf("%s: profile %q is null", path, name)
+		}
+		p.Name = name
+		for _, subnet := range p.Subnets {
+			if _, err := netip.ParsePrefix(subnet); err != nil {
+				return nil, fmt.Errorf("%s: profile %q: %v", path, name, err)
+			}
+		}
+	}
+	if c.DefaultProfile != "" && c.Profiles[c.DefaultProfile] == nil {
+		return nil, fmt.Errorf("%s: DefaultProfile %q isn't defined", path, c.DefaultProfile)
+	}
+	return c, nil
+}
+
+// LoadDefault loads the file at Path, returning an empty Config if it
+// doesn't exist.
+func LoadDefault() (*Config, error) {
+	path := Path()
+	if path == "" {
+		return &Config{}, nil
+	}
+	c, err := Load(path)
+	if errors.Is(err, fs.ErrNotExist) && os.Getenv(EnvConfig) == "" {
+		return &Config{path: path}, nil
+	}
+	return c, err
+}
+
+// Profile selects a profile by name. If name is empty, $LUXOR_PROFILE,
+// DefaultProfile, and the only profile defined are tried in turn, falling
+// back to an unnamed profile for DefaultBaseURL.
+func (c *Config) Profile(name string) (*Profile, error) {
+	if name == "" {
+		name = os.Getenv(EnvProfile)
+	}
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" {
+		if len(c.Profiles) == 1 {
+			for _, p := range c.Profiles {
+				return p, nil
+			}
+		}
+		return &Profile{}, nil
+	}
+	if p := c.Profiles[name]; p != nil {
+		return p, nil
+	}
+	names := make([]string, 0, len(c.Profiles))
+	for n := range c.Profiles {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	if c.path == "" {
+		return nil, fmt.Errorf("no profile %q; no configuration file", name)
+	}
+	return nil, fmt.Errorf("no profile %q in %s; have %q", name, c.path, names)
+}
+
+// Client returns a client for the profile's controller.
+func (p *Profile) Client() *client.Controller {
+	url := p.BaseURL
+	if url == "" {
+		url = DefaultBaseURL
+	}
+	c := &client.Controller{
+		BaseURL: strings.TrimSuffix(url, "/"),
+		Timeout: time.Duration(p.Timeout),
+		Name:    p.ControllerName,
+	}
+	if p.ControllerName != "" {
+		r := &discovery.Resolver{}
+		for _, subnet := range p.Subnets {
+			if prefix, err := netip.ParsePrefix(subnet); err == nil { // checked by Load.
+				r.Prefixes = append(r.Prefixes, prefix.Masked())
+			}
+		}
+		c.Resolver = r
+	}
+	return c
+}
+
+// Group returns the group name or number an alias stands for, or id itself
+// if it isn't an alias.
+func (p *Profile) Group(id string) string {
+	return resolve(p.GroupAliases, id)
+}
+
+// Theme returns the theme letter or name an alias stands for, or id itself
+// if it isn't an alias.
+func (p *Profile) Theme(id string) string {
+	return resolve(p.ThemeAliases, id)
+}
+
+// resolve looks up an alias, preferring an exact match.
+func resolve(aliases map[string]string, id string) string {
+	if v, ok := aliases[id]; ok {
+		return v
+	}
+	for k, v := range aliases {
+		if strings.EqualFold(k, id) {
+			return v
+		}
+	}
+	return id
+}
+
+// Controller returns a client for the named profile from the default
+// configuration file, as selected by Config.Profile.
+func Controller(profile string) (protocol.Controller, error) {
+	c, err := LoadDefault()
+	if err != nil {
+		return nil, err
+	}
+	p, err := c.Profile(profile)
+	if err != nil {
+		return nil, err
+	}
+	return p.Client(), nil
+}
+
+// RegisterFlags adds --profile and --base_url flags to flags. The returned
+// function, called after parsing, selects the profile from the default
+// configuration file; --base_url overrides its URL.
+func RegisterFlags(flags *flag.FlagSet) func() (*Profile, error) {
+	profile := flags.String("profile", "", "Configuration profile; defaults to $"+EnvProfile+" or the configuration file's DefaultProfile")
+	baseURL := flags.String("base_url", "", "Base URL for controller; overrides the profile's (default "+DefaultBaseURL+")")
+	return func() (*Profile, error) {
+		c, err := LoadDefault()
+		if err != nil {
+			return nil, err
+		}
+		p, err := c.Profile(*profile)
+		if err != nil {
+			return nil, err
+		}
+		if *baseURL != "" {
+			overridden := *p
+			overridden.BaseURL = *baseURL
+			p = &overridden
+		}
+		return p, nil
+	}
+}
@@ -0,0 +1,144 @@
+package config_test
+
+import (
+	"flag"
+	"github.com/scottlamb/luxor/config"
+	"github.com/scottlamb/luxor/discovery"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sample = `{
+  "DefaultProfile": "home",
+  "Profiles": {
+    "home": {
+      "BaseURL": "http://luxor.home.example/",
+      "Timeout": "5s",
+      "GroupAliases": {"path": "Front Path"},
+      "ThemeAliases": {"party": "C"}
+    },
+    "rental": {
+      "BaseURL": "http://192.168.7.20",
+      "ControllerName": "Rental",
+      "Subnets": ["192.168.7.0/24"]
+    }
+  }
+}`
+
+// setup writes a configuration file and points $LUXOR_CONFIG at it.
+func setup(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(config.EnvConfig, path)
+	t.Setenv(config.EnvProfile, "")
+	return path
+}
+
+func TestProfile(t *testing.T) {
+	setup(t, sample)
+	c, err := config.LoadDefault()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := c.Profile("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "home" || time.Duration(p.Timeout) != 5*time.Second {
+		t.Errorf("default profile: %+v", p)
+	}
+	if cl := p.Client(); cl.BaseURL != "http://luxor.home.example" || cl.Timeout != 5*time.Second {
+		t.Errorf("client: %+v", cl)
+	}
+	if got := p.Group("Path"); got != "Front Path" {
+		t.Errorf("Group(Path) = %q", got)
+	}
+	if got := p.Group("Patio"); got != "Patio" {
+		t.Errorf("Group(Patio) = %q", got)
+	}
+	if got := p.Theme("party"); got != "C" {
+		t.Errorf("Theme(party) = %q", got)
+	}
+
+	if cl := p.Client(); cl.Name != "" || cl.Resolver != nil {
+		t.Errorf("client without ControllerName: %+v", cl)
+	}
+
+	t.Setenv(config.EnvProfile, "rental")
+	if p, err := c.Profile(""); err != nil || p.Name != "rental" {
+		t.Errorf("$%s=rental: %+v, %v", config.EnvProfile, p, err)
+	}
+	p, _ = c.Profile("rental")
+	cl := p.Client()
+	r, ok := cl.Resolver.(*discovery.Resolver)
+	if cl.Name != "Rental" || !ok || len(r.Prefixes) != 1 || r.Prefixes[0] != netip.MustParsePrefix("192.168.7.0/24") {
+		t.Errorf("client with ControllerName: %+v, resolver %+v", cl, cl.Resolver)
+	}
+	if p, err := c.Profile("home"); err != nil || p.Name != "home" {
+		t.Errorf("explicit home: %+v, %v", p, err)
+	}
+	if _, err := c.Profile("office"); err == nil || !strings.Contains(err.Error(), `have ["home" "rental"]`) {
+		t.Errorf("missing profile: %v", err)
+	}
+}
+
+func TestNoConfig(t *testing.T) {
+	t.Setenv(config.EnvConfig, "")
+	t.Setenv(config.EnvProfile, "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	c, err := config.LoadDefault()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := c.Profile("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cl := p.Client(); cl.BaseURL != "http://luxor" {
+		t.Errorf("client: %+v", cl)
+	}
+
+	// An explicitly named file must exist.
+	t.Setenv(config.EnvConfig, filepath.Join(t.TempDir(), "missing.json"))
+	if _, err := config.LoadDefault(); err == nil {
+		t.Errorf("missing $%s: no error", config.EnvConfig)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	for _, contents := range []string{
+		`{"Profiles": {"home": {"BaseURI": "http://luxor/"}}}`,
+		`{"Profiles": {"home": {"Timeout": 5}}}`,
+		`{"DefaultProfile": "office", "Profiles": {"home": {}}}`,
+		`{"Profiles": {"home": {"ControllerName": "Home", "Subnets": ["192.168.1.0"]}}}`,
+	} {
+		path := setup(t, contents)
+		if _, err := config.Load(path); err == nil {
+			t.Errorf("%s: no error", contents)
+		}
+	}
+}
+
+func TestRegisterFlags(t *testing.T) {
+	setup(t, sample)
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	selectProfile := config.RegisterFlags(flags)
+	if err := flags.Parse([]string{"--profile", "rental", "--base_url", "http://10.0.0.2/"}); err != nil {
+		t.Fatal(err)
+	}
+	p, err := selectProfile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "rental" || p.BaseURL != "http://10.0.0.2/" {
+		t.Errorf("profile: %+v", p)
+	}
+}
@@ -0,0 +1,168 @@
+package ctl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/scottlamb/luxor/protocol"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Batch runs a sequence of protocol calls, one per line of input:
+//
+//	# Commission the pool zone.
+//	GroupListAdd {"GroupNumber": 7, "Name": "Pool"}
+//	IlluminateGroup {"GroupNumber": 7, "Intensity": 50}
+//	ControllerName
+//
+// Blank lines and lines starting with "#" are skipped. A line may also be a
+// Step as written by a previous run, so a run's output can be replayed.
+type Batch struct {
+	Controller protocol.Controller
+
+	// ContinueOnError runs the remaining steps after a failure.
+	ContinueOnError bool
+
+	// Delay is the pause between steps.
+	Delay time.Duration
+
+	// Timeout, if non-zero, bounds each step.
+	Timeout time.Duration
+}
+
+// Step is the result of one step of a Batch, written as a line of JSON.
+type Step struct {
+	Step    int // 1-based.
+	Line    int // line number in the input.
+	Method  string
+	Request json.RawMessage `json:",omitempty"`
+
+	// Input is the line as given, if it couldn't be parsed.
+	Input string `json:",omitempty"`
+
+	// Status is 0 on success, the controller's status on failure, or -1
+	// for other failures, as from protocol.StatusOf.
+	Status   int
+	Error    string      `json:",omitempty"`
+	Response interface{} `json:",omitempty"`
+
+	Start   time.Time
+	Seconds float64
+}
+
+// parseStep parses a line of input. On failure, it also returns the
+// unparseable text, unwrapped from a replayed Step.
+func parseStep(line string) (method string, request json.RawMessage, input string, err error) {
+	if strings.HasPrefix(line, "{") {
+		var s Step
+		if err := json.Unmarshal([]byte(line), &s); err != nil {
+			return "", nil, line, fmt.Errorf("bad step: %v", err)
+		}
+		if s.Input == "" {
+			return s.Method, s.Request, "", nil
+		}
+		line = s.Input
+	}
+	fields := strings.SplitN(line, " ", 2)
+	method = fields[0]
+	if len(fields) == 2 && strings.TrimSpace(fields[1]) != "" {
+		var b bytes.Buffer
+		if err := json.Compact(&b, []byte(fields[1])); err != nil {
+			return method, nil, line, fmt.Errorf("bad %s request: %v", method, err)
+		}
+		request = b.Bytes()
+	}
+	return method, request, "", nil
+}
+
+// Run runs the steps read from r, writing a Step to w for each. It returns
+// the first step's error, wrapped with its step number.
+func (b *Batch) Run(ctx context.Context, r io.Reader, w io.Writer) error {
+	enc := json.NewEncoder(w)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	var first error
+	n := 0
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if n > 0 && b.Delay > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(b.Delay):
+			}
+		}
+		n++
+		step := &Step{Step: n, Line: lineNum, Start: time.Now()}
+		err := b.run(ctx, step, line)
+		step.Seconds = time.Since(step.Start).Seconds()
+		step.Status = protocol.StatusOf(err)
+		if err != nil {
+			step.Error = err.Error()
+		}
+		if werr := enc.Encode(step); werr != nil {
+			return werr
+		}
+		if err != nil && first == nil {
+			first = fmt.Errorf("step %d (line %d, %s): %w", n, lineNum, step.Method, err)
+		}
+		if err != nil && !b.ContinueOnError {
+			return first
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return first
+}
+
+func (b *Batch) run(ctx context.Context, step *Step, line string) error {
+	method, request, input, err := parseStep(line)
+	step.Method, step.Request, step.Input = method, request, input
+	if err != nil {
+		return err
+	}
+	if b.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.Timeout)
+		defer cancel()
+	}
+	resp, err := Call(ctx, b.Controller, method, request)
+	if err == nil {
+		step.Response = resp
+	}
+	return err
+}
+
+func (c *CLI) batch(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("batch", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	b := &Batch{Controller: c.Controller, Timeout: c.Timeout}
+	flags.BoolVar(&b.ContinueOnError, "continue", false, "")
+	flags.DurationVar(&b.Delay, "delay", 0, "")
+	if err := flags.Parse(args); err != nil {
+		return usageErrorf("batch: %v", err)
+	}
+	if flags.NArg() > 1 {
+		return usageErrorf("expected at most one FILE")
+	}
+	in := c.stdin()
+	if flags.NArg() == 1 && flags.Arg(0) != "-" {
+		f, err := os.Open(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	return b.Run(ctx, in, c.Stdout)
+}
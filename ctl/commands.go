@@ -0,0 +1,609 @@
+package ctl
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/scottlamb/luxor/protocol"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// ThemeGroup is a group's entry in a theme, with the group's name for
+// display.
+type ThemeGroup struct {
+	GroupNumber uint8
+	Name        string
+	Intensity   uint8
+
+	// Color is the entry's ZDC color number, if any.
+	Color uint8 `json:",omitempty"`
+}
+
+// Theme is a theme in the output of "themes list" and "theme on/off".
+type Theme struct {
+	ThemeIndex uint8
+	Letter     string
+	Name       string
+	On         bool
+}
+
+// ThemeDetail is the output of "theme show".
+type ThemeDetail struct {
+	Theme
+	Groups []ThemeGroup
+}
+
+func (c *CLI) writeJSON(v interface{}) error {
+	e := json.NewEncoder(c.Stdout)
+	e.SetIndent("", "  ")
+	return e.Encode(v)
+}
+
+func (c *CLI) table() *tabwriter.Writer {
+	return tabwriter.NewWriter(c.Stdout, 0, 4, 2, ' ', 0)
+}
+
+// wantArgs checks the number of arguments to a command.
+func wantArgs(args []string, names ...string) error {
+	if len(args) != len(names) {
+		return usageErrorf("expected %s; got %d argument(s)", strings.Join(names, " "), len(args))
+	}
+	return nil
+}
+
+// ParseIntensity parses an intensity: a percentage from 0 to 100 (with an
+// optional "%"), "on" for 100, or "off" for 0.
+func ParseIntensity(s string) (uint8, error) {
+	switch strings.ToLower(s) {
+	case "on":
+		return protocol.MaxIntensity, nil
+	case "off":
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(strings.TrimSuffix(s, "%"), 10, 8)
+	if err != nil || n > protocol.MaxIntensity {
+		return 0, usageErrorf("bad intensity %q; want 0-%d, on, or off", s, protocol.MaxIntensity)
+	}
+	return uint8(n), nil
+}
+
+func (c *CLI) groups(ctx context.Context) ([]protocol.Group, error) {
+	resp, err := c.Controller.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GroupList, nil
+}
+
+// findGroup finds a group by number or, failing that, by name. Names are
+// matched case-insensitively.
+func findGroup(groups []protocol.Group, id string) (*protocol.Group, error) {
+	if n, err := strconv.ParseUint(id, 10, 8); err == nil {
+		for i := range groups {
+			if groups[i].GroupNumber == uint8(n) {
+				return &groups[i], nil
+			}
+		}
+	}
+	for i := range groups {
+		if strings.EqualFold(groups[i].Name, id) {
+			return &groups[i], nil
+		}
+	}
+	return nil, &notFoundError{fmt.Sprintf("no group %q", id)}
+}
+
+func (c *CLI) themes(ctx context.Context) ([]protocol.Theme, error) {
+	resp, err := c.Controller.ThemeListGet(ctx, &protocol.ThemeListGetRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.ThemeList, nil
+}
+
+// findTheme finds a theme by letter or, failing that, by name.
+func findTheme(themes []protocol.Theme, id string) (*protocol.Theme, error) {
+	if index, err := protocol.ParseThemeLetter(id); err == nil {
+		for i := range themes {
+			if themes[i].ThemeIndex == index {
+				return &themes[i], nil
+			}
+		}
+		return nil, &notFoundError{fmt.Sprintf("no theme %s", protocol.ThemeLetter(index))}
+	}
+	for i := range themes {
+		if strings.EqualFold(themes[i].Name, id) {
+			return &themes[i], nil
+		}
+	}
+	return nil, &notFoundError{fmt.Sprintf("no theme %q", id)}
+}
+
+func (c *CLI) groupsList(ctx context.Context, args []string) error {
+	if err := wantArgs(args); err != nil {
+		return err
+	}
+	groups, err := c.groups(ctx)
+	if err != nil {
+		return err
+	}
+	if c.JSON {
+		if groups == nil {
+			groups = []protocol.Group{}
+		}
+		return c.writeJSON(groups)
+	}
+	w := c.table()
+	fmt.Fprintf(w, "GROUP\tNAME\tINTENSITY\n")
+	for _, g := range groups {
+		fmt.Fprintf(w, "%d\t%s\t%d%%\n", g.GroupNumber, g.Name, g.Intensity)
+	}
+	return w.Flush()
+}
+
+func (c *CLI) groupSet(ctx context.Context, args []string) error {
+	if err := wantArgs(args, "GROUP", "INTENSITY"); err != nil {
+		return err
+	}
+	intensity, err := ParseIntensity(args[1])
+	if err != nil {
+		return err
+	}
+	groups, err := c.groups(ctx)
+	if err != nil {
+		return err
+	}
+	g, err := findGroup(groups, c.groupID(args[0]))
+	if err != nil {
+		return err
+	}
+	if _, err := c.Controller.IlluminateGroup(ctx, &protocol.IlluminateGroupRequest{
+		GroupNumber: g.GroupNumber,
+		Intensity:   intensity,
+	}); err != nil {
+		return err
+	}
+	g.Intensity = intensity
+	if c.JSON {
+		return c.writeJSON(g)
+	}
+	fmt.Fprintf(c.Stdout, "%s (group %d) set to %d%%\n", g.Name, g.GroupNumber, intensity)
+	return nil
+}
+
+func (c *CLI) groupRename(ctx context.Context, args []string) error {
+	if err := wantArgs(args, "GROUP", "NAME"); err != nil {
+		return err
+	}
+	newName := args[1]
+	if newName == "" {
+		return usageErrorf("new name is empty")
+	}
+	if len(newName) > protocol.MaxNameLength {
+		return usageErrorf("name %q is longer than the controller's limit of %d characters", newName, protocol.MaxNameLength)
+	}
+	groups, err := c.groups(ctx)
+	if err != nil {
+		return err
+	}
+	g, err := findGroup(groups, c.groupID(args[0]))
+	if err != nil {
+		return err
+	}
+	oldName := g.Name
+	if _, err := c.Controller.GroupListRename(ctx, &protocol.GroupListRenameRequest{
+		OldName: oldName,
+		NewName: newName,
+	}); err != nil {
+		return err
+	}
+	g.Name = newName
+	if c.JSON {
+		return c.writeJSON(g)
+	}
+	fmt.Fprintf(c.Stdout, "group %d renamed from %s to %s\n", g.GroupNumber, oldName, newName)
+	return nil
+}
+
+func (c *CLI) themesList(ctx context.Context, args []string) error {
+	if err := wantArgs(args); err != nil {
+		return err
+	}
+	themes, err := c.themes(ctx)
+	if err != nil {
+		return err
+	}
+	if c.JSON {
+		out := make([]Theme, len(themes))
+		for i, t := range themes {
+			out[i] = Theme{ThemeIndex: t.ThemeIndex, Letter: protocol.ThemeLetter(t.ThemeIndex), Name: t.Name, On: t.OnOff != 0}
+		}
+		return c.writeJSON(out)
+	}
+	w := c.table()
+	fmt.Fprintf(w, "THEME\tNAME\tSTATE\n")
+	for _, t := range themes {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", protocol.ThemeLetter(t.ThemeIndex), t.Name, onOff(t.OnOff))
+	}
+	return w.Flush()
+}
+
+func onOff(v uint8) string {
+	if v != 0 {
+		return "on"
+	}
+	return "off"
+}
+
+func (c *CLI) illuminateTheme(ctx context.Context, args []string, on uint8) error {
+	if err := wantArgs(args, "THEME"); err != nil {
+		return err
+	}
+	themes, err := c.themes(ctx)
+	if err != nil {
+		return err
+	}
+	t, err := findTheme(themes, c.themeID(args[0]))
+	if err != nil {
+		return err
+	}
+	if _, err := c.Controller.IlluminateTheme(ctx, &protocol.IlluminateThemeRequest{
+		ThemeIndex: t.ThemeIndex,
+		OnOff:      on,
+	}); err != nil {
+		return err
+	}
+	if c.JSON {
+		return c.writeJSON(Theme{ThemeIndex: t.ThemeIndex, Letter: protocol.ThemeLetter(t.ThemeIndex), Name: t.Name, On: on != 0})
+	}
+	fmt.Fprintf(c.Stdout, "theme %s (%s) %s\n", protocol.ThemeLetter(t.ThemeIndex), t.Name, onOff(on))
+	return nil
+}
+
+func (c *CLI) themeOn(ctx context.Context, args []string) error {
+	return c.illuminateTheme(ctx, args, 1)
+}
+
+func (c *CLI) themeOff(ctx context.Context, args []string) error {
+	return c.illuminateTheme(ctx, args, 0)
+}
+
+// themeDetail fetches the theme named by id, with its groups.
+func (c *CLI) themeDetail(ctx context.Context, id string) (*ThemeDetail, []protocol.Group, error) {
+	themes, err := c.themes(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	t, err := findTheme(themes, c.themeID(id))
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := c.Controller.ThemeGet(ctx, &protocol.ThemeGetRequest{ThemeIndex: t.ThemeIndex})
+	if err != nil {
+		return nil, nil, err
+	}
+	groups, err := c.groups(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	names := make(map[uint8]string, len(groups))
+	for _, g := range groups {
+		names[g.GroupNumber] = g.Name
+	}
+	d := &ThemeDetail{
+		Theme:  Theme{ThemeIndex: t.ThemeIndex, Letter: protocol.ThemeLetter(t.ThemeIndex), Name: t.Name, On: t.OnOff != 0},
+		Groups: make([]ThemeGroup, len(resp.Groups)),
+	}
+	for i, tg := range resp.Groups {
+		d.Groups[i] = ThemeGroup{GroupNumber: tg.GroupNumber, Name: names[tg.GroupNumber], Intensity: tg.Intensity, Color: tg.Color}
+	}
+	return d, groups, nil
+}
+
+func (c *CLI) printThemeDetail(d *ThemeDetail) error {
+	if c.JSON {
+		return c.writeJSON(d)
+	}
+	state := "off"
+	if d.On {
+		state = "on"
+	}
+	fmt.Fprintf(c.Stdout, "theme %s (%s) is %s\n\n", d.Letter, d.Name, state)
+	colored := false
+	for _, g := range d.Groups {
+		colored = colored || g.Color != 0
+	}
+	w := c.table()
+	if !colored {
+		fmt.Fprintf(w, "GROUP\tNAME\tINTENSITY\n")
+		for _, g := range d.Groups {
+			fmt.Fprintf(w, "%d\t%s\t%d%%\n", g.GroupNumber, g.Name, g.Intensity)
+		}
+		return w.Flush()
+	}
+	fmt.Fprintf(w, "GROUP\tNAME\tINTENSITY\tCOLOR\n")
+	for _, g := range d.Groups {
+		color := "-"
+		if g.Color != 0 {
+			color = strconv.Itoa(int(g.Color))
+		}
+		fmt.Fprintf(w, "%d\t%s\t%d%%\t%s\n", g.GroupNumber, g.Name, g.Intensity, color)
+	}
+	return w.Flush()
+}
+
+func (c *CLI) themeShow(ctx context.Context, args []string) error {
+	if err := wantArgs(args, "THEME"); err != nil {
+		return err
+	}
+	d, _, err := c.themeDetail(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return c.printThemeDetail(d)
+}
+
+// themeEdit changes a theme's definition. Each argument is GROUP=INTENSITY,
+// adding or changing a group, or GROUP=-, removing it. An intensity may be
+// followed by @COLOR to set the entry's color number (0 for none); otherwise
+// an entry keeps its color. With no arguments, the definition is edited as
+// text in $VISUAL or $EDITOR.
+func (c *CLI) themeEdit(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("expected THEME [GROUP=INTENSITY[@COLOR]|GROUP=-]...")
+	}
+	stepCtx, cancel := c.withTimeout(ctx)
+	d, groups, err := c.themeDetail(stepCtx, args[0])
+	cancel()
+	if err != nil {
+		return err
+	}
+	entries := make([]protocol.ThemeGroup, len(d.Groups))
+	for i, g := range d.Groups {
+		entries[i] = protocol.ThemeGroup{GroupNumber: g.GroupNumber, Intensity: g.Intensity, Color: g.Color}
+	}
+	changes := args[1:]
+	if len(changes) == 0 {
+		if entries, err = c.editTheme(d, groups); err != nil {
+			return err
+		}
+	} else {
+		for _, change := range changes {
+			if entries, err = c.applyChange(entries, groups, change); err != nil {
+				return err
+			}
+		}
+	}
+	canonical, issues := protocol.LintTheme(entries, groups)
+	for _, issue := range issues {
+		fmt.Fprintf(c.Stderr, "note: fixed theme %s: %s\n", d.Letter, issue.Message)
+	}
+	if canonical == nil {
+		canonical = []protocol.ThemeGroup{}
+	}
+	stepCtx, cancel = c.withTimeout(ctx)
+	_, err = c.Controller.ThemeSet(stepCtx, &protocol.ThemeSetRequest{
+		ThemeIndex: d.ThemeIndex,
+		Groups:     canonical,
+	})
+	cancel()
+	if err != nil {
+		return err
+	}
+	stepCtx, cancel = c.withTimeout(ctx)
+	defer cancel()
+	d, _, err = c.themeDetail(stepCtx, d.Letter)
+	if err != nil {
+		return err
+	}
+	return c.printThemeDetail(d)
+}
+
+// applyChange applies one GROUP=INTENSITY[@COLOR] or GROUP=- change to
+// entries.
+func (c *CLI) applyChange(entries []protocol.ThemeGroup, groups []protocol.Group, change string) ([]protocol.ThemeGroup, error) {
+	i := strings.LastIndexByte(change, '=')
+	if i < 0 {
+		return nil, usageErrorf("bad change %q; want GROUP=INTENSITY[@COLOR] or GROUP=-", change)
+	}
+	g, err := findGroup(groups, c.groupID(strings.TrimSpace(change[:i])))
+	if err != nil {
+		return nil, err
+	}
+	value := strings.TrimSpace(change[i+1:])
+	if value == "-" {
+		out := entries[:0]
+		for _, e := range entries {
+			if e.GroupNumber != g.GroupNumber {
+				out = append(out, e)
+			}
+		}
+		return out, nil
+	}
+	value, colorText, hasColor := strings.Cut(value, "@")
+	intensity, err := ParseIntensity(strings.TrimSpace(value))
+	if err != nil {
+		return nil, err
+	}
+	var color uint8
+	if hasColor {
+		n, err := strconv.ParseUint(strings.TrimSpace(colorText), 10, 8)
+		if err != nil {
+			return nil, usageErrorf("bad color %q; want a color number from 0 to 255", colorText)
+		}
+		color = uint8(n)
+	}
+	for j := range entries {
+		if entries[j].GroupNumber == g.GroupNumber {
+			entries[j].Intensity = intensity
+			if hasColor {
+				entries[j].Color = color
+			}
+			return entries, nil
+		}
+	}
+	return append(entries, protocol.ThemeGroup{GroupNumber: g.GroupNumber, Intensity: intensity, Color: color}), nil
+}
+
+// editTheme lets the user edit d's groups in a text editor, returning the
+// result.
+func (c *CLI) editTheme(d *ThemeDetail, groups []protocol.Group) ([]protocol.ThemeGroup, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	f, err := ioutil.TempFile("", "luxorctl-theme-*.txt")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	writeThemeText(f, d, groups)
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	cmd := exec.Command("sh", "-c", editor+` "$1"`, "sh", f.Name())
+	cmd.Stdin = c.stdin()
+	cmd.Stdout = c.Stdout
+	cmd.Stderr = c.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("editor: %v", err)
+	}
+	f, err = os.Open(f.Name())
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return c.parseThemeText(f, groups)
+}
+
+// writeThemeText writes d's groups as "GROUP = INTENSITY" lines (with
+// "@ COLOR" for colored entries), followed by the remaining groups commented
+// out.
+func writeThemeText(w io.Writer, d *ThemeDetail, groups []protocol.Group) {
+	fmt.Fprintf(w, "# Theme %s (%s). One \"GROUP = INTENSITY\" or \"GROUP = INTENSITY @ COLOR\"\n", d.Letter, d.Name)
+	fmt.Fprintf(w, "# per line; delete a line to remove its group. Groups may be named or\n")
+	fmt.Fprintf(w, "# numbered.\n")
+	in := make(map[uint8]bool, len(d.Groups))
+	for _, g := range d.Groups {
+		in[g.GroupNumber] = true
+		if g.Color != 0 {
+			fmt.Fprintf(w, "%s = %d @ %d\n", groupLabel(g.GroupNumber, g.Name), g.Intensity, g.Color)
+		} else {
+			fmt.Fprintf(w, "%s = %d\n", groupLabel(g.GroupNumber, g.Name), g.Intensity)
+		}
+	}
+	fmt.Fprintf(w, "\n# Groups not in this theme:\n")
+	for _, g := range groups {
+		if !in[g.GroupNumber] {
+			fmt.Fprintf(w, "# %s = 0\n", groupLabel(g.GroupNumber, g.Name))
+		}
+	}
+}
+
+// groupLabel is how a group appears in theme text: its name if it has one,
+// or its number.
+func groupLabel(number uint8, name string) string {
+	if name == "" || strings.ContainsRune(name, '=') {
+		return strconv.Itoa(int(number))
+	}
+	return name
+}
+
+func (c *CLI) parseThemeText(r io.Reader, groups []protocol.Group) ([]protocol.ThemeGroup, error) {
+	var entries []protocol.ThemeGroup
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		var err error
+		if entries, err = c.applyChange(entries, groups, text); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	return entries, s.Err()
+}
+
+func (c *CLI) allOff(ctx context.Context, args []string) error {
+	if err := wantArgs(args); err != nil {
+		return err
+	}
+	if _, err := c.Controller.ExtinguishAll(ctx, &protocol.ExtinguishAllRequest{}); err != nil {
+		return err
+	}
+	if !c.JSON {
+		fmt.Fprintln(c.Stdout, "all groups off")
+	}
+	return nil
+}
+
+func (c *CLI) allOn(ctx context.Context, args []string) error {
+	if err := wantArgs(args); err != nil {
+		return err
+	}
+	if _, err := c.Controller.IlluminateAll(ctx, &protocol.IlluminateAllRequest{}); err != nil {
+		return err
+	}
+	if !c.JSON {
+		fmt.Fprintln(c.Stdout, "all groups on")
+	}
+	return nil
+}
+
+var typeOfController = reflect.TypeOf((*protocol.Controller)(nil)).Elem()
+
+// Methods returns the names of protocol methods, for "call".
+func Methods() []string {
+	names := make([]string, typeOfController.NumMethod())
+	for i := range names {
+		names[i] = typeOfController.Method(i).Name
+	}
+	return names
+}
+
+// Call calls the named protocol method on controller, with the request
+// decoded from JSON (which may be empty), and returns the response.
+func Call(ctx context.Context, controller protocol.Controller, method string, request []byte) (interface{}, error) {
+	m, ok := typeOfController.MethodByName(method)
+	if !ok {
+		return nil, usageErrorf("no method %q; try one of %s", method, strings.Join(Methods(), ", "))
+	}
+	req := reflect.New(m.Type.In(1).Elem())
+	if len(request) > 0 {
+		if err := json.Unmarshal(request, req.Interface()); err != nil {
+			return nil, usageErrorf("bad %s request: %v", method, err)
+		}
+	}
+	out := reflect.ValueOf(controller).MethodByName(method).Call([]reflect.Value{reflect.ValueOf(ctx), req})
+	if !out[1].IsNil() {
+		return nil, out[1].Interface().(error)
+	}
+	return out[0].Interface(), nil
+}
+
+func (c *CLI) call(ctx context.Context, args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return usageErrorf("expected METHOD [REQUEST_JSON]")
+	}
+	var request []byte
+	if len(args) == 2 {
+		request = []byte(args[1])
+	}
+	resp, err := Call(ctx, c.Controller, args[0], request)
+	if err != nil {
+		return err
+	}
+	return c.writeJSON(resp)
+}
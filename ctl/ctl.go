@@ -0,0 +1,264 @@
+// Package ctl implements luxorctl, a command-line interface to a controller
+// for humans:
+//
+//	luxorctl groups list
+//	luxorctl group set "Front Path" 60
+//	luxorctl group rename "Front Path" "Walk"
+//	luxorctl themes list
+//	luxorctl theme on C
+//	luxorctl theme off C
+//	luxorctl theme show C
+//	luxorctl theme edit C "Front Path"=60 Patio=- ...
+//	luxorctl all off
+//	luxorctl call GroupListGet '{}'
+//	luxorctl discover 192.168.1.0/24
+//	luxorctl export --metadata site.json --output sheet.html
+//	luxorctl doctor --fix
+//	luxorctl loadtest --rate 2 --writes 0.2 --stages 5 --ramp 2 > results.json
+//	luxorctl batch --delay 2s commission.txt
+//	luxorctl shell
+//
+// Groups may be given by number or name, and themes by letter or name, or by
+// aliases from the configuration profile (see package config).
+// Output is an aligned table, or JSON with --json. Failures print a short
+// explanation and exit with a status from ExitCode.
+package ctl
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/scottlamb/luxor/config"
+	"github.com/scottlamb/luxor/protocol"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// CLI runs commands against one controller.
+type CLI struct {
+	Controller protocol.Controller
+
+	// Profile, if non-nil, supplies aliases for group and theme names.
+	Profile *config.Profile
+
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// JSON selects JSON output instead of tables.
+	JSON bool
+
+	// Timeout, if non-zero, bounds each command, or each step of commands
+	// which wait on the user or their input, such as "shell", "batch", and
+	// "theme edit".
+	Timeout time.Duration
+
+	// HistoryPath is where "shell" keeps its command history. If empty,
+	// history lasts only for the session.
+	HistoryPath string
+}
+
+// command is a subcommand, possibly with subcommands of its own.
+type command struct {
+	name    string
+	args    string // usage synopsis of arguments.
+	summary string
+	run     func(c *CLI, ctx context.Context, args []string) error
+	sub     []*command
+
+	// session is true for commands which run until the user ends them or
+	// their input, or which wait on the user, and so aren't subject to
+	// CLI.Timeout as a whole. They apply it to each step with withTimeout.
+	session bool
+}
+
+// commands is populated in init to avoid an initialization cycle through
+// usage.
+var commands []*command
+
+func init() {
+	commands = []*command{
+		{name: "groups", summary: "list groups", sub: []*command{
+			{name: "list", summary: "list groups with their intensities", run: (*CLI).groupsList},
+		}},
+		{name: "group", summary: "control one group", sub: []*command{
+			{name: "set", args: "GROUP INTENSITY", summary: "set a group's intensity (0-100, on, or off)", run: (*CLI).groupSet},
+			{name: "rename", args: "GROUP NAME", summary: "rename a group", run: (*CLI).groupRename},
+		}},
+		{name: "themes", summary: "list themes", sub: []*command{
+			{name: "list", summary: "list themes with their states", run: (*CLI).themesList},
+		}},
+		{name: "theme", summary: "control one theme", sub: []*command{
+			{name: "on", args: "THEME", summary: "illuminate a theme", run: (*CLI).themeOn},
+			{name: "off", args: "THEME", summary: "extinguish a theme", run: (*CLI).themeOff},
+			{name: "show", args: "THEME", summary: "show a theme's groups and intensities", run: (*CLI).themeShow},
+			{name: "edit", args: "THEME [GROUP=INTENSITY[@COLOR]|GROUP=-]...", summary: "change a theme's definition; with no changes, opens $EDITOR", run: (*CLI).themeEdit, session: true},
+		}},
+		{name: "all", summary: "control all groups", sub: []*command{
+			{name: "off", summary: "extinguish everything", run: (*CLI).allOff},
+			{name: "on", summary: "illuminate everything", run: (*CLI).allOn},
+		}},
+		{name: "call", args: "METHOD [REQUEST_JSON]", summary: "call a protocol method directly", run: (*CLI).call},
+		{name: "discover", args: "[--port N] [CIDR]...", summary: "find controllers on the local network, or in the given ranges", run: (*CLI).discover, session: true},
+		{name: "export", args: "[--format FMT] [--metadata F] [--output F]", summary: "write installation documentation (csv, markdown, or html)", run: (*CLI).export},
+		{name: "loadtest", args: "[--rate R] [--writes F] [--stages N --ramp R]", summary: "measure latency and errors under load, writing results as JSON", run: (*CLI).loadtest, session: true},
+		{name: "doctor", args: "[--fix [--yes]]", summary: "check the controller for problems; --fix offers repairs", run: (*CLI).doctor, session: true},
+		{name: "batch", args: "[--continue] [--delay DURATION] [FILE]", summary: `run "Method {json}" lines from FILE or stdin, writing results as JSON lines`, run: (*CLI).batch, session: true},
+		{name: "shell", summary: "run commands interactively", run: (*CLI).shell, session: true},
+	}
+}
+
+// Usage writes a summary of commands to w.
+func Usage(w io.Writer) {
+	fmt.Fprintf(w, "usage: luxorctl [flags] COMMAND [ARGS]\n\ncommands:\n")
+	for _, cmd := range commands {
+		if cmd.run != nil {
+			fmt.Fprintf(w, "  %-45s %s\n", cmd.name+" "+cmd.args, cmd.summary)
+		}
+		for _, sub := range cmd.sub {
+			fmt.Fprintf(w, "  %-45s %s\n", cmd.name+" "+sub.name+" "+sub.args, sub.summary)
+		}
+	}
+}
+
+// Run runs the command given by args, such as ["group", "set", "3", "60"].
+func (c *CLI) Run(ctx context.Context, args []string) error {
+	cmds := commands
+	var path []string
+	for {
+		if len(args) == 0 {
+			if len(path) == 0 {
+				return usageErrorf("no command given")
+			}
+			return usageErrorf("%q needs a subcommand", strings.Join(path, " "))
+		}
+		var found *command
+		for _, cmd := range cmds {
+			if cmd.name == args[0] {
+				found = cmd
+			}
+		}
+		if found == nil {
+			if len(path) == 0 {
+				return usageErrorf("unknown command %q", args[0])
+			}
+			return usageErrorf("unknown command %q", strings.Join(append(path, args[0]), " "))
+		}
+		path = append(path, args[0])
+		args = args[1:]
+		if len(args) == 0 && len(found.sub) == 1 {
+			// "groups" alone means "groups list".
+			found = found.sub[0]
+		}
+		if found.run != nil {
+			if c.Timeout > 0 && !found.session {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+				defer cancel()
+			}
+			return found.run(c, ctx, args)
+		}
+		cmds = found.sub
+	}
+}
+
+// withTimeout bounds one step of a session command by c.Timeout.
+func (c *CLI) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.Timeout > 0 {
+		return context.WithTimeout(ctx, c.Timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// lookup finds the command named by a prefix of args, returning it and the
+// number of words in its name.
+func lookup(args []string) (*command, int) {
+	cmds := commands
+	var cmd *command
+	n := 0
+	for n < len(args) {
+		var found *command
+		for _, c := range cmds {
+			if c.name == args[n] {
+				found = c
+			}
+		}
+		if found == nil {
+			break
+		}
+		cmd, cmds = found, found.sub
+		n++
+	}
+	return cmd, n
+}
+
+// Main runs luxorctl with the given arguments (excluding the program name)
+// and returns its exit status.
+func Main(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("luxorctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	selectProfile := config.RegisterFlags(flags)
+	jsonOutput := flags.Bool("json", false, "Write JSON instead of tables")
+	timeout := flags.Duration("timeout", 10*time.Second, "Timeout for each command")
+	flags.Usage = func() {
+		Usage(stderr)
+		fmt.Fprintf(stderr, "\nflags:\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return ExitOK
+		}
+		return ExitUsage
+	}
+	profile, err := selectProfile()
+	if err != nil {
+		fmt.Fprintf(stderr, "luxorctl: %v\n", err)
+		return ExitUsage
+	}
+	c := &CLI{
+		Controller:  profile.Client(),
+		Profile:     profile,
+		Stdin:       stdin,
+		Stdout:      stdout,
+		Stderr:      stderr,
+		JSON:        *jsonOutput,
+		Timeout:     *timeout,
+		HistoryPath: DefaultHistoryPath(),
+	}
+	err = c.Run(ctx, flags.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "luxorctl: %s\n", Message(err))
+		if _, ok := err.(*usageError); ok {
+			fmt.Fprintln(stderr)
+			Usage(stderr)
+		}
+	}
+	return ExitCode(err)
+}
+
+// groupID resolves a group alias from the profile.
+func (c *CLI) groupID(id string) string {
+	if c.Profile == nil {
+		return id
+	}
+	return c.Profile.Group(id)
+}
+
+// themeID resolves a theme alias from the profile.
+func (c *CLI) themeID(id string) string {
+	if c.Profile == nil {
+		return id
+	}
+	return c.Profile.Theme(id)
+}
+
+// stdin returns c.Stdin, defaulting to os.Stdin.
+func (c *CLI) stdin() io.Reader {
+	if c.Stdin != nil {
+		return c.Stdin
+	}
+	return os.Stdin
+}
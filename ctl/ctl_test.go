@@ -0,0 +1,478 @@
+package ctl_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/scottlamb/luxor/config"
+	"github.com/scottlamb/luxor/ctl"
+	"github.com/scottlamb/luxor/emulator"
+	"github.com/scottlamb/luxor/loadtest"
+	"github.com/scottlamb/luxor/protocol"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func setup(t *testing.T) *emulator.Controller {
+	t.Helper()
+	ctx := context.Background()
+	e := emulator.New("test")
+	for _, g := range []protocol.GroupListAddRequest{
+		{GroupNumber: 1, Name: "Front Path"},
+		{GroupNumber: 2, Name: "Patio"},
+		{GroupNumber: 3, Name: "Trees"},
+	} {
+		if _, err := e.GroupListAdd(ctx, &g); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := e.ThemeListAdd(ctx, &protocol.ThemeListAddRequest{ThemeIndex: 2, Name: "Evening"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.ThemeSet(ctx, &protocol.ThemeSetRequest{ThemeIndex: 2, Groups: []protocol.ThemeGroup{
+		{GroupNumber: 1, Intensity: 50},
+		{GroupNumber: 2, Intensity: 80},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+// isolate keeps tests from reading the user's configuration or writing
+// their history.
+func isolate(t *testing.T) {
+	t.Helper()
+	t.Setenv(config.EnvConfig, "")
+	t.Setenv(config.EnvProfile, "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+}
+
+// run runs luxorctl against e through the wire protocol, returning its exit
+// status and output.
+func run(t *testing.T, e *emulator.Controller, args ...string) (int, string, string) {
+	t.Helper()
+	isolate(t)
+	server := httptest.NewServer(&emulator.Handler{Controller: e})
+	defer server.Close()
+	var stdout, stderr bytes.Buffer
+	code := ctl.Main(context.Background(), append([]string{"--base_url", server.URL}, args...), strings.NewReader(""), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestGroups(t *testing.T) {
+	ctx := context.Background()
+	e := setup(t)
+	code, out, _ := run(t, e, "group", "set", "front path", "60")
+	if code != ctl.ExitOK || out != "Front Path (group 1) set to 60%\n" {
+		t.Fatalf("group set: %d %q", code, out)
+	}
+	code, out, _ = run(t, e, "groups", "list")
+	want := "GROUP  NAME        INTENSITY\n" +
+		"1      Front Path  60%\n" +
+		"2      Patio       0%\n" +
+		"3      Trees       0%\n"
+	if code != ctl.ExitOK || out != want {
+		t.Fatalf("groups list: %d\n%s\nwant\n%s", code, out, want)
+	}
+	if code, _, _ := run(t, e, "group", "rename", "2", "Deck"); code != ctl.ExitOK {
+		t.Fatalf("group rename: %d", code)
+	}
+	resp, err := e.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GroupList[1].Name != "Deck" {
+		t.Errorf("group 2 is %q after rename", resp.GroupList[1].Name)
+	}
+	code, out, _ = run(t, e, "--json", "groups")
+	var groups []protocol.Group
+	if err := json.Unmarshal([]byte(out), &groups); err != nil || code != ctl.ExitOK {
+		t.Fatalf("groups --json: %d %q: %v", code, out, err)
+	}
+	if len(groups) != 3 || groups[0].Intensity != 60 {
+		t.Errorf("groups --json: %+v", groups)
+	}
+}
+
+// TestThemeEditColor checks that editing a ZDC theme keeps its colors.
+func TestThemeEditColor(t *testing.T) {
+	ctx := context.Background()
+	e := emulator.NewZDC("test")
+	for _, g := range []protocol.GroupListAddRequest{
+		{GroupNumber: 1, Name: "Front Path", Type: protocol.GroupTypeColor},
+		{GroupNumber: 2, Name: "Trees", Type: protocol.GroupTypeColor},
+	} {
+		if _, err := e.GroupListAdd(ctx, &g); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := e.ThemeListAdd(ctx, &protocol.ThemeListAddRequest{ThemeIndex: 7, Name: "Holiday"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.ThemeSet(ctx, &protocol.ThemeSetRequest{ThemeIndex: 7, Groups: []protocol.ThemeGroup{
+		{GroupNumber: 1, Intensity: 50, Color: 3},
+		{GroupNumber: 2, Intensity: 90, Color: 4},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	check := func(step string, want []protocol.ThemeGroup) {
+		t.Helper()
+		resp, err := e.ThemeGet(ctx, &protocol.ThemeGetRequest{ThemeIndex: 7})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(resp.Groups, want) {
+			t.Errorf("after %s, theme H is %+v; want %+v", step, resp.Groups, want)
+		}
+	}
+
+	if code, _, stderr := run(t, e, "theme", "edit", "H", "1=60"); code != ctl.ExitOK {
+		t.Fatalf("theme edit: %d %s", code, stderr)
+	}
+	check("edit without color", []protocol.ThemeGroup{{GroupNumber: 1, Intensity: 60, Color: 3}, {GroupNumber: 2, Intensity: 90, Color: 4}})
+
+	if code, _, stderr := run(t, e, "theme", "edit", "H", "Trees=80@6", "1=60@0"); code != ctl.ExitOK {
+		t.Fatalf("theme edit with colors: %d %s", code, stderr)
+	}
+	check("edit with colors", []protocol.ThemeGroup{{GroupNumber: 1, Intensity: 60}, {GroupNumber: 2, Intensity: 80, Color: 6}})
+
+	t.Setenv("VISUAL", "sed -i -e 's/= 80/= 20/'")
+	if code, _, stderr := run(t, e, "theme", "edit", "H"); code != ctl.ExitOK {
+		t.Fatalf("theme edit in editor: %d %s", code, stderr)
+	}
+	check("editor", []protocol.ThemeGroup{{GroupNumber: 1, Intensity: 60}, {GroupNumber: 2, Intensity: 20, Color: 6}})
+
+	code, out, _ := run(t, e, "--json", "theme", "show", "H")
+	var d ctl.ThemeDetail
+	if err := json.Unmarshal([]byte(out), &d); err != nil || code != ctl.ExitOK {
+		t.Fatalf("theme show --json: %d %q: %v", code, out, err)
+	}
+	if len(d.Groups) != 2 || d.Groups[0].Color != 0 || d.Groups[1].Color != 6 {
+		t.Errorf("theme show --json: %+v", d)
+	}
+	code, out, _ = run(t, e, "theme", "show", "H")
+	if want := "2      Trees       20%        6\n"; code != ctl.ExitOK || !strings.Contains(out, want) {
+		t.Errorf("theme show: %d\n%s\nwant line %q", code, out, want)
+	}
+}
+
+// TestThemeEditTimeout checks that --timeout doesn't count time in $EDITOR.
+func TestThemeEditTimeout(t *testing.T) {
+	ctx := context.Background()
+	e := setup(t)
+	t.Setenv("VISUAL", "sleep 0.5; sed -i -e 's/= 80/= 30/'")
+	if code, _, stderr := run(t, e, "--timeout", "200ms", "theme", "edit", "C"); code != ctl.ExitOK {
+		t.Fatalf("theme edit: %d %s", code, stderr)
+	}
+	resp, err := e.ThemeGet(ctx, &protocol.ThemeGetRequest{ThemeIndex: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []protocol.ThemeGroup{{GroupNumber: 1, Intensity: 50}, {GroupNumber: 2, Intensity: 30}}; !reflect.DeepEqual(resp.Groups, want) {
+		t.Errorf("theme C is %+v; want %+v", resp.Groups, want)
+	}
+}
+
+func TestThemes(t *testing.T) {
+	ctx := context.Background()
+	e := setup(t)
+	if code, out, _ := run(t, e, "theme", "on", "c"); code != ctl.ExitOK || out != "theme C (Evening) on\n" {
+		t.Fatalf("theme on: %d %q", code, out)
+	}
+	code, out, _ := run(t, e, "theme", "show", "Evening")
+	want := "theme C (Evening) is on\n\n" +
+		"GROUP  NAME        INTENSITY\n" +
+		"1      Front Path  50%\n" +
+		"2      Patio       80%\n"
+	if code != ctl.ExitOK || out != want {
+		t.Fatalf("theme show: %d\n%s\nwant\n%s", code, out, want)
+	}
+	if code, _, stderr := run(t, e, "theme", "edit", "C", "Patio=-", "Trees=30", "1=on"); code != ctl.ExitOK {
+		t.Fatalf("theme edit: %d %s", code, stderr)
+	}
+	resp, err := e.ThemeGet(ctx, &protocol.ThemeGetRequest{ThemeIndex: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantGroups := []protocol.ThemeGroup{{GroupNumber: 1, Intensity: 100}, {GroupNumber: 3, Intensity: 30}}
+	if len(resp.Groups) != len(wantGroups) || resp.Groups[0] != wantGroups[0] || resp.Groups[1] != wantGroups[1] {
+		t.Errorf("after edit, theme C is %+v; want %+v", resp.Groups, wantGroups)
+	}
+	code, out, _ = run(t, e, "--json", "theme", "show", "C")
+	var d ctl.ThemeDetail
+	if err := json.Unmarshal([]byte(out), &d); err != nil || code != ctl.ExitOK {
+		t.Fatalf("theme show --json: %d %q: %v", code, out, err)
+	}
+	if d.Letter != "C" || len(d.Groups) != 2 || d.Groups[1].Name != "Trees" {
+		t.Errorf("theme show --json: %+v", d)
+	}
+	if code, _, _ := run(t, e, "all", "off"); code != ctl.ExitOK {
+		t.Fatalf("all off: %d", code)
+	}
+	groups, err := e.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, g := range groups.GroupList {
+		if g.Intensity != 0 {
+			t.Errorf("after all off, %s is at %d", g.Name, g.Intensity)
+		}
+	}
+}
+
+func TestCall(t *testing.T) {
+	e := setup(t)
+	code, out, _ := run(t, e, "call", "ControllerName", "{}")
+	if code != ctl.ExitOK || !strings.Contains(out, `"Controller": "test"`) {
+		t.Errorf("call: %d %q", code, out)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	e := setup(t)
+	for _, test := range []struct {
+		args []string
+		code int
+		msg  string
+	}{
+		{[]string{}, ctl.ExitUsage, "no command given"},
+		{[]string{"group", "frob"}, ctl.ExitUsage, `unknown command "group frob"`},
+		{[]string{"group", "set", "Patio", "150"}, ctl.ExitUsage, "bad intensity"},
+		{[]string{"group", "set", "Pool", "50"}, ctl.ExitNotFound, `no group "Pool"`},
+		{[]string{"theme", "on", "D"}, ctl.ExitNotFound, "no theme D"},
+		{[]string{"group", "rename", "Patio", "Trees"}, ctl.ExitConflict, "already in use"},
+		{[]string{"call", "ThemeGet", `{"ThemeIndex": 30}`}, ctl.ExitNotFound, "out of range"},
+	} {
+		code, _, stderr := run(t, e, test.args...)
+		if code != test.code || !strings.Contains(stderr, test.msg) {
+			t.Errorf("%q: got %d %q; want %d containing %q", test.args, code, stderr, test.code, test.msg)
+		}
+	}
+
+	// Nothing is listening on a closed server's port.
+	server := httptest.NewServer(&emulator.Handler{Controller: e})
+	server.Close()
+	var stdout, stderr bytes.Buffer
+	isolate(t)
+	code := ctl.Main(context.Background(), []string{"--base_url", server.URL, "groups"}, nil, &stdout, &stderr)
+	if code != ctl.ExitUnreachable || !strings.Contains(stderr.String(), "can't connect") {
+		t.Errorf("closed server: got %d %q", code, stderr.String())
+	}
+}
+
+func TestBatch(t *testing.T) {
+	ctx := context.Background()
+	input := `# Commission the pool zone.
+GroupListAdd {"GroupNumber": 7,
+GroupListAdd {"GroupNumber": 7, "Name": "Pool"}
+
+IlluminateGroup {"GroupNumber": 7, "Intensity": 50}
+GroupListAdd {"GroupNumber": 7, "Name": "Spa"}
+ControllerName
+`
+	readSteps := func(out string) []ctl.Step {
+		t.Helper()
+		var steps []ctl.Step
+		for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+			var s ctl.Step
+			if err := json.Unmarshal([]byte(line), &s); err != nil {
+				t.Fatalf("bad output line %q: %v", line, err)
+			}
+			steps = append(steps, s)
+		}
+		return steps
+	}
+
+	// By default, the batch stops at the first failure.
+	e := setup(t)
+	var out bytes.Buffer
+	err := (&ctl.Batch{Controller: e}).Run(ctx, strings.NewReader(input), &out)
+	if err == nil || !strings.Contains(err.Error(), "step 1 (line 2, GroupListAdd)") {
+		t.Errorf("stopping batch returned %v", err)
+	}
+	if steps := readSteps(out.String()); len(steps) != 1 || steps[0].Status != -1 || steps[0].Error == "" {
+		t.Errorf("stopping batch: %+v", steps)
+	}
+
+	e = setup(t)
+	out.Reset()
+	err = (&ctl.Batch{Controller: e, ContinueOnError: true}).Run(ctx, strings.NewReader(input), &out)
+	if err == nil {
+		t.Errorf("continuing batch returned no error")
+	}
+	steps := readSteps(out.String())
+	var got []int
+	for _, s := range steps {
+		got = append(got, s.Line, s.Status)
+	}
+	want := []int{2, -1, 3, 0, 5, 0, 6, protocol.StatusGroupNumberInUse, 7, 0}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("continuing batch: got lines and statuses %v; want %v", got, want)
+	}
+	if string(steps[1].Request) != `{"GroupNumber":7,"Name":"Pool"}` {
+		t.Errorf("step 2 request %s", steps[1].Request)
+	}
+	groups, err := e.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g := groups.GroupList[len(groups.GroupList)-1]; g.Name != "Pool" || g.Intensity != 50 {
+		t.Errorf("after batch, last group is %+v", g)
+	}
+
+	// The output can be replayed, here through the command line.
+	e = setup(t)
+	path := filepath.Join(t.TempDir(), "steps.jsonl")
+	if err := os.WriteFile(path, out.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+	code, replayed, stderr := run(t, e, "batch", "--continue", "--delay", "1ms", path)
+	if code != ctl.ExitFailure || !strings.Contains(stderr, "bad GroupListAdd request") {
+		t.Errorf("replay exited %d: %s", code, stderr)
+	}
+	if replayedSteps := readSteps(replayed); len(replayedSteps) != 5 || replayedSteps[0].Input != steps[0].Input ||
+		replayedSteps[3].Status != protocol.StatusGroupNumberInUse {
+		t.Errorf("replay: %+v", replayedSteps)
+	}
+}
+
+func TestProfile(t *testing.T) {
+	e := setup(t)
+	server := httptest.NewServer(&emulator.Handler{Controller: e})
+	defer server.Close()
+	isolate(t)
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"Profiles": {"yard": {
+		"BaseURL": "`+server.URL+`/",
+		"GroupAliases": {"walk": "Front Path"},
+		"ThemeAliases": {"dusk": "C"}
+	}}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(config.EnvConfig, path)
+	t.Setenv(config.EnvProfile, "yard")
+	for _, args := range [][]string{
+		{"group", "set", "walk", "20"},
+		{"theme", "on", "dusk"},
+		{"theme", "edit", "dusk", "walk=-"},
+	} {
+		var stdout, stderr bytes.Buffer
+		if code := ctl.Main(context.Background(), args, nil, &stdout, &stderr); code != ctl.ExitOK {
+			t.Fatalf("%q: exit %d: %s", args, code, stderr.String())
+		}
+	}
+	resp, err := e.ThemeGet(context.Background(), &protocol.ThemeGetRequest{ThemeIndex: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Groups) != 1 || resp.Groups[0].GroupNumber != 2 {
+		t.Errorf("after edit through alias, theme C is %+v", resp.Groups)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := ctl.Main(context.Background(), []string{"--profile", "office", "groups"}, nil, &stdout, &stderr); code != ctl.ExitUsage ||
+		!strings.Contains(stderr.String(), `no profile "office"`) {
+		t.Errorf("missing profile: exit %d: %s", code, stderr.String())
+	}
+}
+
+func TestDoctor(t *testing.T) {
+	ctx := context.Background()
+	e := setup(t)
+	if _, err := e.ThemeListAdd(ctx, &protocol.ThemeListAddRequest{ThemeIndex: 2, Name: "Late"}); err != nil {
+		t.Fatal(err)
+	}
+	code, out, _ := run(t, e, "doctor")
+	if code != ctl.ExitFailure || !strings.Contains(out, `error     theme_indexes  theme "Late" shares index C with an earlier theme, so it can't be used [fixable]`) {
+		t.Errorf("doctor: exit %d:\n%s", code, out)
+	}
+
+	// Declining leaves the problem.
+	server := httptest.NewServer(&emulator.Handler{Controller: e})
+	defer server.Close()
+	isolate(t)
+	var stdout, stderr bytes.Buffer
+	code = ctl.Main(ctx, []string{"--base_url", server.URL, "doctor", "--fix"}, strings.NewReader("n\n"), &stdout, &stderr)
+	if code != ctl.ExitFailure || !strings.Contains(stderr.String(), "no fixes applied") {
+		t.Errorf("declined fix: exit %d: %s", code, stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	code = ctl.Main(ctx, []string{"--base_url", server.URL, "doctor", "--fix"}, strings.NewReader("y\n"), &stdout, &stderr)
+	if code != ctl.ExitOK || !strings.Contains(stderr.String(), `applied: move theme "Late" to free index A`) {
+		t.Errorf("accepted fix: exit %d: %s\n%s", code, stderr.String(), stdout.String())
+	}
+}
+
+func TestExport(t *testing.T) {
+	e := setup(t)
+	dir := t.TempDir()
+	metadata := filepath.Join(dir, "site.json")
+	if err := os.WriteFile(metadata, []byte(`{"Groups": {"Patio": {"Fixtures": 4}, "Pool": {}}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	output := filepath.Join(dir, "sheet.csv")
+	code, _, errOut := run(t, e, "export", "--metadata", metadata, "--output", output)
+	if code != ctl.ExitOK || errOut != "note: metadata for group Pool matches nothing on the controller\n" {
+		t.Fatalf("export: exit %d: %s", code, errOut)
+	}
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Number,Name,Order,Intensity,Fixtures,Notes,C: Evening\n" +
+		"1,Front Path,0,0,,,50\n" +
+		"2,Patio,1,0,4,,80\n" +
+		"3,Trees,2,0,,,\n"
+	if string(got) != want {
+		t.Errorf("export CSV:\n%s\nwant:\n%s", got, want)
+	}
+	if code, _, _ := run(t, e, "export", "--format", "pdf"); code != ctl.ExitUsage {
+		t.Errorf("export --format pdf: exit %d", code)
+	}
+}
+
+func TestLoadtest(t *testing.T) {
+	e := setup(t)
+	code, out, errOut := run(t, e, "loadtest", "--concurrency", "2", "--writes", "0.5", "--duration", "20ms")
+	var r loadtest.Result
+	if err := json.Unmarshal([]byte(out), &r); err != nil || code != ctl.ExitOK {
+		t.Fatalf("loadtest: exit %d, %v: %s%s", code, err, out, errOut)
+	}
+	if r.ControllerName != "test" || len(r.Stages) != 1 || r.Total.Calls == 0 || r.Total.Writes == 0 {
+		t.Errorf("unexpected result: %s", out)
+	}
+	if !strings.HasPrefix(errOut, "stage 1 (concurrency 2): ") {
+		t.Errorf("unexpected progress: %s", errOut)
+	}
+	if code, _, _ := run(t, e, "loadtest", "--writes", "2"); code != ctl.ExitUsage {
+		t.Errorf("loadtest --writes 2: exit %d", code)
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	server := httptest.NewServer(&emulator.Handler{Controller: emulator.New("Backyard")})
+	defer server.Close()
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, out, errOut := run(t, setup(t), "--json", "discover", "--port", u.Port(), "127.0.0.0/30")
+	var found []ctl.Discovered
+	if err := json.Unmarshal([]byte(out), &found); err != nil || code != ctl.ExitOK {
+		t.Fatalf("discover: exit %d, %v: %s%s", code, err, out, errOut)
+	}
+	if len(found) != 1 || found[0].Name != "Backyard" || found[0].BaseURL != server.URL+"/" {
+		t.Errorf("discover found %+v", found)
+	}
+	if code, _, _ := run(t, setup(t), "discover", "luxor.local"); code != ctl.ExitUsage {
+		t.Errorf("discover luxor.local: exit %d", code)
+	}
+}
@@ -0,0 +1,81 @@
+package ctl
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/scottlamb/luxor/discovery"
+	"io"
+	"net/netip"
+	"strings"
+	"time"
+)
+
+// Discovered is a controller in the output of "discover".
+type Discovered struct {
+	Name    string
+	BaseURL string
+	Seconds float64 // ControllerName round trip.
+}
+
+func (c *CLI) discover(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("discover", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	s := &discovery.Scanner{}
+	flags.IntVar(&s.Port, "port", discovery.DefaultPort, "")
+	flags.IntVar(&s.Concurrency, "concurrency", discovery.DefaultConcurrency, "")
+	flags.DurationVar(&s.Timeout, "timeout", discovery.DefaultTimeout, "")
+	if err := flags.Parse(args); err != nil {
+		return usageErrorf("discover: %v", err)
+	}
+	var prefixes []netip.Prefix
+	for _, arg := range flags.Args() {
+		p, err := parsePrefix(arg)
+		if err != nil {
+			return err
+		}
+		prefixes = append(prefixes, p)
+	}
+	if len(prefixes) == 0 {
+		var err error
+		if prefixes, err = discovery.LocalPrefixes(); err != nil {
+			return err
+		}
+	}
+	names := make([]string, len(prefixes))
+	for i, p := range prefixes {
+		names[i] = p.String()
+	}
+	fmt.Fprintf(c.Stderr, "scanning %s\n", strings.Join(names, ", "))
+	found, err := s.Scan(ctx, prefixes)
+	out := make([]Discovered, len(found))
+	for i, f := range found {
+		out[i] = Discovered{Name: f.Name, BaseURL: f.BaseURL, Seconds: f.Latency.Seconds()}
+	}
+	if c.JSON {
+		if werr := c.writeJSON(out); werr != nil {
+			return werr
+		}
+	} else if len(found) == 0 {
+		fmt.Fprintf(c.Stderr, "no controllers found\n")
+	} else {
+		w := c.table()
+		fmt.Fprintf(w, "NAME\tBASE URL\tLATENCY\n")
+		for _, f := range found {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", f.Name, f.BaseURL, f.Latency.Round(time.Millisecond))
+		}
+		w.Flush()
+	}
+	return err
+}
+
+// parsePrefix parses a CIDR prefix or a single address.
+func parsePrefix(s string) (netip.Prefix, error) {
+	if p, err := netip.ParsePrefix(s); err == nil {
+		return p.Masked(), nil
+	}
+	if a, err := netip.ParseAddr(s); err == nil {
+		return netip.PrefixFrom(a, a.BitLen()), nil
+	}
+	return netip.Prefix{}, usageErrorf("bad address range %q; want CIDR such as 192.168.1.0/24", s)
+}
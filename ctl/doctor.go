@@ -0,0 +1,127 @@
+package ctl
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"github.com/scottlamb/luxor/doctor"
+	"io"
+	"strings"
+)
+
+// doctorProblemsError reports that doctor found errors, for the exit status.
+type doctorProblemsError struct {
+	n int
+}
+
+func (e *doctorProblemsError) Error() string {
+	return fmt.Sprintf("found %d error(s)", e.n)
+}
+
+func (c *CLI) doctor(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	fix := flags.Bool("fix", false, "")
+	yes := flags.Bool("yes", false, "")
+	if err := flags.Parse(args); err != nil {
+		return usageErrorf("doctor: %v", err)
+	}
+	if flags.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", flags.Arg(0))
+	}
+	d := &doctor.Doctor{Controller: c.Controller}
+	report, err := c.check(ctx, d)
+	if c.JSON {
+		if werr := c.writeJSON(report); werr != nil {
+			return werr
+		}
+	} else {
+		c.printReport(report)
+	}
+	if err != nil {
+		return err
+	}
+	if *fix {
+		if err := c.applyFixes(ctx, report, *yes); err != nil {
+			return err
+		}
+		if report, err = c.check(ctx, d); err != nil {
+			return err
+		}
+		if !c.JSON {
+			fmt.Fprintf(c.Stdout, "\nafter repairs:\n")
+			c.printReport(report)
+		}
+	}
+	n := 0
+	for _, f := range report.Findings {
+		if f.Severity == doctor.Error {
+			n++
+		}
+	}
+	if n > 0 {
+		return &doctorProblemsError{n}
+	}
+	return nil
+}
+
+// check runs d's checks under their own timeout, as doctor's wait for
+// answers isn't bounded.
+func (c *CLI) check(ctx context.Context, d *doctor.Doctor) (*doctor.Report, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	return d.Check(ctx)
+}
+
+func (c *CLI) printReport(r *doctor.Report) {
+	if r.ControllerName != "" {
+		fmt.Fprintf(c.Stdout, "controller %q: %d groups, %d themes\n\n", r.ControllerName, len(r.Groups), len(r.Themes))
+	}
+	w := c.table()
+	fmt.Fprintf(w, "SEVERITY\tCHECK\tFINDING\n")
+	for _, f := range r.Findings {
+		msg := f.Message
+		if f.Fix != nil {
+			msg += " [fixable]"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", f.Severity, f.Check, msg)
+	}
+	w.Flush()
+}
+
+// applyFixes applies each proposed fix, asking first unless yes is set.
+// Answers are read from stdin; prompts go to stderr, so JSON output stays
+// clean.
+func (c *CLI) applyFixes(ctx context.Context, r *doctor.Report, yes bool) error {
+	answers := bufio.NewReader(c.stdin())
+	applied := 0
+	for _, f := range r.Findings {
+		if f.Fix == nil {
+			continue
+		}
+		if !yes {
+			fmt.Fprintf(c.Stderr, "%s\n  fix: %s? [y/N] ", f.Message, f.Fix.Description)
+			answer, err := answers.ReadString('\n')
+			if err != nil && answer == "" {
+				fmt.Fprintln(c.Stderr)
+				break // no more answers; apply nothing further.
+			}
+			if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+				continue
+			}
+		}
+		fixCtx, cancel := c.withTimeout(ctx)
+		err := f.Fix.Apply(fixCtx, c.Controller)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.Fix.Description, err)
+		}
+		applied++
+		fmt.Fprintf(c.Stderr, "applied: %s\n", f.Fix.Description)
+	}
+	if applied == 0 {
+		fmt.Fprintf(c.Stderr, "no fixes applied\n")
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package ctl
+
+import (
+	"errors"
+	"fmt"
+	"github.com/scottlamb/luxor/client"
+	"github.com/scottlamb/luxor/protocol"
+)
+
+// Exit statuses, as returned by ExitCode.
+const (
+	ExitOK          = 0
+	ExitFailure     = 1 // anything not covered below.
+	ExitUsage       = 2 // bad command line.
+	ExitNotFound    = 3 // no such group or theme.
+	ExitConflict    = 4 // name or number already in use.
+	ExitRefused     = 5 // the controller refused the request, as for restricted themes.
+	ExitUnreachable = 6 // couldn't talk to the controller.
+	ExitProtocol    = 7 // the controller didn't understand, or answered strangely.
+)
+
+// usageError is a problem with the command line.
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string { return e.msg }
+
+func usageErrorf(format string, args ...interface{}) error {
+	return &usageError{fmt.Sprintf(format, args...)}
+}
+
+// notFoundError is a group or theme missing from the controller's lists.
+type notFoundError struct {
+	msg string
+}
+
+func (e *notFoundError) Error() string { return e.msg }
+
+// ExitCode maps an error from a command to an exit status.
+func ExitCode(err error) int {
+	var (
+		usage    *usageError
+		notFound *notFoundError
+	)
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.As(err, &usage):
+		return ExitUsage
+	case errors.As(err, &notFound):
+		return ExitNotFound
+	}
+	switch protocol.StatusOf(err) {
+	case protocol.StatusPreconditionFailed, protocol.StatusThemeIndexOutOfRange:
+		return ExitNotFound
+	case protocol.StatusGroupNameInUse, protocol.StatusGroupNumberInUse:
+		return ExitConflict
+	case protocol.StatusInvalidRequest:
+		return ExitRefused
+	case protocol.StatusUnknownMethod, protocol.StatusUnparseableRequest:
+		return ExitProtocol
+	case -1:
+		switch client.ErrorClass(err) {
+		case client.ClassDNS, client.ClassConnect, client.ClassTimeout:
+			return ExitUnreachable
+		case client.ClassHTTP, client.ClassMalformed:
+			return ExitProtocol
+		}
+	}
+	return ExitFailure
+}
+
+// Message explains an error from a command for a human.
+func Message(err error) string {
+	switch protocol.StatusOf(err) {
+	case protocol.StatusPreconditionFailed:
+		return "no such group or theme on the controller"
+	case protocol.StatusThemeIndexOutOfRange:
+		return "theme index out of range; themes are A through Z"
+	case protocol.StatusGroupNameInUse:
+		return "that group name is already in use"
+	case protocol.StatusGroupNumberInUse:
+		return "that group number is already in use"
+	case protocol.StatusInvalidRequest:
+		return "the controller refused the request; themes may be restricted in its setup menu"
+	case protocol.StatusUnknownMethod:
+		return "the controller doesn't support that operation"
+	case protocol.StatusUnparseableRequest:
+		return "the controller couldn't parse the request"
+	case -1:
+		switch client.ErrorClass(err) {
+		case client.ClassDNS:
+			return "can't find the controller; check --base_url (" + err.Error() + ")"
+		case client.ClassConnect:
+			return "can't connect to the controller; is it powered on and on the network? (" + err.Error() + ")"
+		case client.ClassTimeout:
+			return "the controller didn't respond in time"
+		case client.ClassHTTP, client.ClassMalformed:
+			return "unexpected response from the controller: " + err.Error()
+		}
+	}
+	return err.Error()
+}
@@ -0,0 +1,71 @@
+package ctl
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/scottlamb/luxor/installdoc"
+	"io"
+	"os"
+)
+
+func (c *CLI) export(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	format := flags.String("format", "", "")
+	metadataPath := flags.String("metadata", "", "")
+	output := flags.String("output", "", "")
+	if err := flags.Parse(args); err != nil {
+		return usageErrorf("export: %v", err)
+	}
+	if flags.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", flags.Arg(0))
+	}
+	if *format == "" {
+		*format = installdoc.FormatForPath(*output)
+	}
+	if *format == "" {
+		*format = installdoc.FormatMarkdown
+	}
+	if !c.JSON && !validFormat(*format) {
+		return usageErrorf("bad format %q; want one of %q", *format, installdoc.Formats)
+	}
+	var metadata *installdoc.Metadata
+	if *metadataPath != "" {
+		var err error
+		if metadata, err = installdoc.LoadMetadata(*metadataPath); err != nil {
+			return err
+		}
+	}
+	sheet, err := installdoc.Collect(ctx, c.Controller, metadata)
+	if err != nil {
+		return err
+	}
+	for _, u := range sheet.Unmatched {
+		fmt.Fprintf(c.Stderr, "note: metadata for %s matches nothing on the controller\n", u)
+	}
+	if c.JSON {
+		return c.writeJSON(sheet)
+	}
+	if *output == "" || *output == "-" {
+		return sheet.Write(c.Stdout, *format)
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := sheet.Write(f, *format); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func validFormat(format string) bool {
+	for _, f := range installdoc.Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
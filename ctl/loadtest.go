@@ -0,0 +1,57 @@
+package ctl
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/scottlamb/luxor/loadtest"
+	"io"
+)
+
+func (c *CLI) loadtest(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("loadtest", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	t := &loadtest.Test{Controller: c.Controller}
+	flags.Float64Var(&t.Rate, "rate", 0, "")
+	flags.IntVar(&t.Concurrency, "concurrency", 0, "")
+	flags.Float64Var(&t.WriteFraction, "writes", 0, "")
+	flags.IntVar(&t.Stages, "stages", 1, "")
+	flags.Float64Var(&t.Ramp, "ramp", 0, "")
+	flags.DurationVar(&t.StageDuration, "duration", loadtest.DefaultStageDuration, "")
+	flags.DurationVar(&t.CallTimeout, "call_timeout", loadtest.DefaultCallTimeout, "")
+	flags.IntVar(&t.UnresponsiveAfter, "unresponsive_after", loadtest.DefaultUnresponsiveAfter, "")
+	flags.DurationVar(&t.RecoveryTimeout, "recovery", loadtest.DefaultRecoveryTimeout, "")
+	if err := flags.Parse(args); err != nil {
+		return usageErrorf("loadtest: %v", err)
+	}
+	if flags.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", flags.Arg(0))
+	}
+	switch {
+	case t.Rate < 0:
+		return usageErrorf("--rate must not be negative")
+	case t.Concurrency < 0:
+		return usageErrorf("--concurrency must not be negative")
+	case t.WriteFraction < 0 || t.WriteFraction > 1:
+		return usageErrorf("--writes must be a fraction from 0 to 1")
+	}
+	if t.RecoveryTimeout == 0 {
+		t.RecoveryTimeout = -1 // --recovery 0 means not to wait.
+	}
+	t.Progress = func(s *loadtest.Stage) { fmt.Fprintln(c.Stderr, s) }
+	r, err := t.Run(ctx)
+	if err != nil {
+		return err
+	}
+	if err := c.writeJSON(r); err != nil {
+		return err
+	}
+	if u := r.Unresponsive; u != nil {
+		recovery := "didn't recover"
+		if u.Recovered {
+			recovery = fmt.Sprintf("recovered after %.1fs", u.RecoverySeconds)
+		}
+		return fmt.Errorf("controller stopped answering in stage %d after %d calls (%s); %s", u.Stage, u.AfterCalls, u.Error, recovery)
+	}
+	return nil
+}
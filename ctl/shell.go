@@ -0,0 +1,500 @@
+package ctl
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/scottlamb/luxor/protocol"
+	"golang.org/x/term"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MaxHistory is the number of lines of shell history kept.
+const MaxHistory = 1000
+
+// shellHelp describes the shell's additions to the usual commands.
+const shellHelp = `Commands are as for luxorctl, without the "luxorctl". Also:
+  group GROUP        select a group as the current group
+  +N, -N             adjust the current group's intensity by N
+  N                  set the current group's intensity to N
+  history            show command history
+  help               show this help
+  exit               leave the shell (as does ^D)
+Tab completes commands, group names, and theme letters and names.
+`
+
+// shell is an interactive session.
+type shell struct {
+	cli     *CLI
+	term    *term.Terminal // nil if input isn't a terminal.
+	history *history
+	name    string // controller's name, for the prompt.
+
+	// current is the current group, if any. Only its number and name are
+	// kept up to date.
+	current *protocol.Group
+
+	// groups and themes are cached for completion, and refetched after each
+	// command.
+	groups, themes []string
+	namesValid     bool
+}
+
+// DefaultHistoryPath returns where the shell keeps its history by default:
+// $XDG_STATE_HOME/luxorctl/history, or ~/.local/state/luxorctl/history. It
+// returns "" if neither can be determined.
+func DefaultHistoryPath() string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "luxorctl", "history")
+}
+
+func (c *CLI) shell(ctx context.Context, args []string) error {
+	if err := wantArgs(args); err != nil {
+		return err
+	}
+	sub := *c
+	s := &shell{cli: &sub, history: loadHistory(c.HistoryPath)}
+	s.fetchName(ctx)
+
+	f, ok := c.stdin().(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		// Read commands from a pipe or file, without prompts or editing.
+		scanner := bufio.NewScanner(c.stdin())
+		for scanner.Scan() {
+			if !s.exec(ctx, scanner.Text()) {
+				return nil
+			}
+		}
+		return scanner.Err()
+	}
+
+	state, err := term.MakeRaw(int(f.Fd()))
+	if err != nil {
+		return err
+	}
+	defer term.Restore(int(f.Fd()), state)
+	s.term = term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{f, c.Stdout}, s.prompt())
+	if width, height, err := term.GetSize(int(f.Fd())); err == nil && width > 0 {
+		s.term.SetSize(width, height)
+	}
+	s.term.History = s.history
+	s.term.AutoCompleteCallback = s.autoComplete
+	sub.Stdout = s.term
+	sub.Stderr = s.term
+	fmt.Fprintf(s.term, "Connected to %s. Type \"help\" for help.\n", s.name)
+	for {
+		line, err := s.term.ReadLine()
+		if err == io.EOF {
+			return nil
+		} else if err != nil && err != term.ErrPasteIndicator {
+			return err
+		}
+		if !s.exec(ctx, line) {
+			return nil
+		}
+		s.term.SetPrompt(s.prompt())
+	}
+}
+
+func (s *shell) fetchName(ctx context.Context) {
+	ctx, cancel := s.cli.withTimeout(ctx)
+	defer cancel()
+	s.name = "luxor"
+	if resp, err := s.cli.Controller.ControllerName(ctx, &protocol.ControllerNameRequest{}); err != nil {
+		fmt.Fprintf(s.cli.Stderr, "warning: %s\n", Message(err))
+	} else if resp.Controller != "" {
+		s.name = resp.Controller
+	}
+}
+
+func (s *shell) prompt() string {
+	if s.current != nil {
+		return fmt.Sprintf("%s [%s]> ", s.name, s.current.Name)
+	}
+	return s.name + "> "
+}
+
+// exec runs one line of input, returning false if the shell should exit.
+func (s *shell) exec(ctx context.Context, line string) bool {
+	words, _, err := splitWords(line)
+	if err != nil {
+		fmt.Fprintf(s.cli.Stderr, "error: %v\n", err)
+		return true
+	}
+	if len(words) == 0 {
+		return true
+	}
+	switch {
+	case words[0] == "exit" || words[0] == "quit":
+		return false
+	case words[0] == "help":
+		fmt.Fprint(s.cli.Stdout, shellHelp)
+		Usage(s.cli.Stdout)
+	case words[0] == "history":
+		for i := s.history.Len() - 1; i >= 0; i-- {
+			fmt.Fprintf(s.cli.Stdout, "%5d  %s\n", s.history.Len()-i, s.history.At(i))
+		}
+	case words[0] == "shell":
+		err = usageErrorf("already in the shell")
+	case len(words) == 1 && isAdjustment(words[0]):
+		err = s.adjust(ctx, words[0])
+	case len(words) == 2 && words[0] == "group" && words[1] != "set" && words[1] != "rename":
+		err = s.selectGroup(ctx, words[1])
+	default:
+		err = s.cli.Run(ctx, words)
+		if err == nil && len(words) == 4 && words[0] == "group" {
+			// Follow the group just set or renamed.
+			id := words[2]
+			if words[1] == "rename" {
+				id = words[3]
+			}
+			err = s.selectGroup(ctx, id)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(s.cli.Stderr, "error: %s\n", Message(err))
+	}
+	s.namesValid = false
+	return true
+}
+
+// isAdjustment returns true for "+N", "-N", and "N".
+func isAdjustment(word string) bool {
+	_, err := strconv.Atoi(word)
+	return err == nil
+}
+
+func (s *shell) selectGroup(ctx context.Context, id string) error {
+	ctx, cancel := s.cli.withTimeout(ctx)
+	defer cancel()
+	groups, err := s.cli.groups(ctx)
+	if err != nil {
+		return err
+	}
+	g, err := findGroup(groups, s.cli.groupID(id))
+	if err != nil {
+		return err
+	}
+	s.current = g
+	return nil
+}
+
+// adjust sets the current group's intensity, relative to its present
+// intensity if word starts with a sign. The result is clamped to 0-100.
+func (s *shell) adjust(ctx context.Context, word string) error {
+	if s.current == nil {
+		return usageErrorf(`no current group; select one with "group GROUP"`)
+	}
+	n, _ := strconv.Atoi(word)
+	if word[0] == '+' || word[0] == '-' {
+		ctx, cancel := s.cli.withTimeout(ctx)
+		groups, err := s.cli.groups(ctx)
+		cancel()
+		if err != nil {
+			return err
+		}
+		g, err := findGroup(groups, strconv.Itoa(int(s.current.GroupNumber)))
+		if err != nil {
+			return err
+		}
+		n += int(g.Intensity)
+	}
+	if n < 0 {
+		n = 0
+	} else if n > protocol.MaxIntensity {
+		n = protocol.MaxIntensity
+	}
+	return s.cli.Run(ctx, []string{"group", "set", strconv.Itoa(int(s.current.GroupNumber)), strconv.Itoa(n)})
+}
+
+// fetchNames refreshes the names used for completion.
+func (s *shell) fetchNames() {
+	if s.namesValid {
+		return
+	}
+	ctx, cancel := s.cli.withTimeout(context.Background())
+	defer cancel()
+	s.groups, s.themes = nil, nil
+	if groups, err := s.cli.groups(ctx); err == nil {
+		for _, g := range groups {
+			s.groups = append(s.groups, g.Name)
+		}
+	}
+	if themes, err := s.cli.themes(ctx); err == nil {
+		for _, t := range themes {
+			s.themes = append(s.themes, protocol.ThemeLetter(t.ThemeIndex), t.Name)
+		}
+	}
+	if p := s.cli.Profile; p != nil {
+		for alias := range p.GroupAliases {
+			s.groups = append(s.groups, alias)
+		}
+		for alias := range p.ThemeAliases {
+			s.themes = append(s.themes, alias)
+		}
+	}
+	s.namesValid = true
+}
+
+func (s *shell) autoComplete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+	s.fetchNames()
+	newLine, newPos, matches := complete(line, pos, s.groups, s.themes)
+	if newPos == pos && len(matches) > 1 {
+		fmt.Fprintf(s.term, "%s\n", strings.Join(matches, "  "))
+	}
+	return newLine, newPos, true
+}
+
+// complete completes the word before pos in line. It returns the new line
+// and cursor position, and all candidates matching the original word.
+func complete(line string, pos int, groups, themes []string) (string, int, []string) {
+	words, start, err := splitWords(line[:pos])
+	partial := ""
+	if start < pos {
+		partial = words[len(words)-1]
+		words = words[:len(words)-1]
+	} else if err != nil {
+		return line, pos, nil
+	}
+	candidates, suffix := candidatesFor(words, groups, themes)
+	var matches []string
+	seen := make(map[string]bool)
+	for _, c := range candidates {
+		if !seen[c] && len(c) >= len(partial) && strings.EqualFold(c[:len(partial)], partial) {
+			seen[c] = true
+			matches = append(matches, c)
+		}
+	}
+	sort.Strings(matches)
+	var replacement string
+	switch len(matches) {
+	case 0:
+		return line, pos, nil
+	case 1:
+		replacement = quote(matches[0]) + suffix
+	default:
+		prefix := matches[0]
+		for _, m := range matches[1:] {
+			n := 0
+			for n < len(prefix) && n < len(m) && strings.EqualFold(prefix[n:n+1], m[n:n+1]) {
+				n++
+			}
+			prefix = prefix[:n]
+		}
+		if len(prefix) <= len(partial) {
+			return line, pos, matches
+		}
+		replacement = quote(prefix)
+		if strings.HasSuffix(replacement, `"`) {
+			// Leave the quote open, as the word is incomplete.
+			replacement = replacement[:len(replacement)-1]
+		}
+	}
+	return line[:start] + replacement + line[pos:], start + len(replacement), matches
+}
+
+// candidatesFor returns the possible words following words, and what to
+// append after a unique completion.
+func candidatesFor(words []string, groups, themes []string) ([]string, string) {
+	if len(words) == 0 {
+		names := []string{"help", "history", "exit"}
+		for _, c := range commands {
+			names = append(names, c.name)
+		}
+		return names, " "
+	}
+	cmd, n := lookup(words)
+	if cmd == nil || n < len(words) && cmd.run == nil {
+		return nil, ""
+	}
+	if cmd.run == nil {
+		var names []string
+		for _, sub := range cmd.sub {
+			names = append(names, sub.name)
+		}
+		if cmd.name == "group" {
+			names = append(names, groups...)
+		}
+		return names, " "
+	}
+	synopsis := strings.Fields(cmd.args)
+	i := len(words) - n
+	var kind string
+	if i < len(synopsis) {
+		kind = synopsis[i]
+	} else if len(synopsis) > 0 && strings.HasSuffix(synopsis[len(synopsis)-1], "...") {
+		kind = synopsis[len(synopsis)-1]
+	}
+	switch {
+	case strings.Contains(kind, "GROUP="):
+		return groups, "="
+	case kind == "GROUP":
+		return groups, " "
+	case kind == "THEME":
+		return themes, " "
+	case kind == "METHOD":
+		return Methods(), " "
+	}
+	return nil, ""
+}
+
+// splitWords splits a line into words as a POSIX shell would, with single
+// and double quotes and backslash escapes. It also returns the offset at
+// which the last word starts, or len(line) if the line ends between words.
+// An unterminated quote is an error, but the words so far are returned.
+func splitWords(line string) ([]string, int, error) {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		start   = len(line)
+		quoting byte
+	)
+	for i := 0; i < len(line); i++ {
+		ch := line[i]
+		if !inWord && ch != ' ' && ch != '\t' {
+			inWord, start = true, i
+		}
+		switch {
+		case quoting == '\'':
+			if ch == '\'' {
+				quoting = 0
+			} else {
+				word.WriteByte(ch)
+			}
+		case ch == '\\' && i+1 < len(line) && (quoting == 0 || line[i+1] == '"' || line[i+1] == '\\'):
+			i++
+			word.WriteByte(line[i])
+		case quoting == '"':
+			if ch == '"' {
+				quoting = 0
+			} else {
+				word.WriteByte(ch)
+			}
+		case ch == '\'' || ch == '"':
+			quoting = ch
+		case ch == ' ' || ch == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord, start = false, len(line)
+			}
+		default:
+			word.WriteByte(ch)
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	if quoting != 0 {
+		return words, start, fmt.Errorf("unterminated %c quote", quoting)
+	}
+	return words, start, nil
+}
+
+// quote quotes s as a single word for splitWords, if necessary.
+func quote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t'\"\\") {
+		return s
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// history is a term.History which also appends each entry to a file. The
+// file is cut back to MaxHistory entries when loaded.
+type history struct {
+	path    string
+	entries []string // oldest first.
+}
+
+func loadHistory(path string) *history {
+	h := &history{path: path}
+	if path == "" {
+		return h
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return h
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	n := 0
+	for scanner.Scan() {
+		h.append(scanner.Text())
+		n++
+	}
+	if n > MaxHistory {
+		h.rewrite()
+	}
+	return h
+}
+
+// rewrite replaces the file with the entries kept in memory.
+func (h *history) rewrite() {
+	tmp, err := os.CreateTemp(filepath.Dir(h.path), ".history-*")
+	if err != nil {
+		return
+	}
+	w := bufio.NewWriter(tmp)
+	for _, entry := range h.entries {
+		fmt.Fprintln(w, entry)
+	}
+	err = w.Flush()
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), h.path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+func (h *history) append(entry string) {
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > MaxHistory {
+		h.entries = h.entries[len(h.entries)-MaxHistory:]
+	}
+}
+
+func (h *history) Add(entry string) {
+	if entry == "" || (len(h.entries) > 0 && h.entries[len(h.entries)-1] == entry) {
+		return
+	}
+	h.append(entry)
+	if h.path == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0700); err != nil {
+		return
+	}
+	f, err := os.OpenFile(h.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(f, entry)
+	f.Close()
+}
+
+func (h *history) Len() int { return len(h.entries) }
+
+func (h *history) At(i int) string { return h.entries[len(h.entries)-1-i] }
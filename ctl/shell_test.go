@@ -0,0 +1,143 @@
+package ctl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/scottlamb/luxor/emulator"
+	"github.com/scottlamb/luxor/protocol"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitWords(t *testing.T) {
+	for _, test := range []struct {
+		line  string
+		words []string
+		start int
+	}{
+		{`group set "Front Path" 60`, []string{"group", "set", "Front Path", "60"}, 23},
+		{`group set Front\ Path `, []string{"group", "set", "Front Path"}, 22},
+		{`theme edit C 'Front Path'=5 "say \"hi\""=1`, []string{"theme", "edit", "C", "Front Path=5", `say "hi"=1`}, 28},
+	} {
+		words, start, err := splitWords(test.line)
+		if err != nil || !reflect.DeepEqual(words, test.words) || start != test.start {
+			t.Errorf("splitWords(%q) = %q, %d, %v; want %q, %d", test.line, words, start, err, test.words, test.start)
+		}
+	}
+	if _, _, err := splitWords(`group set "Front`); err == nil {
+		t.Errorf("unterminated quote: no error")
+	}
+}
+
+func TestComplete(t *testing.T) {
+	groups := []string{"Front Path", "Front Porch", "Patio"}
+	themes := []string{"A", "Evening", "C", "Party"}
+	for _, test := range []struct {
+		line string
+		want string
+	}{
+		{"gr", "group"},
+		{"group s", "group set "},
+		{"group set pa", "group set Patio "},
+		{"group set fr", `group set "Front P`},
+		{`group set "Front Pa`, `group set "Front Path" `},
+		{"group Pat", "group Patio "},
+		{"theme on e", "theme on Evening "},
+		{"theme edit C Pa", "theme edit C Patio="},
+		{"call ControllerN", "call ControllerName "},
+		{"group set Patio ", "group set Patio "},
+		{"frob ", "frob "},
+	} {
+		got, pos, _ := complete(test.line, len(test.line), groups, themes)
+		if got != test.want || pos != len(test.want) {
+			t.Errorf("complete(%q) = %q, %d; want %q", test.line, got, pos, test.want)
+		}
+	}
+	if _, _, matches := complete("theme on ", 9, groups, themes); len(matches) != 4 {
+		t.Errorf("theme on: matches %q", matches)
+	}
+}
+
+func TestShell(t *testing.T) {
+	ctx := context.Background()
+	e := emulator.New("Back Yard")
+	for _, g := range []protocol.GroupListAddRequest{{GroupNumber: 1, Name: "Front Path"}, {GroupNumber: 2, Name: "Patio"}} {
+		if _, err := e.GroupListAdd(ctx, &g); err != nil {
+			t.Fatal(err)
+		}
+	}
+	input := strings.Join([]string{
+		"+10", // no current group yet.
+		`group "front path"`,
+		"40",
+		"+10",
+		"-70",
+		"group set Patio 95",
+		"+10",
+		"bogus",
+		"exit",
+		"all off", // not reached.
+	}, "\n")
+	var stdout, stderr bytes.Buffer
+	c := &CLI{
+		Controller:  e,
+		Stdin:       strings.NewReader(input),
+		Stdout:      &stdout,
+		Stderr:      &stderr,
+		HistoryPath: filepath.Join(t.TempDir(), "history"),
+	}
+	if err := c.Run(ctx, []string{"shell"}); err != nil {
+		t.Fatal(err)
+	}
+	wantOut := "Front Path (group 1) set to 40%\n" +
+		"Front Path (group 1) set to 50%\n" +
+		"Front Path (group 1) set to 0%\n" +
+		"Patio (group 2) set to 95%\n" +
+		"Patio (group 2) set to 100%\n"
+	if stdout.String() != wantOut {
+		t.Errorf("stdout:\n%s\nwant:\n%s", stdout.String(), wantOut)
+	}
+	wantErr := "error: no current group; select one with \"group GROUP\"\n" +
+		"error: unknown command \"bogus\"\n"
+	if stderr.String() != wantErr {
+		t.Errorf("stderr:\n%s\nwant:\n%s", stderr.String(), wantErr)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "luxorctl", "history")
+	h := loadHistory(path)
+	h.Add("groups")
+	h.Add("theme on C")
+	h.Add("theme on C")
+	h = loadHistory(path)
+	if h.Len() != 2 || h.At(0) != "theme on C" || h.At(1) != "groups" {
+		t.Errorf("history after reload: %q", h.entries)
+	}
+}
+
+func TestHistoryCapped(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	var lines strings.Builder
+	for i := 0; i < MaxHistory+5; i++ {
+		fmt.Fprintf(&lines, "group set %d 50\n", i)
+	}
+	if err := os.WriteFile(path, []byte(lines.String()), 0600); err != nil {
+		t.Fatal(err)
+	}
+	h := loadHistory(path)
+	if h.Len() != MaxHistory || h.At(MaxHistory-1) != "group set 5 50" {
+		t.Errorf("loaded %d entries, oldest %q", h.Len(), h.At(h.Len()-1))
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"); len(got) != MaxHistory || got[0] != "group set 5 50" {
+		t.Errorf("file has %d lines, first %q", len(got), got[0])
+	}
+}
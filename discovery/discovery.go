@@ -0,0 +1,258 @@
+// Package discovery finds controllers on the local network. Controllers
+// usually get their addresses by DHCP and don't announce themselves, so a
+// Scanner probes every address in a range with a ControllerName request and
+// reports those which answer as a controller would.
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/scottlamb/luxor/client"
+	"github.com/scottlamb/luxor/protocol"
+	"iter"
+	"net"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults for Scanner fields.
+const (
+	DefaultConcurrency = 64
+	DefaultTimeout     = time.Second
+	DefaultPort        = 80
+)
+
+// MaxPrefixBits is the shortest IPv4 prefix LocalPrefixes returns. Larger
+// subnets are narrowed to the /24 around the interface's address, as a
+// controller is rarely far from the machine scanning for it and scanning a
+// /16 takes a long time.
+const MaxPrefixBits = 24
+
+// Scanner scans address ranges for controllers.
+type Scanner struct {
+	// Concurrency bounds the probes in flight; it defaults to
+	// DefaultConcurrency.
+	Concurrency int
+
+	// Timeout bounds each probe; it defaults to DefaultTimeout.
+	Timeout time.Duration
+
+	// Port defaults to DefaultPort.
+	Port int
+
+	// Found, if non-nil, is called as each controller is found, possibly
+	// from several goroutines at once.
+	Found func(Controller)
+}
+
+// Controller is a controller found by a scan.
+type Controller struct {
+	Addr    netip.Addr
+	BaseURL string
+	Name    string
+
+	// Latency is the ControllerName round trip.
+	Latency time.Duration
+}
+
+// Scan probes every host address in prefixes, returning the controllers
+// found, ordered by address. It returns an error only if ctx ends before the
+// scan does, along with the controllers found so far.
+func (s *Scanner) Scan(ctx context.Context, prefixes []netip.Prefix) ([]Controller, error) {
+	concurrency := s.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	addrs := make(chan netip.Addr)
+	var (
+		mu    sync.Mutex
+		found []Controller
+		wg    sync.WaitGroup
+	)
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for addr := range addrs {
+				c, ok := s.Probe(ctx, addr)
+				if !ok {
+					continue
+				}
+				if s.Found != nil {
+					s.Found(c)
+				}
+				mu.Lock()
+				found = append(found, c)
+				mu.Unlock()
+			}
+		}()
+	}
+feed:
+	for _, p := range prefixes {
+		for addr := range Hosts(p) {
+			select {
+			case addrs <- addr:
+			case <-ctx.Done():
+				break feed
+			}
+		}
+	}
+	close(addrs)
+	wg.Wait()
+	sort.Slice(found, func(i, j int) bool { return found[i].Addr.Less(found[j].Addr) })
+	return found, ctx.Err()
+}
+
+// Probe checks whether addr is a controller: whether it answers a
+// ControllerName request with a successful response naming the controller.
+func (s *Scanner) Probe(ctx context.Context, addr netip.Addr) (Controller, bool) {
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	port := s.Port
+	if port == 0 {
+		port = DefaultPort
+	}
+	baseURL := "http://" + net.JoinHostPort(addr.String(), strconv.Itoa(port))
+	if port == DefaultPort {
+		baseURL = "http://" + bracket(addr)
+	}
+	c := &client.Controller{BaseURL: baseURL, Timeout: timeout}
+	start := time.Now()
+	resp, err := c.ControllerName(ctx, &protocol.ControllerNameRequest{})
+	if err != nil || resp.Controller == "" {
+		return Controller{}, false
+	}
+	return Controller{Addr: addr, BaseURL: baseURL + "/", Name: resp.Controller, Latency: time.Since(start)}, true
+}
+
+// bracket formats addr for a URL's host.
+func bracket(addr netip.Addr) string {
+	if addr.Is6() {
+		return "[" + addr.String() + "]"
+	}
+	return addr.String()
+}
+
+// Hosts yields the host addresses in p: all of them, except the network and
+// broadcast addresses of an IPv4 prefix shorter than /31.
+func Hosts(p netip.Prefix) iter.Seq[netip.Addr] {
+	return func(yield func(netip.Addr) bool) {
+		p = p.Masked()
+		addr := p.Addr()
+		skipEnds := addr.Is4() && p.Bits() < 31
+		if skipEnds {
+			addr = addr.Next()
+		}
+		for ; addr.IsValid() && p.Contains(addr); addr = addr.Next() {
+			if skipEnds && !p.Contains(addr.Next()) {
+				return // broadcast.
+			}
+			if !yield(addr) {
+				return
+			}
+		}
+	}
+}
+
+// LocalPrefixes returns the IPv4 subnets of the machine's up, non-loopback
+// interfaces, narrowed to at most MaxPrefixBits.
+func LocalPrefixes() ([]netip.Prefix, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	var prefixes []netip.Prefix
+	seen := make(map[netip.Prefix]bool)
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", iface.Name, err)
+		}
+		for _, a := range addrs {
+			ipNet, ok := a.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			p, ok := prefixOf(ipNet)
+			if !ok || seen[p] {
+				continue
+			}
+			seen[p] = true
+			prefixes = append(prefixes, p)
+		}
+	}
+	if len(prefixes) == 0 {
+		return nil, errors.New("no IPv4 interfaces to scan")
+	}
+	return prefixes, nil
+}
+
+// prefixOf converts an interface's IPv4 network, narrowing it to
+// MaxPrefixBits.
+func prefixOf(ipNet *net.IPNet) (netip.Prefix, bool) {
+	ip4 := ipNet.IP.To4()
+	if ip4 == nil {
+		return netip.Prefix{}, false
+	}
+	bits, _ := ipNet.Mask.Size()
+	if bits < MaxPrefixBits {
+		bits = MaxPrefixBits
+	}
+	addr, _ := netip.AddrFromSlice(ip4)
+	if addr.IsLinkLocalUnicast() {
+		return netip.Prefix{}, false
+	}
+	return netip.PrefixFrom(addr, bits).Masked(), true
+}
+
+// Resolver finds a controller by name, for client.Controller.Resolver.
+type Resolver struct {
+	// Scanner, if non-nil, configures the scan.
+	Scanner *Scanner
+
+	// Prefixes are the ranges to scan; they default to LocalPrefixes.
+	Prefixes []netip.Prefix
+}
+
+// Resolve scans for controllers named name, returning the base URL of the
+// only one. It fails rather than choose between several with the same name.
+func (r *Resolver) Resolve(ctx context.Context, name string) (string, error) {
+	s := r.Scanner
+	if s == nil {
+		s = &Scanner{}
+	}
+	prefixes := r.Prefixes
+	if len(prefixes) == 0 {
+		var err error
+		if prefixes, err = LocalPrefixes(); err != nil {
+			return "", err
+		}
+	}
+	found, err := s.Scan(ctx, prefixes)
+	if err != nil {
+		return "", err
+	}
+	var urls []string
+	for _, c := range found {
+		if c.Name == name {
+			urls = append(urls, c.BaseURL)
+		}
+	}
+	switch len(urls) {
+	case 0:
+		return "", fmt.Errorf("no controller named %q in %v", name, prefixes)
+	case 1:
+		return urls[0], nil
+	}
+	return "", fmt.Errorf("%d controllers named %q (%s); not choosing one", len(urls), name, strings.Join(urls, ", "))
+}
@@ -0,0 +1,130 @@
+package discovery_test
+
+import (
+	"context"
+	"github.com/scottlamb/luxor/discovery"
+	"github.com/scottlamb/luxor/emulator"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func port(t *testing.T, server *httptest.Server) int {
+	t.Helper()
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := strconv.Atoi(u.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestScan(t *testing.T) {
+	server := httptest.NewServer(&emulator.Handler{Controller: emulator.New("Backyard")})
+	defer server.Close()
+	var reported []string
+	s := &discovery.Scanner{
+		Port:        port(t, server),
+		Concurrency: 3,
+		Found:       func(c discovery.Controller) { reported = append(reported, c.Name) },
+	}
+	found, err := s.Scan(context.Background(), []netip.Prefix{netip.MustParsePrefix("127.0.0.0/29")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || !reflect.DeepEqual(reported, []string{"Backyard"}) {
+		t.Fatalf("found %+v, reported %q", found, reported)
+	}
+	c := found[0]
+	if c.Addr != netip.MustParseAddr("127.0.0.1") || c.BaseURL != server.URL+"/" || c.Name != "Backyard" || c.Latency <= 0 {
+		t.Errorf("unexpected controller: %+v", c)
+	}
+}
+
+func TestProbeNotController(t *testing.T) {
+	for name, h := range map[string]http.HandlerFunc{
+		"404": http.NotFound,
+		"html": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html>router login</html>"))
+		},
+		"empty JSON": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte("{}"))
+		},
+	} {
+		server := httptest.NewServer(h)
+		s := &discovery.Scanner{Port: port(t, server)}
+		if c, ok := s.Probe(context.Background(), netip.MustParseAddr("127.0.0.1")); ok {
+			t.Errorf("%s: probe found %+v", name, c)
+		}
+		server.Close()
+	}
+}
+
+func TestHosts(t *testing.T) {
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"192.168.1.9/30", []string{"192.168.1.9", "192.168.1.10"}},
+		{"10.0.0.4/31", []string{"10.0.0.4", "10.0.0.5"}},
+		{"10.0.0.4/32", []string{"10.0.0.4"}},
+		{"fd00::/127", []string{"fd00::", "fd00::1"}},
+	}
+	for _, test := range tests {
+		var got []string
+		for a := range discovery.Hosts(netip.MustParsePrefix(test.prefix)) {
+			got = append(got, a.String())
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Hosts(%s) = %q; want %q", test.prefix, got, test.want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	// Controllers on 127.0.0.1 and 127.0.0.2, with the same port.
+	first := httptest.NewServer(&emulator.Handler{Controller: emulator.New("Yard")})
+	defer first.Close()
+	p := port(t, first)
+	l, err := net.Listen("tcp", net.JoinHostPort("127.0.0.2", strconv.Itoa(p)))
+	if err != nil {
+		t.Skipf("can't listen on 127.0.0.2: %v", err)
+	}
+	var h atomic.Pointer[emulator.Handler]
+	h.Store(&emulator.Handler{Controller: emulator.New("Porch")})
+	second := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.Load().ServeHTTP(w, r)
+	}))
+	second.Listener.Close()
+	second.Listener = l
+	second.Start()
+	defer second.Close()
+
+	r := &discovery.Resolver{
+		Scanner:  &discovery.Scanner{Port: p},
+		Prefixes: []netip.Prefix{netip.MustParsePrefix("127.0.0.0/30")},
+	}
+	ctx := context.Background()
+	if url, err := r.Resolve(ctx, "Porch"); err != nil || url != second.URL+"/" {
+		t.Errorf("Resolve(Porch) = %q, %v; want %q", url, err, second.URL+"/")
+	}
+	if url, err := r.Resolve(ctx, "Attic"); err == nil {
+		t.Errorf("Resolve(Attic) = %q; want error", url)
+	}
+	h.Store(&emulator.Handler{Controller: emulator.New("Yard")})
+	if url, err := r.Resolve(ctx, "Yard"); err == nil || !strings.Contains(err.Error(), "2 controllers") {
+		t.Errorf("Resolve(Yard) = %q, %v; want ambiguity error", url, err)
+	}
+}
@@ -0,0 +1,386 @@
+// Package doctor diagnoses a controller's configuration: whether it's
+// reachable and responsive, and whether its groups and themes have drifted
+// into odd states such as duplicate names, duplicate theme indexes, or themes
+// which refer to deleted groups. Some problems come with a Fix which repairs
+// them.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"github.com/scottlamb/luxor/protocol"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Severity is how serious a Finding is.
+type Severity int
+
+const (
+	Info    Severity = iota // worth knowing, but not a problem.
+	Warning                 // likely to confuse people or apps.
+	Error                   // something doesn't work.
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	}
+	return "severity(" + strconv.Itoa(int(s)) + ")"
+}
+
+func (s Severity) MarshalText() ([]byte, error) { return []byte(s.String()), nil }
+
+// Checks, as in Finding.Check.
+const (
+	CheckConnectivity   = "connectivity"
+	CheckLatency        = "latency"
+	CheckControllerName = "controller_name"
+	CheckGroupNames     = "group_names"
+	CheckGroupNumbers   = "group_numbers"
+	CheckThemeNames     = "theme_names"
+	CheckThemeIndexes   = "theme_indexes"
+	CheckThemeGroups    = "theme_groups"
+	CheckUnusedGroups   = "unused_groups"
+	CheckEmptyThemes    = "empty_themes"
+	CheckRestricted     = "restricted"
+)
+
+// DefaultSlowLatency is the round trip beyond which the controller is
+// reported as slow.
+const DefaultSlowLatency = 500 * time.Millisecond
+
+// latencySamples is how many ControllerName calls are timed.
+const latencySamples = 3
+
+// Finding is one observation about the controller.
+type Finding struct {
+	Check    string
+	Severity Severity
+	Message  string
+
+	// Fix, if non-nil, repairs the problem.
+	Fix *Fix `json:",omitempty"`
+}
+
+// Fix is a proposed repair.
+type Fix struct {
+	Description string
+	Apply       func(ctx context.Context, c protocol.Controller) error `json:"-"`
+}
+
+// Report is the result of Check.
+type Report struct {
+	ControllerName string
+	Latency        time.Duration // median of the timed round trips.
+
+	Groups     []protocol.Group
+	Themes     []protocol.Theme
+	Restricted bool
+
+	// ThemeGroups holds each theme's definition, by index.
+	ThemeGroups map[uint8][]protocol.ThemeGroup
+
+	Findings []Finding
+}
+
+// Worst returns the highest severity among the findings, or Info if there
+// are none.
+func (r *Report) Worst() Severity {
+	worst := Info
+	for _, f := range r.Findings {
+		if f.Severity > worst {
+			worst = f.Severity
+		}
+	}
+	return worst
+}
+
+func (r *Report) add(check string, severity Severity, fix *Fix, format string, args ...interface{}) {
+	r.Findings = append(r.Findings, Finding{Check: check, Severity: severity, Message: fmt.Sprintf(format, args...), Fix: fix})
+}
+
+// Doctor checks a controller.
+type Doctor struct {
+	Controller protocol.Controller
+
+	// SlowLatency defaults to DefaultSlowLatency.
+	SlowLatency time.Duration
+}
+
+// Check reads everything from the controller and reports what it finds. The
+// error is non-nil only if the controller can't be reached at all, in which
+// case the report explains.
+func (d *Doctor) Check(ctx context.Context) (*Report, error) {
+	r := &Report{ThemeGroups: make(map[uint8][]protocol.ThemeGroup)}
+	if err := d.checkConnectivity(ctx, r); err != nil {
+		return r, err
+	}
+	groups, err := d.Controller.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	if err != nil {
+		r.add(CheckConnectivity, Error, nil, "GroupListGet failed: %v", err)
+	} else {
+		r.Groups = groups.GroupList
+		checkGroups(r)
+	}
+	themes, err := d.Controller.ThemeListGet(ctx, &protocol.ThemeListGetRequest{})
+	if err != nil {
+		r.add(CheckConnectivity, Error, nil, "ThemeListGet failed: %v", err)
+		return r, nil
+	}
+	r.Themes = themes.ThemeList
+	r.Restricted = themes.Restricted != 0
+	if r.Restricted {
+		r.add(CheckRestricted, Info, nil, "themes are restricted in the controller's setup menu, so theme fixes will fail")
+	}
+	checkThemeNames(r)
+	d.readThemes(ctx, r)
+	if groups != nil {
+		checkThemeGroups(r)
+	}
+	return r, nil
+}
+
+func (d *Doctor) checkConnectivity(ctx context.Context, r *Report) error {
+	var samples []time.Duration
+	for i := 0; i < latencySamples; i++ {
+		start := time.Now()
+		resp, err := d.Controller.ControllerName(ctx, &protocol.ControllerNameRequest{})
+		if err != nil {
+			r.add(CheckConnectivity, Error, nil, "ControllerName failed: %v", err)
+			return err
+		}
+		samples = append(samples, time.Since(start))
+		r.ControllerName = resp.Controller
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	r.Latency = samples[len(samples)/2]
+	slow := d.SlowLatency
+	if slow <= 0 {
+		slow = DefaultSlowLatency
+	}
+	if r.Latency > slow {
+		r.add(CheckLatency, Warning, nil, "round trips take %v; check the wi-fi signal at the controller", r.Latency.Round(time.Millisecond))
+	} else {
+		r.add(CheckLatency, Info, nil, "round trips take %v", r.Latency.Round(time.Millisecond))
+	}
+	if r.ControllerName == "" {
+		r.add(CheckControllerName, Warning, nil, "the controller has no name")
+	}
+	return nil
+}
+
+// uniqueName returns a variant of name, fit to MaxNameLength, which isn't in
+// taken, and adds it to taken.
+func uniqueName(name string, taken map[string]bool) string {
+	for n := 2; ; n++ {
+		suffix := " " + strconv.Itoa(n)
+		base := name
+		if len(base)+len(suffix) > protocol.MaxNameLength {
+			base = base[:protocol.MaxNameLength-len(suffix)]
+		}
+		if candidate := base + suffix; !taken[candidate] {
+			taken[candidate] = true
+			return candidate
+		}
+	}
+}
+
+func checkGroups(r *Report) {
+	names := make(map[string]int)
+	numbers := make(map[uint8]int)
+	taken := make(map[string]bool)
+	for _, g := range r.Groups {
+		names[g.Name]++
+		numbers[g.GroupNumber]++
+		taken[g.Name] = true
+	}
+	reported := make(map[string]bool)
+	for _, g := range r.Groups {
+		if g.Name == "" {
+			r.add(CheckGroupNames, Warning, nil, "group %d has no name", g.GroupNumber)
+			continue
+		}
+		if names[g.Name] < 2 || reported[g.Name] {
+			continue
+		}
+		reported[g.Name] = true
+
+		// Renames apply to the first group with the old name, so renaming
+		// all but one leaves the last with the original.
+		var renames []string
+		for i := 1; i < names[g.Name]; i++ {
+			renames = append(renames, uniqueName(g.Name, taken))
+		}
+		oldName := g.Name
+		r.add(CheckGroupNames, Warning, &Fix{
+			Description: fmt.Sprintf("rename %d of the groups named %q to %q", len(renames), oldName, renames),
+			Apply: func(ctx context.Context, c protocol.Controller) error {
+				for _, newName := range renames {
+					if _, err := c.GroupListRename(ctx, &protocol.GroupListRenameRequest{OldName: oldName, NewName: newName}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}, "%d groups are named %q, so apps can't tell them apart", names[g.Name], g.Name)
+	}
+	reportedNumbers := make(map[uint8]bool)
+	for _, g := range r.Groups {
+		if n := numbers[g.GroupNumber]; n > 1 && !reportedNumbers[g.GroupNumber] {
+			reportedNumbers[g.GroupNumber] = true
+			r.add(CheckGroupNumbers, Error, nil, "%d groups have number %d", n, g.GroupNumber)
+		}
+	}
+}
+
+func checkThemeNames(r *Report) {
+	names := make(map[string]int)
+	indexes := make(map[uint8]int)
+	taken := make(map[string]bool)
+	for _, t := range r.Themes {
+		names[t.Name]++
+		indexes[t.ThemeIndex]++
+		taken[t.Name] = true
+	}
+	reported := make(map[string]bool)
+	for _, t := range r.Themes {
+		if names[t.Name] < 2 || reported[t.Name] {
+			continue
+		}
+		reported[t.Name] = true
+		var renames []string
+		for i := 1; i < names[t.Name]; i++ {
+			renames = append(renames, uniqueName(t.Name, taken))
+		}
+		oldName := t.Name
+		r.add(CheckThemeNames, Warning, &Fix{
+			Description: fmt.Sprintf("rename %d of the themes named %q to %q", len(renames), oldName, renames),
+			Apply: func(ctx context.Context, c protocol.Controller) error {
+				for _, newName := range renames {
+					if _, err := c.ThemeListRename(ctx, &protocol.ThemeListRenameRequest{OldName: oldName, NewName: newName}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}, "%d themes are named %q", names[t.Name], t.Name)
+	}
+
+	// A theme sharing an earlier theme's index can't be read or illuminated;
+	// moving it to a free index (by deleting and re-adding it) makes it
+	// usable again, though its definition must be set afresh.
+	var free []uint8
+	for i := uint8(0); i <= protocol.MaxThemeNumber; i++ {
+		if indexes[i] == 0 {
+			free = append(free, i)
+		}
+	}
+	seen := make(map[uint8]bool)
+	for _, t := range r.Themes {
+		if !seen[t.ThemeIndex] {
+			seen[t.ThemeIndex] = true
+			continue
+		}
+		var fix *Fix
+		if names[t.Name] == 1 && len(free) > 0 {
+			name, to := t.Name, free[0]
+			free = free[1:]
+			fix = &Fix{
+				Description: fmt.Sprintf("move theme %q to free index %s", name, protocol.ThemeLetter(to)),
+				Apply: func(ctx context.Context, c protocol.Controller) error {
+					if _, err := c.ThemeListDelete(ctx, &protocol.ThemeListDeleteRequest{Name: name}); err != nil {
+						return err
+					}
+					_, err := c.ThemeListAdd(ctx, &protocol.ThemeListAddRequest{ThemeIndex: to, Name: name})
+					return err
+				},
+			}
+		}
+		r.add(CheckThemeIndexes, Error, fix, "theme %q shares index %s with an earlier theme, so it can't be used", t.Name, protocol.ThemeLetter(t.ThemeIndex))
+	}
+}
+
+// readThemes reads each distinct theme index's definition.
+func (d *Doctor) readThemes(ctx context.Context, r *Report) {
+	for _, t := range r.Themes {
+		if _, ok := r.ThemeGroups[t.ThemeIndex]; ok {
+			continue
+		}
+		resp, err := d.Controller.ThemeGet(ctx, &protocol.ThemeGetRequest{ThemeIndex: t.ThemeIndex})
+		if err != nil {
+			r.add(CheckThemeGroups, Error, nil, "ThemeGet %s failed: %v", protocol.ThemeLetter(t.ThemeIndex), err)
+			continue
+		}
+		r.ThemeGroups[t.ThemeIndex] = resp.Groups
+	}
+}
+
+// checkThemeGroups looks for theme definitions with problems found by
+// protocol.LintTheme, and groups no theme uses.
+func checkThemeGroups(r *Report) {
+	used := make(map[uint8]bool)
+	seenIndex := make(map[uint8]bool)
+	for _, t := range r.Themes {
+		if seenIndex[t.ThemeIndex] {
+			continue
+		}
+		seenIndex[t.ThemeIndex] = true
+		entries, ok := r.ThemeGroups[t.ThemeIndex]
+		if !ok {
+			continue
+		}
+		if len(entries) == 0 {
+			r.add(CheckEmptyThemes, Info, nil, "theme %s (%q) has no groups", protocol.ThemeLetter(t.ThemeIndex), t.Name)
+			continue
+		}
+
+		canonical, issues := protocol.LintTheme(entries, r.Groups)
+		for _, e := range canonical {
+			used[e.GroupNumber] = true
+		}
+		if len(issues) == 0 {
+			continue
+		}
+		index := t.ThemeIndex
+		fix := &Fix{
+			Description: fmt.Sprintf("redefine theme %s with %d entries instead of %d", protocol.ThemeLetter(index), len(canonical), len(entries)),
+			Apply: func(ctx context.Context, c protocol.Controller) error {
+				_, err := c.ThemeSet(ctx, &protocol.ThemeSetRequest{ThemeIndex: index, Groups: canonical})
+				return err
+			},
+		}
+		byKind := make(map[string][]string)
+		listed := make(map[string]bool)
+		for _, issue := range issues {
+			n := strconv.Itoa(int(issue.GroupNumber))
+			if !listed[issue.Kind+"/"+n] {
+				listed[issue.Kind+"/"+n] = true
+				byKind[issue.Kind] = append(byKind[issue.Kind], n)
+			}
+		}
+		var problems []string
+		if g := byKind[protocol.IssueUnknownGroup]; len(g) > 0 {
+			problems = append(problems, "refers to missing group(s) "+strings.Join(g, ", "))
+		}
+		if g := byKind[protocol.IssueDuplicateGroup]; len(g) > 0 {
+			problems = append(problems, "lists group(s) "+strings.Join(g, ", ")+" more than once")
+		}
+		if g := byKind[protocol.IssueIntensity]; len(g) > 0 {
+			problems = append(problems, fmt.Sprintf("sets group(s) %s above %d%%", strings.Join(g, ", "), protocol.MaxIntensity))
+		}
+		r.add(CheckThemeGroups, Warning, fix, "theme %s (%q) %s", protocol.ThemeLetter(index), t.Name, strings.Join(problems, " and "))
+	}
+	for _, g := range r.Groups {
+		if !used[g.GroupNumber] {
+			r.add(CheckUnusedGroups, Info, nil, "group %d (%q) isn't in any theme", g.GroupNumber, g.Name)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package doctor_test
+
+import (
+	"context"
+	"github.com/scottlamb/luxor/client"
+	"github.com/scottlamb/luxor/doctor"
+	"github.com/scottlamb/luxor/emulator"
+	"github.com/scottlamb/luxor/protocol"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// messy returns a controller with one of each problem doctor can fix.
+func messy(t *testing.T) *emulator.Controller {
+	t.Helper()
+	ctx := context.Background()
+	e := emulator.New("yard")
+	for _, g := range []protocol.GroupListAddRequest{
+		{GroupNumber: 1, Name: "Path"},
+		{GroupNumber: 2, Name: "Patio"},
+		{GroupNumber: 3, Name: "Trees"},
+	} {
+		if _, err := e.GroupListAdd(ctx, &g); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, th := range []protocol.ThemeListAddRequest{
+		{ThemeIndex: 0, Name: "Evening"},
+		{ThemeIndex: 1, Name: "Evening"},
+		{ThemeIndex: 1, Name: "Party"},
+		{ThemeIndex: 2, Name: "Empty"},
+	} {
+		if _, err := e.ThemeListAdd(ctx, &th); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := e.ThemeSet(ctx, &protocol.ThemeSetRequest{ThemeIndex: 0, Groups: []protocol.ThemeGroup{
+		{GroupNumber: 1, Intensity: 10},
+		{GroupNumber: 9, Intensity: 50}, // no such group.
+		{GroupNumber: 1, Intensity: 20}, // repeated.
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.ThemeSet(ctx, &protocol.ThemeSetRequest{ThemeIndex: 1, Groups: []protocol.ThemeGroup{
+		{GroupNumber: 2, Intensity: 80},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func findings(r *doctor.Report, severity doctor.Severity) []string {
+	var out []string
+	for _, f := range r.Findings {
+		if f.Severity == severity {
+			out = append(out, f.Check+": "+f.Message)
+		}
+	}
+	return out
+}
+
+func TestCheckAndFix(t *testing.T) {
+	ctx := context.Background()
+	e := messy(t)
+	d := &doctor.Doctor{Controller: e}
+	r, err := d.Check(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.ControllerName != "yard" || len(r.Groups) != 3 || len(r.Themes) != 4 {
+		t.Errorf("report: %+v", r)
+	}
+	wantWarnings := []string{
+		`theme_names: 2 themes are named "Evening"`,
+		`theme_groups: theme A ("Evening") refers to missing group(s) 9 and lists group(s) 1 more than once`,
+	}
+	if got := findings(r, doctor.Warning); strings.Join(got, "\n") != strings.Join(wantWarnings, "\n") {
+		t.Errorf("warnings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(wantWarnings, "\n"))
+	}
+	wantErrors := []string{`theme_indexes: theme "Party" shares index B with an earlier theme, so it can't be used`}
+	if got := findings(r, doctor.Error); strings.Join(got, "\n") != strings.Join(wantErrors, "\n") {
+		t.Errorf("errors:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(wantErrors, "\n"))
+	}
+	info := strings.Join(findings(r, doctor.Info), "\n")
+	for _, want := range []string{`empty_themes: theme C ("Empty") has no groups`, `unused_groups: group 3 ("Trees") isn't in any theme`} {
+		if !strings.Contains(info, want) {
+			t.Errorf("info lacks %q:\n%s", want, info)
+		}
+	}
+	if r.Worst() != doctor.Error {
+		t.Errorf("Worst() = %v", r.Worst())
+	}
+
+	for _, f := range r.Findings {
+		if f.Fix != nil {
+			if err := f.Fix.Apply(ctx, e); err != nil {
+				t.Fatalf("%s: %v", f.Fix.Description, err)
+			}
+		}
+	}
+	r, err = d.Check(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Worst() != doctor.Info {
+		t.Errorf("after fixes, still have:\n%s\n%s", strings.Join(findings(r, doctor.Error), "\n"), strings.Join(findings(r, doctor.Warning), "\n"))
+	}
+	themes, err := e.ThemeListGet(ctx, &protocol.ThemeListGetRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, th := range themes.ThemeList {
+		names = append(names, protocol.ThemeLetter(th.ThemeIndex)+"="+th.Name)
+	}
+	if got, want := strings.Join(names, " "), "A=Evening 2 B=Evening C=Empty D=Party"; got != want {
+		t.Errorf("themes after fixes: %s; want %s", got, want)
+	}
+	groups, err := e.ThemeGet(ctx, &protocol.ThemeGetRequest{ThemeIndex: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups.Groups) != 1 || groups.Groups[0] != (protocol.ThemeGroup{GroupNumber: 1, Intensity: 20}) {
+		t.Errorf("theme A after fixes: %+v", groups.Groups)
+	}
+}
+
+func TestUnreachable(t *testing.T) {
+	server := httptest.NewServer(&emulator.Handler{Controller: emulator.New("gone")})
+	server.Close()
+	d := &doctor.Doctor{Controller: &client.Controller{BaseURL: server.URL}}
+	r, err := d.Check(context.Background())
+	if err == nil {
+		t.Fatal("no error")
+	}
+	if got := findings(r, doctor.Error); len(got) != 1 || !strings.HasPrefix(got[0], "connectivity: ControllerName failed") {
+		t.Errorf("errors: %q", got)
+	}
+}
@@ -0,0 +1,479 @@
+// Package emulator is an in-memory implementation of protocol.Controller.
+// It mimics the Luxor ZD wi-fi module's documented behavior closely enough
+// for tests and demos, without any lights attached. NewZDC returns one which
+// also mimics the ZDC's color support.
+package emulator
+
+import (
+	"context"
+	"github.com/scottlamb/luxor/protocol"
+	"sort"
+	"sync"
+)
+
+// IlluminateAllIntensity is the intensity IlluminateAll sets each group to.
+const IlluminateAllIntensity = 75
+
+type theme struct {
+	protocol.Theme
+	groups []protocol.ThemeGroup
+}
+
+// *Controller implements protocol.Controller. The zero value is a controller
+// with an empty name, no groups, and no themes. It is safe for concurrent use.
+type Controller struct {
+	mu          sync.Mutex
+	name        string
+	restricted  bool
+	flash       bool
+	intensities [256]uint8
+	groups      []protocol.Group // Intensity and Color are not maintained here.
+	themes      []theme
+
+	// zdc is true for a ZDC controller, which supports colors.
+	zdc     bool
+	colors  [256]uint8
+	palette []protocol.Color // by ColorNumber.
+}
+
+// New returns a ZD controller with the given name.
+func New(name string) *Controller {
+	return &Controller{name: name}
+}
+
+// NewZDC returns a ZDC controller with the given name and an empty palette.
+func NewZDC(name string) *Controller {
+	return &Controller{name: name, zdc: true}
+}
+
+// SetRestricted sets whether themes are restricted, as through the
+// controller's setup menu.
+func (c *Controller) SetRestricted(restricted bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.restricted = restricted
+}
+
+func truncate(name string) string {
+	if len(name) > protocol.MaxNameLength {
+		return name[:protocol.MaxNameLength]
+	}
+	return name
+}
+
+func (c *Controller) groupByName(name string) int {
+	for i := range c.groups {
+		if c.groups[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (c *Controller) themeByName(name string) int {
+	for i := range c.themes {
+		if c.themes[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// themeByIndex returns the position of the theme with the given index, or a
+// non-zero status.
+func (c *Controller) themeByIndex(index uint8) (int, int) {
+	if index > protocol.MaxThemeNumber {
+		return -1, protocol.StatusThemeIndexOutOfRange
+	}
+	for i := range c.themes {
+		if c.themes[i].ThemeIndex == index {
+			return i, protocol.StatusOk
+		}
+	}
+	return -1, protocol.StatusPreconditionFailed
+}
+
+func (c *Controller) groupList() []protocol.Group {
+	l := make([]protocol.Group, len(c.groups))
+	for i, g := range c.groups {
+		l[i] = protocol.Group{GroupNumber: g.GroupNumber, Intensity: c.intensities[g.GroupNumber], Name: g.Name, Type: g.Type}
+		if g.Type == protocol.GroupTypeColor {
+			l[i].Color = c.colors[g.GroupNumber]
+		}
+	}
+	return l
+}
+
+// colorByNumber returns the position of the palette color with the given
+// number, or -1.
+func (c *Controller) colorByNumber(number uint8) int {
+	i := sort.Search(len(c.palette), func(i int) bool { return c.palette[i].ColorNumber >= number })
+	if i < len(c.palette) && c.palette[i].ColorNumber == number {
+		return i
+	}
+	return -1
+}
+
+func (c *Controller) AssignLight(ctx context.Context, req *protocol.AssignLightRequest) (*protocol.AssignLightResponse, error) {
+	resp := &protocol.AssignLightResponse{}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) ControllerName(ctx context.Context, req *protocol.ControllerNameRequest) (*protocol.ControllerNameResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp := &protocol.ControllerNameResponse{Controller: c.name}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) ExtinguishAll(ctx context.Context, req *protocol.ExtinguishAllRequest) (*protocol.ExtinguishAllResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.intensities = [256]uint8{}
+	for i := range c.themes {
+		c.themes[i].OnOff = 0
+	}
+	c.flash = false
+	resp := &protocol.ExtinguishAllResponse{}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) FlashLights(ctx context.Context, req *protocol.FlashLightsRequest) (*protocol.FlashLightsResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.flash && req.OnOff == 0 {
+		c.intensities = [256]uint8{}
+	}
+	c.flash = req.OnOff != 0
+	resp := &protocol.FlashLightsResponse{}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) GroupListAdd(ctx context.Context, req *protocol.GroupListAddRequest) (*protocol.GroupListAddResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp := &protocol.GroupListAddResponse{}
+	name := truncate(req.Name)
+	for _, g := range c.groups {
+		if g.GroupNumber == req.GroupNumber {
+			resp.Status = protocol.StatusGroupNumberInUse
+			return resp, protocol.ErrorForStatus(resp.Status)
+		}
+	}
+	if c.groupByName(name) >= 0 {
+		resp.Status = protocol.StatusGroupNameInUse
+		return resp, protocol.ErrorForStatus(resp.Status)
+	}
+	g := protocol.Group{GroupNumber: req.GroupNumber, Name: name}
+	if c.zdc && req.Type == protocol.GroupTypeColor {
+		g.Type = protocol.GroupTypeColor
+	}
+	c.groups = append(c.groups, g)
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) GroupListClear(ctx context.Context, req *protocol.GroupListClearRequest) (*protocol.GroupListClearResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.groups = nil
+	resp := &protocol.GroupListClearResponse{GroupList: []protocol.Group{}}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) GroupListDelete(ctx context.Context, req *protocol.GroupListDeleteRequest) (*protocol.GroupListDeleteResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp := &protocol.GroupListDeleteResponse{}
+	i := c.groupByName(req.Name)
+	if i < 0 {
+		resp.Status = protocol.StatusPreconditionFailed
+		return resp, protocol.ErrorForStatus(resp.Status)
+	}
+	c.groups = append(c.groups[:i], c.groups[i+1:]...)
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) GroupListGet(ctx context.Context, req *protocol.GroupListGetRequest) (*protocol.GroupListGetResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp := &protocol.GroupListGetResponse{GroupList: c.groupList()}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) GroupListRename(ctx context.Context, req *protocol.GroupListRenameRequest) (*protocol.GroupListRenameResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp := &protocol.GroupListRenameResponse{}
+	i := c.groupByName(req.OldName)
+	if i < 0 {
+		resp.Status = protocol.StatusPreconditionFailed
+		return resp, protocol.ErrorForStatus(resp.Status)
+	}
+	newName := truncate(req.NewName)
+	if j := c.groupByName(newName); j >= 0 && j != i {
+		resp.Status = protocol.StatusGroupNameInUse
+		return resp, protocol.ErrorForStatus(resp.Status)
+	}
+	c.groups[i].Name = newName
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) GroupListReorder(ctx context.Context, req *protocol.GroupListReorderRequest) (*protocol.GroupListReorderResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp := &protocol.GroupListReorderResponse{}
+	if len(req.GroupNumbers) != len(c.groups) {
+		resp.Status = protocol.StatusPreconditionFailed
+		return resp, protocol.ErrorForStatus(resp.Status)
+	}
+	byNumber := make(map[uint8]protocol.Group, len(c.groups))
+	for _, g := range c.groups {
+		byNumber[g.GroupNumber] = g
+	}
+	reordered := make([]protocol.Group, 0, len(c.groups))
+	for _, n := range req.GroupNumbers {
+		g, ok := byNumber[n]
+		if !ok {
+			resp.Status = protocol.StatusPreconditionFailed
+			return resp, protocol.ErrorForStatus(resp.Status)
+		}
+		delete(byNumber, n)
+		reordered = append(reordered, g)
+	}
+	c.groups = reordered
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) IlluminateAll(ctx context.Context, req *protocol.IlluminateAllRequest) (*protocol.IlluminateAllResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, g := range c.groups {
+		c.intensities[g.GroupNumber] = IlluminateAllIntensity
+	}
+	resp := &protocol.IlluminateAllResponse{}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) IlluminateGroup(ctx context.Context, req *protocol.IlluminateGroupRequest) (*protocol.IlluminateGroupResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp := &protocol.IlluminateGroupResponse{}
+	if c.zdc && req.Color != 0 {
+		if c.colorByNumber(req.Color) < 0 {
+			resp.Status = protocol.StatusPreconditionFailed
+			return resp, protocol.ErrorForStatus(resp.Status)
+		}
+		c.colors[req.GroupNumber] = req.Color
+	}
+	c.intensities[req.GroupNumber] = req.Intensity
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) IlluminateTheme(ctx context.Context, req *protocol.IlluminateThemeRequest) (*protocol.IlluminateThemeResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp := &protocol.IlluminateThemeResponse{}
+	i, status := c.themeByIndex(req.ThemeIndex)
+	if status != protocol.StatusOk {
+		resp.Status = status
+		return resp, protocol.ErrorForStatus(resp.Status)
+	}
+	t := &c.themes[i]
+	for _, g := range t.groups {
+		if req.OnOff != 0 {
+			c.intensities[g.GroupNumber] = g.Intensity
+			if c.zdc && g.Color != 0 {
+				c.colors[g.GroupNumber] = g.Color
+			}
+		} else {
+			c.intensities[g.GroupNumber] = 0
+		}
+	}
+	if req.OnOff != 0 {
+		t.OnOff = 1
+	} else {
+		t.OnOff = 0
+	}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) ThemeClear(ctx context.Context, req *protocol.ThemeClearRequest) (*protocol.ThemeClearResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp := &protocol.ThemeClearResponse{}
+	i, status := c.themeByIndex(req.ThemeIndex)
+	if status != protocol.StatusOk {
+		resp.Status = status
+		return resp, protocol.ErrorForStatus(resp.Status)
+	}
+	c.themes[i].groups = nil
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) ThemeGet(ctx context.Context, req *protocol.ThemeGetRequest) (*protocol.ThemeGetResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp := &protocol.ThemeGetResponse{}
+	i, status := c.themeByIndex(req.ThemeIndex)
+	if status != protocol.StatusOk {
+		resp.Status = status
+		return resp, protocol.ErrorForStatus(resp.Status)
+	}
+	resp.Groups = append([]protocol.ThemeGroup{}, c.themes[i].groups...)
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) ThemeListAdd(ctx context.Context, req *protocol.ThemeListAddRequest) (*protocol.ThemeListAddResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp := &protocol.ThemeListAddResponse{}
+	if c.restricted {
+		resp.Status = protocol.StatusInvalidRequest
+		return resp, protocol.ErrorForStatus(resp.Status)
+	}
+	if req.ThemeIndex > protocol.MaxThemeNumber {
+		resp.Status = protocol.StatusThemeIndexOutOfRange
+		return resp, protocol.ErrorForStatus(resp.Status)
+	}
+
+	// The real controller allows duplicate names and indexes; so does this.
+	c.themes = append(c.themes, theme{Theme: protocol.Theme{Name: truncate(req.Name), ThemeIndex: req.ThemeIndex}})
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) ThemeListClear(ctx context.Context, req *protocol.ThemeListClearRequest) (*protocol.ThemeListClearResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp := &protocol.ThemeListClearResponse{}
+	if c.restricted {
+		resp.Status = protocol.StatusInvalidRequest
+		return resp, protocol.ErrorForStatus(resp.Status)
+	}
+	c.themes = nil
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) ThemeListDelete(ctx context.Context, req *protocol.ThemeListDeleteRequest) (*protocol.ThemeListDeleteResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp := &protocol.ThemeListDeleteResponse{}
+	if c.restricted {
+		resp.Status = protocol.StatusInvalidRequest
+		return resp, protocol.ErrorForStatus(resp.Status)
+	}
+	i := c.themeByName(req.Name)
+	if i < 0 {
+		resp.Status = protocol.StatusPreconditionFailed
+		return resp, protocol.ErrorForStatus(resp.Status)
+	}
+	c.themes = append(c.themes[:i], c.themes[i+1:]...)
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) ThemeListGet(ctx context.Context, req *protocol.ThemeListGetRequest) (*protocol.ThemeListGetResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp := &protocol.ThemeListGetResponse{ThemeList: make([]protocol.Theme, len(c.themes))}
+	if c.restricted {
+		resp.Restricted = 1
+	}
+	for i, t := range c.themes {
+		resp.ThemeList[i] = t.Theme
+	}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) ThemeListRename(ctx context.Context, req *protocol.ThemeListRenameRequest) (*protocol.ThemeListRenameResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp := &protocol.ThemeListRenameResponse{}
+	i := c.themeByName(req.OldName)
+	if i < 0 {
+		resp.Status = protocol.StatusPreconditionFailed
+		return resp, protocol.ErrorForStatus(resp.Status)
+	}
+	c.themes[i].Name = truncate(req.NewName)
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) ThemeListReorder(ctx context.Context, req *protocol.ThemeListReorderRequest) (*protocol.ThemeListReorderResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp := &protocol.ThemeListReorderResponse{}
+	if len(req.ThemeIndexes) != len(c.themes) {
+		resp.Status = protocol.StatusPreconditionFailed
+		return resp, protocol.ErrorForStatus(resp.Status)
+	}
+	byIndex := make(map[uint8]theme, len(c.themes))
+	for _, t := range c.themes {
+		byIndex[t.ThemeIndex] = t
+	}
+	reordered := make([]theme, 0, len(c.themes))
+	for _, index := range req.ThemeIndexes {
+		t, ok := byIndex[index]
+		if !ok {
+			resp.Status = protocol.StatusPreconditionFailed
+			return resp, protocol.ErrorForStatus(resp.Status)
+		}
+		delete(byIndex, index)
+		reordered = append(reordered, t)
+	}
+	c.themes = reordered
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) ThemeSet(ctx context.Context, req *protocol.ThemeSetRequest) (*protocol.ThemeSetResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp := &protocol.ThemeSetResponse{}
+	i, status := c.themeByIndex(req.ThemeIndex)
+	if status != protocol.StatusOk {
+		resp.Status = status
+		return resp, protocol.ErrorForStatus(resp.Status)
+	}
+	groups := append([]protocol.ThemeGroup{}, req.Groups...)
+	if !c.zdc {
+		for j := range groups {
+			groups[j].Color = 0
+		}
+	}
+	c.themes[i].groups = groups
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) ColorListGet(ctx context.Context, req *protocol.ColorListGetRequest) (*protocol.ColorListGetResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp := &protocol.ColorListGetResponse{}
+	if !c.zdc {
+		resp.Status = protocol.StatusUnknownMethod
+		return resp, protocol.ErrorForStatus(resp.Status)
+	}
+	resp.ColorList = append([]protocol.Color{}, c.palette...)
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) ColorListSet(ctx context.Context, req *protocol.ColorListSetRequest) (*protocol.ColorListSetResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp := &protocol.ColorListSetResponse{}
+	if !c.zdc {
+		resp.Status = protocol.StatusUnknownMethod
+		return resp, protocol.ErrorForStatus(resp.Status)
+	}
+	if req.ColorNumber == 0 || req.Hue > protocol.MaxHue || req.Saturation > protocol.MaxSaturation {
+		resp.Status = protocol.StatusInvalidRequest
+		return resp, protocol.ErrorForStatus(resp.Status)
+	}
+	color := protocol.Color{ColorNumber: req.ColorNumber, Hue: req.Hue, Saturation: req.Saturation}
+	if i := c.colorByNumber(req.ColorNumber); i >= 0 {
+		c.palette[i] = color
+	} else {
+		c.palette = append(c.palette, color)
+		sort.Slice(c.palette, func(i, j int) bool { return c.palette[i].ColorNumber < c.palette[j].ColorNumber })
+	}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+// Ensure *Controller implements protocol.ColorController.
+var _ protocol.ColorController = (*Controller)(nil)
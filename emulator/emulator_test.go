@@ -0,0 +1,219 @@
+package emulator_test
+
+import (
+	"context"
+	"github.com/scottlamb/luxor/client"
+	"github.com/scottlamb/luxor/emulator"
+	"github.com/scottlamb/luxor/protocol"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGroups(t *testing.T) {
+	ctx := context.Background()
+	c := emulator.New("test")
+	if _, err := c.GroupListAdd(ctx, &protocol.GroupListAddRequest{GroupNumber: 1, Name: "Front Path"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GroupListAdd(ctx, &protocol.GroupListAddRequest{GroupNumber: 2, Name: "an extremely long name for a group"}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := c.GroupListAdd(ctx, &protocol.GroupListAddRequest{GroupNumber: 1, Name: "Other"})
+	if resp.Status != protocol.StatusGroupNumberInUse || err == nil {
+		t.Errorf("expected number in use; got %+v, %v", resp, err)
+	}
+	resp, err = c.GroupListAdd(ctx, &protocol.GroupListAddRequest{GroupNumber: 3, Name: "Front Path"})
+	if resp.Status != protocol.StatusGroupNameInUse || err == nil {
+		t.Errorf("expected name in use; got %+v, %v", resp, err)
+	}
+	if _, err := c.IlluminateGroup(ctx, &protocol.IlluminateGroupRequest{GroupNumber: 2, Intensity: 40}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GroupListReorder(ctx, &protocol.GroupListReorderRequest{GroupNumbers: []uint8{2, 2}}); err == nil {
+		t.Error("reorder with duplicate should fail")
+	}
+	if _, err := c.GroupListReorder(ctx, &protocol.GroupListReorderRequest{GroupNumbers: []uint8{2, 1}}); err != nil {
+		t.Fatal(err)
+	}
+	list, err := c.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []protocol.Group{
+		{GroupNumber: 2, Intensity: 40, Name: "an extremely long n"},
+		{GroupNumber: 1, Intensity: 0, Name: "Front Path"},
+	}
+	if len(list.GroupList) != len(want) || list.GroupList[0] != want[0] || list.GroupList[1] != want[1] {
+		t.Errorf("expected %+v; got %+v", want, list.GroupList)
+	}
+}
+
+func TestThemes(t *testing.T) {
+	ctx := context.Background()
+	c := emulator.New("test")
+	if _, err := c.ThemeListAdd(ctx, &protocol.ThemeListAddRequest{ThemeIndex: 26, Name: "Z+1"}); err == nil ||
+		!strings.Contains(err.Error(), "out of range") {
+		t.Errorf("expected out of range; got %v", err)
+	}
+	if _, err := c.ThemeListAdd(ctx, &protocol.ThemeListAddRequest{ThemeIndex: 2, Name: "Party"}); err != nil {
+		t.Fatal(err)
+	}
+	groups := []protocol.ThemeGroup{{GroupNumber: 1, Intensity: 10}, {GroupNumber: 1, Intensity: 20}, {GroupNumber: 4, Intensity: 80}}
+	if _, err := c.ThemeSet(ctx, &protocol.ThemeSetRequest{ThemeIndex: 2, Groups: groups}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ThemeSet(ctx, &protocol.ThemeSetRequest{ThemeIndex: 3}); err == nil {
+		t.Error("ThemeSet of missing theme should fail")
+	}
+	if _, err := c.IlluminateTheme(ctx, &protocol.IlluminateThemeRequest{ThemeIndex: 2, OnOff: 1}); err != nil {
+		t.Fatal(err)
+	}
+	list, err := c.ThemeListGet(ctx, &protocol.ThemeListGetRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.ThemeList) != 1 || list.ThemeList[0] != (protocol.Theme{Name: "Party", ThemeIndex: 2, OnOff: 1}) {
+		t.Errorf("unexpected theme list %+v", list.ThemeList)
+	}
+
+	// Last tuple wins.
+	if _, err := c.GroupListAdd(ctx, &protocol.GroupListAddRequest{GroupNumber: 1, Name: "One"}); err != nil {
+		t.Fatal(err)
+	}
+	g, err := c.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.GroupList[0].Intensity != 20 {
+		t.Errorf("expected intensity 20; got %+v", g.GroupList)
+	}
+
+	if _, err := c.ExtinguishAll(ctx, &protocol.ExtinguishAllRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	list, err = c.ThemeListGet(ctx, &protocol.ThemeListGetRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.ThemeList[0].OnOff != 0 {
+		t.Errorf("theme should be off after ExtinguishAll; got %+v", list.ThemeList)
+	}
+
+	c.SetRestricted(true)
+	resp, err := c.ThemeListDelete(ctx, &protocol.ThemeListDeleteRequest{Name: "Party"})
+	if resp.Status != protocol.StatusInvalidRequest || err == nil {
+		t.Errorf("expected invalid request while restricted; got %+v, %v", resp, err)
+	}
+}
+
+func TestColors(t *testing.T) {
+	ctx := context.Background()
+	zd := emulator.New("test")
+	if _, err := zd.ColorListGet(ctx, &protocol.ColorListGetRequest{}); protocol.StatusOf(err) != protocol.StatusUnknownMethod {
+		t.Errorf("ZD ColorListGet: expected unknown method; got %v", err)
+	}
+
+	c := emulator.NewZDC("test")
+	if _, err := c.ColorListSet(ctx, &protocol.ColorListSetRequest{ColorNumber: 0, Hue: 10}); protocol.StatusOf(err) != protocol.StatusInvalidRequest {
+		t.Errorf("ColorListSet of color 0: expected invalid request; got %v", err)
+	}
+	for _, color := range []protocol.ColorListSetRequest{{ColorNumber: 7, Hue: 240, Saturation: 100}, {ColorNumber: 2, Hue: 0, Saturation: 80}} {
+		if _, err := c.ColorListSet(ctx, &color); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := c.GroupListAdd(ctx, &protocol.GroupListAddRequest{GroupNumber: 1, Name: "Path"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GroupListAdd(ctx, &protocol.GroupListAddRequest{GroupNumber: 2, Name: "Trees", Type: protocol.GroupTypeColor}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.IlluminateGroup(ctx, &protocol.IlluminateGroupRequest{GroupNumber: 2, Intensity: 50, Color: 9}); protocol.StatusOf(err) != protocol.StatusPreconditionFailed {
+		t.Errorf("IlluminateGroup with undefined color: expected precondition failed; got %v", err)
+	}
+	if _, err := c.ThemeListAdd(ctx, &protocol.ThemeListAddRequest{ThemeIndex: 7, Name: "Holiday"}); err != nil {
+		t.Fatal(err)
+	}
+	theme := []protocol.ThemeGroup{{GroupNumber: 1, Intensity: 30, Color: 2}, {GroupNumber: 2, Intensity: 90, Color: 2}}
+	if _, err := c.ThemeSet(ctx, &protocol.ThemeSetRequest{ThemeIndex: 7, Groups: theme}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.IlluminateTheme(ctx, &protocol.IlluminateThemeRequest{ThemeIndex: 7, OnOff: 1}); err != nil {
+		t.Fatal(err)
+	}
+	colors, err := c.ColorListGet(ctx, &protocol.ColorListGetRequest{})
+	if err != nil || len(colors.ColorList) != 2 || colors.ColorList[0].ColorNumber != 2 || colors.ColorList[1].Hue != 240 {
+		t.Errorf("ColorListGet: got %+v, %v", colors, err)
+	}
+	list, err := c.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []protocol.Group{
+		{GroupNumber: 1, Intensity: 30, Name: "Path"},
+		{GroupNumber: 2, Intensity: 90, Name: "Trees", Type: protocol.GroupTypeColor, Color: 2},
+	}
+	if len(list.GroupList) != len(want) || list.GroupList[0] != want[0] || list.GroupList[1] != want[1] {
+		t.Errorf("expected %+v; got %+v", want, list.GroupList)
+	}
+}
+
+// nilController returns neither a response nor an error from ControllerName.
+type nilController struct {
+	protocol.Controller
+}
+
+func (nilController) ControllerName(ctx context.Context, req *protocol.ControllerNameRequest) (*protocol.ControllerNameResponse, error) {
+	return nil, nil
+}
+
+func TestHandler(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(&emulator.Handler{Controller: emulator.New("luxor")})
+	defer server.Close()
+	c := &client.Controller{BaseURL: server.URL}
+	name, err := c.ControllerName(ctx, &protocol.ControllerNameRequest{})
+	if err != nil || name.Controller != "luxor" {
+		t.Errorf("unexpected ControllerName result %+v, %v", name, err)
+	}
+	if _, err := c.GroupListAdd(ctx, &protocol.GroupListAddRequest{GroupNumber: 1, Name: "One"}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := c.GroupListAdd(ctx, &protocol.GroupListAddRequest{GroupNumber: 1, Name: "Two"})
+	if protocol.StatusOf(err) != protocol.StatusGroupNumberInUse || resp.Status != protocol.StatusGroupNumberInUse {
+		t.Errorf("expected number in use; got %+v, %v", resp, err)
+	}
+	groups, err := c.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	if err != nil || len(groups.GroupList) != 1 || groups.GroupList[0].Name != "One" {
+		t.Errorf("unexpected GroupListGet result %+v, %v", groups, err)
+	}
+
+	for path, want := range map[string]string{
+		"/Bogus.json":        `{"Status":1}`,
+		"/GroupListAdd.json": `{"Status":101}`,
+	} {
+		httpResp, err := http.Post(server.URL+path, "application/json", strings.NewReader("{"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(httpResp.Body)
+		httpResp.Body.Close()
+		if strings.TrimSpace(string(body)) != want {
+			t.Errorf("%s: expected %s; got %s", path, want, body)
+		}
+	}
+
+	nilServer := httptest.NewServer(&emulator.Handler{Controller: nilController{emulator.New("luxor")}})
+	defer nilServer.Close()
+	httpResp, err := http.Post(nilServer.URL+"/ControllerName.json", "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusBadGateway {
+		t.Errorf("ControllerName returning (nil, nil): expected 502; got %v", httpResp.Status)
+	}
+}
@@ -0,0 +1,83 @@
+package emulator
+
+import (
+	"encoding/json"
+	"github.com/scottlamb/luxor/protocol"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+var (
+	typeOfController      = reflect.TypeOf((*protocol.Controller)(nil)).Elem()
+	typeOfColorController = reflect.TypeOf((*protocol.ColorController)(nil)).Elem()
+)
+
+// Handler serves any protocol.Controller using the wi-fi module's
+// JSON-over-HTTP protocol, so that a client.Controller can talk to it. Each
+// method is a POST to /Method.json; application-level failures are reported
+// through the response's Status field, as the real module does. The
+// protocol.ColorController methods are served if Controller implements them.
+type Handler struct {
+	Controller protocol.Controller
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "POST required", http.StatusMethodNotAllowed)
+		return
+	}
+	name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/"), ".json")
+	if !h.serves(name) || !strings.HasSuffix(r.URL.Path, ".json") {
+		writeJSON(w, map[string]int{"Status": protocol.StatusUnknownMethod})
+		return
+	}
+	method := reflect.ValueOf(h.Controller).MethodByName(name)
+	request := reflect.New(method.Type().In(1).Elem())
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, request.Interface()); err != nil {
+			writeJSON(w, map[string]int{"Status": protocol.StatusUnparseableRequest})
+			return
+		}
+	}
+	out := method.Call([]reflect.Value{reflect.ValueOf(r.Context()), request})
+	if !out[0].IsNil() {
+		writeJSON(w, out[0].Interface())
+		return
+	}
+	if out[1].IsNil() {
+		// A buggy Controller; there's nothing to send back.
+		http.Error(w, name+" returned neither response nor error", http.StatusBadGateway)
+		return
+	}
+	err = out[1].Interface().(error)
+	status := protocol.StatusOf(err)
+	if status < 0 {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	writeJSON(w, map[string]int{"Status": status})
+}
+
+// serves reports whether name is a protocol method h.Controller implements.
+func (h *Handler) serves(name string) bool {
+	if _, ok := typeOfController.MethodByName(name); ok {
+		return true
+	}
+	if _, ok := h.Controller.(protocol.ColorController); ok {
+		_, ok = typeOfColorController.MethodByName(name)
+		return ok
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,134 @@
+// Package exporter provides Prometheus metrics for a Luxor controller: its
+// lighting state, gathered through protocol.Controller on each scrape, and
+// per-RPC latency and errors, gathered by observing a client.Controller.
+package exporter
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scottlamb/luxor/client"
+	"github.com/scottlamb/luxor/protocol"
+	"strconv"
+	"time"
+)
+
+// DefaultTimeout bounds the RPCs made by a single scrape.
+const DefaultTimeout = 10 * time.Second
+
+var (
+	upDesc = prometheus.NewDesc(
+		"luxor_up",
+		"Whether the controller answered the last scrape's RPCs.",
+		nil, nil)
+	groupIntensityDesc = prometheus.NewDesc(
+		"luxor_group_intensity",
+		"Current intensity of each group, in [0, 100].",
+		[]string{"group_number", "name"}, nil)
+	themeOnDesc = prometheus.NewDesc(
+		"luxor_theme_on",
+		"Whether each theme is on, per the last IlluminateTheme or ExtinguishAll.",
+		[]string{"theme_index", "letter", "name"}, nil)
+)
+
+// Collector is a prometheus.Collector which polls a controller's groups and
+// themes on each scrape.
+type Collector struct {
+	controller protocol.Controller
+	timeout    time.Duration
+}
+
+// NewCollector returns a collector for the given controller. Each scrape's
+// RPCs are bounded by timeout, or DefaultTimeout if it is zero.
+func NewCollector(controller protocol.Controller, timeout time.Duration) *Collector {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Collector{controller: controller, timeout: timeout}
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- upDesc
+	ch <- groupIntensityDesc
+	ch <- themeOnDesc
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	up := 1.0
+	if groups, err := c.controller.GroupListGet(ctx, &protocol.GroupListGetRequest{}); err != nil {
+		up = 0
+	} else {
+		for _, g := range groups.GroupList {
+			ch <- prometheus.MustNewConstMetric(groupIntensityDesc, prometheus.GaugeValue, float64(g.Intensity),
+				strconv.Itoa(int(g.GroupNumber)), g.Name)
+		}
+	}
+	if themes, err := c.controller.ThemeListGet(ctx, &protocol.ThemeListGetRequest{}); err != nil {
+		up = 0
+	} else {
+		// The controller allows duplicate themes, but a registry rejects
+		// duplicate series; report the first of each.
+		seen := make(map[protocol.Theme]bool)
+		for _, t := range themes.ThemeList {
+			key := protocol.Theme{Name: t.Name, ThemeIndex: t.ThemeIndex}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			var on float64
+			if t.OnOff != 0 {
+				on = 1
+			}
+			ch <- prometheus.MustNewConstMetric(themeOnDesc, prometheus.GaugeValue, on,
+				strconv.Itoa(int(t.ThemeIndex)), protocol.ThemeLetter(t.ThemeIndex), t.Name)
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, up)
+}
+
+// RPCMetrics implements client.Observer, recording each RPC's latency and
+// errors.
+type RPCMetrics struct {
+	duration        *prometheus.HistogramVec
+	statusErrors    *prometheus.CounterVec
+	transportErrors *prometheus.CounterVec
+}
+
+// NewRPCMetrics creates RPC metrics and registers them with reg.
+func NewRPCMetrics(reg prometheus.Registerer) *RPCMetrics {
+	m := &RPCMetrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "luxor_rpc_duration_seconds",
+			Help: "Latency of controller RPCs, including failed ones.",
+
+			// The wi-fi module is slow; responses take tens to hundreds of
+			// milliseconds when it's healthy.
+			Buckets: []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		}, []string{"method"}),
+		statusErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "luxor_rpc_status_errors_total",
+			Help: "RPCs which the controller answered with a non-zero status.",
+		}, []string{"method", "status"}),
+		transportErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "luxor_rpc_transport_errors_total",
+			Help: "RPCs which failed before a valid response was received, by error class.",
+		}, []string{"method", "class"}),
+	}
+	reg.MustRegister(m.duration, m.statusErrors, m.transportErrors)
+	return m
+}
+
+func (m *RPCMetrics) ObserveRPC(method string, elapsed time.Duration, status int, err error) {
+	m.duration.WithLabelValues(method).Observe(elapsed.Seconds())
+	switch class := client.ErrorClass(err); class {
+	case client.ClassNone:
+	case client.ClassStatus:
+		m.statusErrors.WithLabelValues(method, strconv.Itoa(status)).Inc()
+	default:
+		m.transportErrors.WithLabelValues(method, class).Inc()
+	}
+}
+
+// Ensure *RPCMetrics implements client.Observer.
+var _ client.Observer = (*RPCMetrics)(nil)
@@ -0,0 +1,73 @@
+package exporter_test
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/scottlamb/luxor/client"
+	"github.com/scottlamb/luxor/emulator"
+	"github.com/scottlamb/luxor/exporter"
+	"github.com/scottlamb/luxor/protocol"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExporter(t *testing.T) {
+	ctx := context.Background()
+	e := emulator.New("luxor")
+	e.GroupListAdd(ctx, &protocol.GroupListAddRequest{GroupNumber: 3, Name: "Front Path"})
+	e.IlluminateGroup(ctx, &protocol.IlluminateGroupRequest{GroupNumber: 3, Intensity: 60})
+	e.ThemeListAdd(ctx, &protocol.ThemeListAddRequest{ThemeIndex: 2, Name: "Party"})
+	e.ThemeListAdd(ctx, &protocol.ThemeListAddRequest{ThemeIndex: 2, Name: "Party"}) // duplicate.
+	e.IlluminateTheme(ctx, &protocol.IlluminateThemeRequest{ThemeIndex: 2, OnOff: 1})
+	server := httptest.NewServer(&emulator.Handler{Controller: e})
+	defer server.Close()
+
+	reg := prometheus.NewPedanticRegistry()
+	c := &client.Controller{BaseURL: server.URL, Observer: exporter.NewRPCMetrics(reg)}
+	reg.MustRegister(exporter.NewCollector(c, 0))
+	err := testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP luxor_group_intensity Current intensity of each group, in [0, 100].
+# TYPE luxor_group_intensity gauge
+luxor_group_intensity{group_number="3",name="Front Path"} 60
+# HELP luxor_theme_on Whether each theme is on, per the last IlluminateTheme or ExtinguishAll.
+# TYPE luxor_theme_on gauge
+luxor_theme_on{letter="C",name="Party",theme_index="2"} 1
+# HELP luxor_up Whether the controller answered the last scrape's RPCs.
+# TYPE luxor_up gauge
+luxor_up 1
+`), "luxor_group_intensity", "luxor_theme_on", "luxor_up")
+	if err != nil {
+		t.Error(err)
+	}
+
+	// The scrape's own RPCs were observed.
+	if n := testutil.CollectAndCount(reg, "luxor_rpc_duration_seconds"); n != 2 {
+		t.Errorf("expected histograms for 2 methods; got %d", n)
+	}
+
+	// Status and transport errors are counted separately.
+	c.ThemeGet(ctx, &protocol.ThemeGetRequest{ThemeIndex: 30})
+	server.Close()
+	err = testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP luxor_up Whether the controller answered the last scrape's RPCs.
+# TYPE luxor_up gauge
+luxor_up 0
+`), "luxor_up")
+	if err != nil {
+		t.Error(err)
+	}
+	err = testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP luxor_rpc_status_errors_total RPCs which the controller answered with a non-zero status.
+# TYPE luxor_rpc_status_errors_total counter
+luxor_rpc_status_errors_total{method="ThemeGet",status="243"} 1
+# HELP luxor_rpc_transport_errors_total RPCs which failed before a valid response was received, by error class.
+# TYPE luxor_rpc_transport_errors_total counter
+luxor_rpc_transport_errors_total{class="connect",method="GroupListGet"} 1
+luxor_rpc_transport_errors_total{class="connect",method="ThemeListGet"} 1
+`), "luxor_rpc_status_errors_total", "luxor_rpc_transport_errors_total")
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,474 @@
+// Package health tracks whether a controller is responding and stops callers
+// from piling up behind one which isn't.
+//
+// A Breaker wraps a protocol.Controller and counts consecutive failed calls.
+// After TripAfter of them it opens: calls fail immediately with ErrOpen rather
+// than each waiting for its own timeout. Once Cooldown has passed, the next
+// call first probes the controller with a ControllerName request; if that
+// succeeds the breaker closes and the call proceeds, and otherwise it stays
+// open for another Cooldown.
+//
+// Only failures to reach the controller count. A status error (such as a
+// request for a missing group) means the controller answered, and calls
+// abandoned by their callers say nothing about the controller.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/scottlamb/luxor/protocol"
+	"sync"
+	"time"
+)
+
+// Defaults for Breaker fields.
+const (
+	DefaultTripAfter = 3
+	DefaultCooldown  = 30 * time.Second
+)
+
+// ErrOpen is returned (wrapped) by calls refused while the breaker is open.
+var ErrOpen = errors.New("controller unavailable")
+
+// State is a Breaker's state.
+type State int
+
+const (
+	// Closed passes calls to the controller.
+	Closed State = iota
+
+	// Open refuses calls until the cooldown passes.
+	Open
+
+	// Probing refuses calls while a ControllerName probe is in flight.
+	Probing
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case Probing:
+		return "probing"
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// Status is a snapshot of a Breaker's view of the controller.
+type Status struct {
+	State State
+
+	// Failures is the number of consecutive failed calls.
+	Failures int
+
+	// LastSuccess is when a call last succeeded; zero if none has.
+	LastSuccess time.Time
+
+	// LastError is the most recent failure, or nil if the last call
+	// succeeded.
+	LastError error
+
+	// OpenUntil is when an open breaker will next probe the controller.
+	OpenUntil time.Time
+}
+
+// Healthy reports whether the breaker is closed.
+func (s *Status) Healthy() bool { return s.State == Closed }
+
+// Breaker is a protocol.Controller which tracks the health of the controller
+// it wraps and fails calls fast while it's down.
+type Breaker struct {
+	protocol.Controller
+
+	// TripAfter is the number of consecutive failures which open the
+	// breaker; it defaults to DefaultTripAfter.
+	TripAfter int
+
+	// Cooldown is how long the breaker stays open before probing; it
+	// defaults to DefaultCooldown.
+	Cooldown time.Duration
+
+	mu       sync.Mutex
+	status   Status
+	watchers map[chan Status]bool
+}
+
+// Status returns the breaker's current status.
+func (b *Breaker) Status() Status {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.status
+}
+
+// Healthy reports whether calls are passing through to the controller.
+func (b *Breaker) Healthy() bool {
+	s := b.Status()
+	return s.Healthy()
+}
+
+// Watch returns a channel which receives the breaker's status each time its
+// state changes. Should the receiver fall behind, it gets only the latest
+// status. cancel stops delivery; the channel isn't closed.
+func (b *Breaker) Watch() (changes <-chan Status, cancel func()) {
+	ch := make(chan Status, 1)
+	b.mu.Lock()
+	if b.watchers == nil {
+		b.watchers = make(map[chan Status]bool)
+	}
+	b.watchers[ch] = true
+	b.mu.Unlock()
+	return ch, func() {
+		b.mu.Lock()
+		delete(b.watchers, ch)
+		b.mu.Unlock()
+	}
+}
+
+// setStateLocked changes state, notifying watchers. b.mu must be held, which
+// also makes the drain-then-send below safe: no other sender can fill the
+// channel in between.
+func (b *Breaker) setStateLocked(state State) {
+	if b.status.State == state {
+		return
+	}
+	b.status.State = state
+	for ch := range b.watchers {
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- b.status
+	}
+}
+
+// allow decides whether a call may proceed, probing the controller if the
+// cooldown has passed.
+func (b *Breaker) allow(ctx context.Context) error {
+	b.mu.Lock()
+	switch b.status.State {
+	case Closed:
+		b.mu.Unlock()
+		return nil
+	case Open:
+		if time.Now().Before(b.status.OpenUntil) {
+			err := b.openErrorLocked()
+			b.mu.Unlock()
+			return err
+		}
+		b.setStateLocked(Probing)
+		b.mu.Unlock()
+		return b.probe(ctx)
+	}
+	err := b.openErrorLocked()
+	b.mu.Unlock()
+	return err
+}
+
+func (b *Breaker) openErrorLocked() error {
+	return fmt.Errorf("%w after %d failures (retrying at %s): %v",
+		ErrOpen, b.status.Failures, b.status.OpenUntil.Format(time.TimeOnly), b.status.LastError)
+}
+
+// probe checks the controller is back with a cheap ControllerName call.
+func (b *Breaker) probe(ctx context.Context) error {
+	_, err := b.Controller.ControllerName(ctx, &protocol.ControllerNameRequest{})
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch {
+	case answered(err):
+		b.succeedLocked()
+		return nil
+	case abandoned(ctx, err):
+		// Let the next call probe instead.
+		b.status.OpenUntil = time.Now()
+		b.setStateLocked(Open)
+	default:
+		b.failLocked(err)
+	}
+	return b.openErrorLocked()
+}
+
+// answered reports whether a call's outcome shows the controller responding.
+func answered(err error) bool {
+	return err == nil || protocol.StatusOf(err) > 0
+}
+
+// abandoned reports whether a call failed because its caller gave up, which
+// says nothing about the controller.
+func abandoned(ctx context.Context, err error) bool {
+	return errors.Is(err, context.Canceled) && ctx.Err() != nil
+}
+
+// observe records a call's outcome.
+func (b *Breaker) observe(ctx context.Context, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch {
+	case answered(err):
+		b.succeedLocked()
+	case !abandoned(ctx, err):
+		b.failLocked(err)
+	}
+}
+
+func (b *Breaker) succeedLocked() {
+	b.status.Failures = 0
+	b.status.LastError = nil
+	b.status.LastSuccess = time.Now()
+	b.status.OpenUntil = time.Time{}
+	b.setStateLocked(Closed)
+}
+
+func (b *Breaker) failLocked(err error) {
+	b.status.Failures++
+	b.status.LastError = err
+	tripAfter := b.TripAfter
+	if tripAfter <= 0 {
+		tripAfter = DefaultTripAfter
+	}
+	if b.status.State == Closed && b.status.Failures < tripAfter {
+		return
+	}
+	cooldown := b.Cooldown
+	if cooldown <= 0 {
+		cooldown = DefaultCooldown
+	}
+	b.status.OpenUntil = time.Now().Add(cooldown)
+	b.setStateLocked(Open)
+}
+
+func (b *Breaker) AssignLight(ctx context.Context, req *protocol.AssignLightRequest) (*protocol.AssignLightResponse, error) {
+	if err := b.allow(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := b.Controller.AssignLight(ctx, req)
+	b.observe(ctx, err)
+	return resp, err
+}
+
+func (b *Breaker) ControllerName(ctx context.Context, req *protocol.ControllerNameRequest) (*protocol.ControllerNameResponse, error) {
+	if err := b.allow(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := b.Controller.ControllerName(ctx, req)
+	b.observe(ctx, err)
+	return resp, err
+}
+
+func (b *Breaker) ExtinguishAll(ctx context.Context, req *protocol.ExtinguishAllRequest) (*protocol.ExtinguishAllResponse, error) {
+	if err := b.allow(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := b.Controller.ExtinguishAll(ctx, req)
+	b.observe(ctx, err)
+	return resp, err
+}
+
+func (b *Breaker) FlashLights(ctx context.Context, req *protocol.FlashLightsRequest) (*protocol.FlashLightsResponse, error) {
+	if err := b.allow(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := b.Controller.FlashLights(ctx, req)
+	b.observe(ctx, err)
+	return resp, err
+}
+
+func (b *Breaker) GroupListAdd(ctx context.Context, req *protocol.GroupListAddRequest) (*protocol.GroupListAddResponse, error) {
+	if err := b.allow(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := b.Controller.GroupListAdd(ctx, req)
+	b.observe(ctx, err)
+	return resp, err
+}
+
+func (b *Breaker) GroupListClear(ctx context.Context, req *protocol.GroupListClearRequest) (*protocol.GroupListClearResponse, error) {
+	if err := b.allow(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := b.Controller.GroupListClear(ctx, req)
+	b.observe(ctx, err)
+	return resp, err
+}
+
+func (b *Breaker) GroupListDelete(ctx context.Context, req *protocol.GroupListDeleteRequest) (*protocol.GroupListDeleteResponse, error) {
+	if err := b.allow(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := b.Controller.GroupListDelete(ctx, req)
+	b.observe(ctx, err)
+	return resp, err
+}
+
+func (b *Breaker) GroupListGet(ctx context.Context, req *protocol.GroupListGetRequest) (*protocol.GroupListGetResponse, error) {
+	if err := b.allow(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := b.Controller.GroupListGet(ctx, req)
+	b.observe(ctx, err)
+	return resp, err
+}
+
+func (b *Breaker) GroupListRename(ctx context.Context, req *protocol.GroupListRenameRequest) (*protocol.GroupListRenameResponse, error) {
+	if err := b.allow(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := b.Controller.GroupListRename(ctx, req)
+	b.observe(ctx, err)
+	return resp, err
+}
+
+func (b *Breaker) GroupListReorder(ctx context.Context, req *protocol.GroupListReorderRequest) (*protocol.GroupListReorderResponse, error) {
+	if err := b.allow(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := b.Controller.GroupListReorder(ctx, req)
+	b.observe(ctx, err)
+	return resp, err
+}
+
+func (b *Breaker) IlluminateAll(ctx context.Context, req *protocol.IlluminateAllRequest) (*protocol.IlluminateAllResponse, error) {
+	if err := b.allow(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := b.Controller.IlluminateAll(ctx, req)
+	b.observe(ctx, err)
+	return resp, err
+}
+
+func (b *Breaker) IlluminateGroup(ctx context.Context, req *protocol.IlluminateGroupRequest) (*protocol.IlluminateGroupResponse, error) {
+	if err := b.allow(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := b.Controller.IlluminateGroup(ctx, req)
+	b.observe(ctx, err)
+	return resp, err
+}
+
+func (b *Breaker) IlluminateTheme(ctx context.Context, req *protocol.IlluminateThemeRequest) (*protocol.IlluminateThemeResponse, error) {
+	if err := b.allow(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := b.Controller.IlluminateTheme(ctx, req)
+	b.observe(ctx, err)
+	return resp, err
+}
+
+func (b *Breaker) ThemeClear(ctx context.Context, req *protocol.ThemeClearRequest) (*protocol.ThemeClearResponse, error) {
+	if err := b.allow(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := b.Controller.ThemeClear(ctx, req)
+	b.observe(ctx, err)
+	return resp, err
+}
+
+func (b *Breaker) ThemeGet(ctx context.Context, req *protocol.ThemeGetRequest) (*protocol.ThemeGetResponse, error) {
+	if err := b.allow(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := b.Controller.ThemeGet(ctx, req)
+	b.observe(ctx, err)
+	return resp, err
+}
+
+func (b *Breaker) ThemeListAdd(ctx context.Context, req *protocol.ThemeListAddRequest) (*protocol.ThemeListAddResponse, error) {
+	if err := b.allow(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := b.Controller.ThemeListAdd(ctx, req)
+	b.observe(ctx, err)
+	return resp, err
+}
+
+func (b *Breaker) ThemeListClear(ctx context.Context, req *protocol.ThemeListClearRequest) (*protocol.ThemeListClearResponse, error) {
+	if err := b.allow(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := b.Controller.ThemeListClear(ctx, req)
+	b.observe(ctx, err)
+	return resp, err
+}
+
+func (b *Breaker) ThemeListDelete(ctx context.Context, req *protocol.ThemeListDeleteRequest) (*protocol.ThemeListDeleteResponse, error) {
+	if err := b.allow(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := b.Controller.ThemeListDelete(ctx, req)
+	b.observe(ctx, err)
+	return resp, err
+}
+
+func (b *Breaker) ThemeListGet(ctx context.Context, req *protocol.ThemeListGetRequest) (*protocol.ThemeListGetResponse, error) {
+	if err := b.allow(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := b.Controller.ThemeListGet(ctx, req)
+	b.observe(ctx, err)
+	return resp, err
+}
+
+func (b *Breaker) ThemeListRename(ctx context.Context, req *protocol.ThemeListRenameRequest) (*protocol.ThemeListRenameResponse, error) {
+	if err := b.allow(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := b.Controller.ThemeListRename(ctx, req)
+	b.observe(ctx, err)
+	return resp, err
+}
+
+func (b *Breaker) ThemeListReorder(ctx context.Context, req *protocol.ThemeListReorderRequest) (*protocol.ThemeListReorderResponse, error) {
+	if err := b.allow(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := b.Controller.ThemeListReorder(ctx, req)
+	b.observe(ctx, err)
+	return resp, err
+}
+
+func (b *Breaker) ThemeSet(ctx context.Context, req *protocol.ThemeSetRequest) (*protocol.ThemeSetResponse, error) {
+	if err := b.allow(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := b.Controller.ThemeSet(ctx, req)
+	b.observe(ctx, err)
+	return resp, err
+}
+
+// ColorListGet forwards to the wrapped controller if it implements
+// protocol.ColorController, and otherwise reports StatusUnknownMethod without
+// a call.
+func (b *Breaker) ColorListGet(ctx context.Context, req *protocol.ColorListGetRequest) (*protocol.ColorListGetResponse, error) {
+	c, ok := b.Controller.(protocol.ColorController)
+	if !ok {
+		resp := &protocol.ColorListGetResponse{Status: protocol.StatusUnknownMethod}
+		return resp, protocol.ErrorForStatus(resp.Status)
+	}
+	if err := b.allow(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := c.ColorListGet(ctx, req)
+	b.observe(ctx, err)
+	return resp, err
+}
+
+// ColorListSet is as ColorListGet.
+func (b *Breaker) ColorListSet(ctx context.Context, req *protocol.ColorListSetRequest) (*protocol.ColorListSetResponse, error) {
+	c, ok := b.Controller.(protocol.ColorController)
+	if !ok {
+		resp := &protocol.ColorListSetResponse{Status: protocol.StatusUnknownMethod}
+		return resp, protocol.ErrorForStatus(resp.Status)
+	}
+	if err := b.allow(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := c.ColorListSet(ctx, req)
+	b.observe(ctx, err)
+	return resp, err
+}
+
+// Ensure *Breaker implements protocol.ColorController.
+var _ protocol.ColorController = (*Breaker)(nil)
@@ -0,0 +1,163 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"github.com/scottlamb/luxor/emulator"
+	"github.com/scottlamb/luxor/health"
+	"github.com/scottlamb/luxor/protocol"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var errDown = errors.New("connection refused")
+
+// flaky is a controller which can be taken down, counting the calls which
+// reach it.
+type flaky struct {
+	protocol.Controller
+	down  atomic.Bool
+	calls atomic.Int32
+}
+
+func (f *flaky) ControllerName(ctx context.Context, req *protocol.ControllerNameRequest) (*protocol.ControllerNameResponse, error) {
+	f.calls.Add(1)
+	if f.down.Load() {
+		return nil, errDown
+	}
+	return f.Controller.ControllerName(ctx, req)
+}
+
+func (f *flaky) GroupListGet(ctx context.Context, req *protocol.GroupListGetRequest) (*protocol.GroupListGetResponse, error) {
+	f.calls.Add(1)
+	if f.down.Load() {
+		return nil, errDown
+	}
+	return f.Controller.GroupListGet(ctx, req)
+}
+
+func (f *flaky) IlluminateTheme(ctx context.Context, req *protocol.IlluminateThemeRequest) (*protocol.IlluminateThemeResponse, error) {
+	f.calls.Add(1)
+	if f.down.Load() {
+		return nil, errDown
+	}
+	return f.Controller.IlluminateTheme(ctx, req)
+}
+
+func next(t *testing.T, changes <-chan health.Status) health.Status {
+	t.Helper()
+	select {
+	case s := <-changes:
+		return s
+	case <-time.After(time.Second):
+		t.Fatal("no state change")
+	}
+	panic("unreachable")
+}
+
+func TestBreaker(t *testing.T) {
+	ctx := context.Background()
+	f := &flaky{Controller: emulator.New("Home")}
+	b := &health.Breaker{Controller: f, TripAfter: 2, Cooldown: 50 * time.Millisecond}
+	changes, cancel := b.Watch()
+	defer cancel()
+
+	if _, err := b.GroupListGet(ctx, &protocol.GroupListGetRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if s := b.Status(); !s.Healthy() || s.LastSuccess.IsZero() {
+		t.Errorf("status = %+v", s)
+	}
+
+	// A status error means the controller answered.
+	_, err := b.IlluminateTheme(ctx, &protocol.IlluminateThemeRequest{ThemeIndex: 9, OnOff: 1})
+	if protocol.StatusOf(err) <= 0 {
+		t.Fatalf("IlluminateTheme of missing theme: %v", err)
+	}
+	if s := b.Status(); s.Failures != 0 {
+		t.Errorf("status error counted: %+v", s)
+	}
+
+	// Calls abandoned by their callers don't count either.
+	canceled, cancelCall := context.WithCancel(ctx)
+	cancelCall()
+	b2 := &health.Breaker{Controller: &canceling{}, TripAfter: 1}
+	if _, err := b2.GroupListGet(canceled, &protocol.GroupListGetRequest{}); err == nil || !b2.Healthy() {
+		t.Errorf("after abandoned call: %v, %+v", err, b2.Status())
+	}
+
+	f.down.Store(true)
+	for i := 0; i < 2; i++ {
+		if _, err := b.GroupListGet(ctx, &protocol.GroupListGetRequest{}); !errors.Is(err, errDown) {
+			t.Fatalf("call %d while down: %v", i, err)
+		}
+	}
+	if s := next(t, changes); s.State != health.Open || s.Failures != 2 || b.Healthy() {
+		t.Errorf("after failures: %+v", s)
+	}
+
+	// Open: calls fail fast without reaching the controller.
+	before := f.calls.Load()
+	if _, err := b.GroupListGet(ctx, &protocol.GroupListGetRequest{}); !errors.Is(err, health.ErrOpen) {
+		t.Errorf("call while open: %v", err)
+	}
+	if f.calls.Load() != before {
+		t.Error("call while open reached the controller")
+	}
+
+	// After the cooldown, a failed probe reopens the breaker.
+	time.Sleep(60 * time.Millisecond)
+	if _, err := b.GroupListGet(ctx, &protocol.GroupListGetRequest{}); !errors.Is(err, health.ErrOpen) {
+		t.Errorf("call after cooldown while down: %v", err)
+	}
+	if got := f.calls.Load() - before; got != 1 {
+		t.Errorf("%d calls reached the controller after cooldown; want 1 probe", got)
+	}
+	// The watcher sees only the latest state, not the probe in between.
+	if s := next(t, changes); s.State != health.Open || s.Failures != 3 {
+		t.Errorf("after failed probe: %+v", s)
+	}
+
+	// A successful probe closes it and lets the call through.
+	f.down.Store(false)
+	time.Sleep(60 * time.Millisecond)
+	if _, err := b.IlluminateTheme(ctx, &protocol.IlluminateThemeRequest{ThemeIndex: 9, OnOff: 1}); protocol.StatusOf(err) <= 0 {
+		t.Errorf("call after recovery: %v", err)
+	}
+	if s := next(t, changes); s.State != health.Closed || s.Failures != 0 || !b.Healthy() {
+		t.Errorf("after recovery: %+v", s)
+	}
+}
+
+// canceling is a controller whose calls fail as if their caller gave up.
+type canceling struct {
+	protocol.Controller
+}
+
+func (canceling) GroupListGet(ctx context.Context, _ *protocol.GroupListGetRequest) (*protocol.GroupListGetResponse, error) {
+	return nil, ctx.Err()
+}
+
+func TestBreakerColors(t *testing.T) {
+	ctx := context.Background()
+	b := &health.Breaker{Controller: emulator.NewZDC("Home")}
+	if _, err := b.ColorListSet(ctx, &protocol.ColorListSetRequest{ColorNumber: 1, Hue: 120, Saturation: 100}); err != nil {
+		t.Fatal(err)
+	}
+	colors, err := b.ColorListGet(ctx, &protocol.ColorListGetRequest{})
+	if err != nil || len(colors.ColorList) != 1 || colors.ColorList[0].Hue != 120 {
+		t.Errorf("ColorListGet = %+v, %v", colors, err)
+	}
+
+	// A wrapped controller without color methods isn't called.
+	f := &flaky{Controller: emulator.NewZDC("Home")}
+	f.down.Store(true)
+	b = &health.Breaker{Controller: f, TripAfter: 1}
+	if _, err := b.ColorListGet(ctx, &protocol.ColorListGetRequest{}); protocol.StatusOf(err) != protocol.StatusUnknownMethod {
+		t.Errorf("ColorListGet without color methods: got %v", err)
+	}
+	if !b.Healthy() || f.calls.Load() != 0 {
+		t.Errorf("after ColorListGet without color methods: %+v, %d calls", b.Status(), f.calls.Load())
+	}
+}
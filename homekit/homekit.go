@@ -0,0 +1,367 @@
+// Package homekit exposes a Luxor controller to Apple's Home app and Siri as
+// a HomeKit Accessory Protocol bridge.
+//
+// Each group from GroupListGet becomes a Lightbulb accessory whose Brightness
+// drives IlluminateGroup; each theme from ThemeListGet becomes a Switch
+// accessory driving IlluminateTheme. Accessory IDs are derived from group
+// numbers and theme indexes, so renaming or reordering doesn't make HomeKit
+// forget rooms, scenes, or automations. Pairing state lives in a directory on
+// disk, so the bridge stays paired across restarts.
+package homekit
+
+import (
+	"context"
+	"errors"
+	"github.com/brutella/hap"
+	"github.com/brutella/hap/accessory"
+	"github.com/brutella/hap/characteristic"
+	"github.com/scottlamb/luxor/protocol"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultPollInterval = 10 * time.Second
+
+	// DefaultPin is the setup code entered in the Home app when pairing,
+	// shown there as 001-02-003.
+	DefaultPin = "00102003"
+
+	commandTimeout = 10 * time.Second
+
+	// minRetryDelay and maxRetryDelay bound Run's backoff after failures.
+	minRetryDelay = time.Second
+	maxRetryDelay = 5 * time.Minute
+
+	// groupIDBase and themeIDBase offset accessory IDs. ID 1 belongs to the
+	// bridge itself.
+	groupIDBase = 100
+	themeIDBase = 1000
+
+	manufacturer = "Luxor"
+)
+
+// Bridge serves one controller to HomeKit.
+type Bridge struct {
+	Controller protocol.Controller
+
+	// StorePath is the directory holding the bridge's key pair and pairings.
+	StorePath string
+
+	// Pin is the eight-digit setup code. Defaults to DefaultPin.
+	Pin string
+
+	// Addr is the TCP address to serve on. If empty, a random port is used;
+	// HomeKit finds it through mDNS either way.
+	Addr string
+
+	// Name is the bridge's name in the Home app. Defaults to the controller's
+	// name.
+	Name string
+
+	// PollInterval is how often to poll the controller for changes made
+	// elsewhere. Defaults to DefaultPollInterval.
+	PollInterval time.Duration
+}
+
+// Run serves until ctx is done. When groups or themes are added or deleted,
+// the HAP server is restarted with the new accessory set; existing
+// accessories keep their IDs. Failures, such as of the controller while
+// starting, are logged and retried with backoff, so Run returns only
+// ctx.Err().
+func (b *Bridge) Run(ctx context.Context) error {
+	delay := minRetryDelay
+	for {
+		started, err := b.serve(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if started {
+			delay = minRetryDelay
+		}
+		if err == nil {
+			log.Printf("homekit: groups or themes added or deleted; restarting")
+			continue
+		}
+		log.Printf("homekit: %v; retrying in %s", err, delay)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, maxRetryDelay)
+	}
+}
+
+// serve runs one HAP server until ctx is done, it fails, or the accessory
+// set changes (in which case it returns a nil error). started is true if the
+// server was started.
+func (b *Bridge) serve(ctx context.Context) (started bool, err error) {
+	name := b.Name
+	if name == "" {
+		resp, err := b.Controller.ControllerName(ctx, &protocol.ControllerNameRequest{})
+		if err != nil {
+			return false, err
+		}
+		name = resp.Controller
+	}
+	interval := b.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	groups, themes, err := fetch(ctx, b.Controller)
+	if err != nil {
+		return false, err
+	}
+	set := newAccessorySet(b.Controller, name, groups, themes)
+	server, err := hap.NewServer(hap.NewFsStore(b.StorePath), set.bridge.A, set.accessories()...)
+	if err != nil {
+		return false, err
+	}
+	server.Pin = b.Pin
+	if server.Pin == "" {
+		server.Pin = DefaultPin
+	}
+	server.Addr = b.Addr
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	serveCtx, cancel := context.WithCancel(ctx)
+	served := make(chan error, 1)
+	go func() { served <- server.ListenAndServe(serveCtx) }()
+	err = set.poll(serveCtx, ticker.C, served)
+	cancel()
+	if err != nil {
+		return true, err
+	}
+	<-served
+	return true, nil
+}
+
+// fetch returns the controller's groups and themes, dropping themes with
+// duplicate indexes.
+func fetch(ctx context.Context, c protocol.Controller) ([]protocol.Group, []protocol.Theme, error) {
+	groups, err := c.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	if err != nil {
+		return nil, nil, err
+	}
+	themes, err := c.ThemeListGet(ctx, &protocol.ThemeListGetRequest{})
+	if err != nil {
+		return nil, nil, err
+	}
+	var unique []protocol.Theme
+	seen := make(map[uint8]bool)
+	for _, t := range themes.ThemeList {
+		if !seen[t.ThemeIndex] {
+			seen[t.ThemeIndex] = true
+			unique = append(unique, t)
+		}
+	}
+	return groups.GroupList, unique, nil
+}
+
+// accessorySet is the bridge and accessories for one layout of groups and
+// themes.
+type accessorySet struct {
+	controller protocol.Controller
+	bridge     *accessory.Bridge
+	groups     map[uint8]*groupLight
+	themes     map[uint8]*themeSwitch
+}
+
+type groupLight struct {
+	*accessory.Lightbulb
+	brightness *characteristic.Brightness
+
+	mu     sync.Mutex
+	lastOn int // last non-zero intensity, restored when turned on.
+}
+
+type themeSwitch struct {
+	*accessory.Switch
+}
+
+func newAccessorySet(c protocol.Controller, name string, groups []protocol.Group, themes []protocol.Theme) *accessorySet {
+	s := &accessorySet{
+		controller: c,
+		bridge:     accessory.NewBridge(accessory.Info{Name: name, Manufacturer: manufacturer, Model: "Controller"}),
+		groups:     make(map[uint8]*groupLight),
+		themes:     make(map[uint8]*themeSwitch),
+	}
+	s.bridge.Id = 1
+	for _, g := range groups {
+		s.addGroup(g)
+	}
+	for _, t := range themes {
+		s.addTheme(t)
+	}
+	return s
+}
+
+func (s *accessorySet) addGroup(g protocol.Group) {
+	l := &groupLight{
+		Lightbulb: accessory.NewLightbulb(accessory.Info{
+			Name:         g.Name,
+			SerialNumber: "group-" + strconv.Itoa(int(g.GroupNumber)),
+			Manufacturer: manufacturer,
+			Model:        "Group",
+		}),
+		brightness: characteristic.NewBrightness(),
+		lastOn:     protocol.MaxIntensity,
+	}
+	l.Id = groupIDBase + uint64(g.GroupNumber)
+	l.Lightbulb.Lightbulb.AddC(l.brightness.C)
+	l.set(int(g.Intensity))
+	n := g.GroupNumber
+	l.brightness.OnSetRemoteValue(func(v int) error {
+		if err := s.illuminateGroup(n, v); err != nil {
+			return err
+		}
+		l.mu.Lock()
+		if v > 0 {
+			l.lastOn = v
+		}
+		l.mu.Unlock()
+		l.Lightbulb.Lightbulb.On.SetValue(v > 0)
+		return nil
+	})
+	l.Lightbulb.Lightbulb.On.OnSetRemoteValue(func(on bool) error {
+		v := 0
+		if on {
+			l.mu.Lock()
+			v = l.lastOn
+			l.mu.Unlock()
+		}
+		if err := s.illuminateGroup(n, v); err != nil {
+			return err
+		}
+		if on {
+			l.brightness.SetValue(v)
+		}
+		return nil
+	})
+	s.groups[n] = l
+}
+
+// set updates the light to reflect intensity. An unlit group keeps its last
+// brightness, as HomeKit lights do.
+func (l *groupLight) set(intensity int) {
+	l.Lightbulb.Lightbulb.On.SetValue(intensity > 0)
+	if intensity > 0 {
+		l.mu.Lock()
+		l.lastOn = intensity
+		l.mu.Unlock()
+		l.brightness.SetValue(intensity)
+	}
+}
+
+func (s *accessorySet) addTheme(t protocol.Theme) {
+	sw := &themeSwitch{accessory.NewSwitch(accessory.Info{
+		Name:         t.Name,
+		SerialNumber: "theme-" + strconv.Itoa(int(t.ThemeIndex)),
+		Manufacturer: manufacturer,
+		Model:        "Theme",
+	})}
+	sw.Id = themeIDBase + uint64(t.ThemeIndex)
+	sw.Switch.Switch.On.SetValue(t.OnOff != 0)
+	index := t.ThemeIndex
+	sw.Switch.Switch.On.OnSetRemoteValue(func(on bool) error {
+		ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+		defer cancel()
+		req := &protocol.IlluminateThemeRequest{ThemeIndex: index}
+		if on {
+			req.OnOff = 1
+		}
+		_, err := s.controller.IlluminateTheme(ctx, req)
+		if err != nil {
+			log.Printf("homekit: IlluminateTheme %d failed: %v", index, err)
+		}
+		return err
+	})
+	s.themes[index] = sw
+}
+
+func (s *accessorySet) illuminateGroup(n uint8, intensity int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	_, err := s.controller.IlluminateGroup(ctx, &protocol.IlluminateGroupRequest{
+		GroupNumber: n,
+		Intensity:   uint8(intensity),
+	})
+	if err != nil {
+		log.Printf("homekit: IlluminateGroup %d failed: %v", n, err)
+	}
+	return err
+}
+
+// accessories returns the bridged accessories, ordered by ID.
+func (s *accessorySet) accessories() []*accessory.A {
+	var as []*accessory.A
+	for _, l := range s.groups {
+		as = append(as, l.A)
+	}
+	for _, sw := range s.themes {
+		as = append(as, sw.A)
+	}
+	sort.Slice(as, func(i, j int) bool { return as[i].Id < as[j].Id })
+	return as
+}
+
+// update applies a fresh poll. It returns false if groups or themes were
+// added or deleted, in which case the set must be rebuilt.
+func (s *accessorySet) update(groups []protocol.Group, themes []protocol.Theme) bool {
+	if len(groups) != len(s.groups) || len(themes) != len(s.themes) {
+		return false
+	}
+	for _, g := range groups {
+		if s.groups[g.GroupNumber] == nil {
+			return false
+		}
+	}
+	for _, t := range themes {
+		if s.themes[t.ThemeIndex] == nil {
+			return false
+		}
+	}
+	for _, g := range groups {
+		l := s.groups[g.GroupNumber]
+		l.Info.Name.SetValue(g.Name)
+		l.set(int(g.Intensity))
+	}
+	for _, t := range themes {
+		sw := s.themes[t.ThemeIndex]
+		sw.Info.Name.SetValue(t.Name)
+		sw.Switch.Switch.On.SetValue(t.OnOff != 0)
+	}
+	return true
+}
+
+// poll updates the set on each tick until ctx is done (returning its error),
+// the server fails (returning that error), or the layout changes (returning
+// nil).
+func (s *accessorySet) poll(ctx context.Context, tick <-chan time.Time, served <-chan error) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-served:
+			if err == nil {
+				err = errors.New("homekit: server stopped")
+			}
+			return err
+		case <-tick:
+		}
+		groups, themes, err := fetch(ctx, s.controller)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("homekit: poll failed: %v", err)
+			}
+			continue
+		}
+		if !s.update(groups, themes) {
+			return nil
+		}
+	}
+}
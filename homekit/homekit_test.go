@@ -0,0 +1,153 @@
+package homekit
+
+import (
+	"context"
+	"errors"
+	"github.com/brutella/hap"
+	"github.com/scottlamb/luxor/emulator"
+	"github.com/scottlamb/luxor/protocol"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// remote marks a characteristic write as coming from a paired device; hap
+// only calls OnSetRemoteValue callbacks for those.
+var remote = &http.Request{}
+
+func setup(t *testing.T) (*emulator.Controller, *accessorySet) {
+	ctx := context.Background()
+	e := emulator.New("test")
+	if _, err := e.GroupListAdd(ctx, &protocol.GroupListAddRequest{GroupNumber: 3, Name: "Path"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.ThemeListAdd(ctx, &protocol.ThemeListAddRequest{ThemeIndex: 1, Name: "Party"}); err != nil {
+		t.Fatal(err)
+	}
+	groups, themes, err := fetch(ctx, e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e, newAccessorySet(e, "test", groups, themes)
+}
+
+func intensity(t *testing.T, e *emulator.Controller) uint8 {
+	groups, err := e.GroupListGet(context.Background(), &protocol.GroupListGetRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return groups.GroupList[0].Intensity
+}
+
+func TestServerAcceptsAccessories(t *testing.T) {
+	_, set := setup(t)
+	if _, err := hap.NewServer(hap.NewMemStore(), set.bridge.A, set.accessories()...); err != nil {
+		t.Fatal(err)
+	}
+	as := set.accessories()
+	if len(as) != 2 || as[0].Id != groupIDBase+3 || as[1].Id != themeIDBase+1 {
+		t.Errorf("unexpected accessories %+v", as)
+	}
+}
+
+func TestRemoteWrites(t *testing.T) {
+	e, set := setup(t)
+	l := set.groups[3]
+	if _, code := l.brightness.SetValueRequest(40, remote); code != 0 {
+		t.Fatalf("brightness write failed with %d", code)
+	}
+	if got := intensity(t, e); got != 40 || !l.Lightbulb.Lightbulb.On.Value() {
+		t.Errorf("after brightness 40: intensity %d, on %v", got, l.Lightbulb.Lightbulb.On.Value())
+	}
+	if _, code := l.Lightbulb.Lightbulb.On.SetValueRequest(false, remote); code != 0 {
+		t.Fatalf("off write failed with %d", code)
+	}
+	if got := intensity(t, e); got != 0 {
+		t.Errorf("after off: intensity %d", got)
+	}
+	if _, code := l.Lightbulb.Lightbulb.On.SetValueRequest(true, remote); code != 0 {
+		t.Fatalf("on write failed with %d", code)
+	}
+	if got := intensity(t, e); got != 40 {
+		t.Errorf("after on: intensity %d; want last brightness 40", got)
+	}
+
+	sw := set.themes[1]
+	if _, code := sw.Switch.Switch.On.SetValueRequest(true, remote); code != 0 {
+		t.Fatalf("theme write failed with %d", code)
+	}
+	themes, err := e.ThemeListGet(context.Background(), &protocol.ThemeListGetRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if themes.ThemeList[0].OnOff != 1 {
+		t.Errorf("theme not on: %+v", themes.ThemeList)
+	}
+
+	// Failures are reported to HomeKit.
+	if _, err := e.ThemeListDelete(context.Background(), &protocol.ThemeListDeleteRequest{Name: "Party"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, code := sw.Switch.Switch.On.SetValueRequest(false, remote); code == 0 {
+		t.Error("write to deleted theme should fail")
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	ctx := context.Background()
+	e, set := setup(t)
+	if _, err := e.GroupListRename(ctx, &protocol.GroupListRenameRequest{OldName: "Path", NewName: "Walk"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.IlluminateGroup(ctx, &protocol.IlluminateGroupRequest{GroupNumber: 3, Intensity: 70}); err != nil {
+		t.Fatal(err)
+	}
+	groups, themes, err := fetch(ctx, e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !set.update(groups, themes) {
+		t.Fatal("rename should update in place")
+	}
+	l := set.groups[3]
+	if l.Name() != "Walk" || l.brightness.Value() != 70 || !l.Lightbulb.Lightbulb.On.Value() || l.Id != groupIDBase+3 {
+		t.Errorf("unexpected light: name %q, brightness %d, on %v, id %d",
+			l.Name(), l.brightness.Value(), l.Lightbulb.Lightbulb.On.Value(), l.Id)
+	}
+
+	if _, err := e.GroupListAdd(ctx, &protocol.GroupListAddRequest{GroupNumber: 4, Name: "Patio"}); err != nil {
+		t.Fatal(err)
+	}
+	groups, themes, err = fetch(ctx, e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if set.update(groups, themes) {
+		t.Error("added group should require a rebuild")
+	}
+}
+
+// unreachable is a controller which can't be reached, counting attempts.
+type unreachable struct {
+	protocol.Controller
+	calls atomic.Int32
+}
+
+func (u *unreachable) ControllerName(context.Context, *protocol.ControllerNameRequest) (*protocol.ControllerNameResponse, error) {
+	u.calls.Add(1)
+	return nil, errors.New("connection refused")
+}
+
+func TestRunRetries(t *testing.T) {
+	u := &unreachable{}
+	b := &Bridge{Controller: u, StorePath: t.TempDir()}
+	ctx, cancel := context.WithTimeout(context.Background(), minRetryDelay+500*time.Millisecond)
+	defer cancel()
+	if err := b.Run(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Run = %v; want %v", err, context.DeadlineExceeded)
+	}
+	if n := u.calls.Load(); n != 2 {
+		t.Errorf("%d attempts; want 2", n)
+	}
+}
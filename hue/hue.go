@@ -0,0 +1,726 @@
+// Package hue emulates a Philips Hue bridge (API v1) in front of any
+// protocol.Controller, for universal remotes and voice assistants which can
+// talk to a local Hue bridge but nothing else.
+//
+// Each Luxor group is a dimmable light whose ID is its group number; Hue's
+// bri (1–254) maps onto intensity (0–MaxIntensity). Themes are scenes whose
+// IDs are their letters. Group 0 and group 1 both hold every light; turning
+// them off is ExtinguishAll, and recalling a scene on them is
+// IlluminateTheme. The supported endpoints are:
+//
+//	GET  /description.xml               UPnP description, found through SSDP
+//	POST /api                           register a user (always succeeds)
+//	GET  /api/{user}                    full state
+//	GET  /api/{user}/config             bridge configuration
+//	GET  /api/{user}/lights             all lights
+//	GET  /api/{user}/lights/{id}        one light
+//	PUT  /api/{user}/lights/{id}/state  set on, bri, or bri_inc
+//	GET  /api/{user}/groups             all groups
+//	GET  /api/{user}/groups/{id}        one group
+//	PUT  /api/{user}/groups/{id}/action set on, bri, or scene
+//	GET  /api/{user}/scenes             all scenes
+//	GET  /api/{user}/scenes/{id}        one scene, with its light states
+//
+// There's no link button: any device on the LAN may register, just as
+// anyone on the LAN may talk to the controller itself.
+package hue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/scottlamb/luxor/protocol"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	MaxBri = 254
+
+	apiVersion = "1.16.0"
+	swVersion  = "1916021010"
+)
+
+// Hue error types, as in the API's error responses.
+const (
+	errInvalidJSON      = 2
+	errNotAvailable     = 3
+	errMethodNotAllowed = 4
+	errMissingParameter = 5
+	errParameter        = 6
+	errInvalidValue     = 7
+	errDeviceOff        = 201
+	errInternal         = 901
+)
+
+// Bridge is an http.Handler serving the emulated Hue API.
+type Bridge struct {
+	Controller protocol.Controller
+
+	// Name is the bridge's name. Defaults to "Luxor".
+	Name string
+
+	// URLBase is the externally visible URL of the bridge, such as
+	// "http://192.168.1.5:80/". Many clients only look for bridges on port
+	// 80.
+	URLBase string
+
+	// SerialNumber is twelve hex digits identifying the bridge, by
+	// convention its MAC address.
+	SerialNumber string
+
+	mux  *http.ServeMux
+	once sync.Once
+
+	mu     sync.Mutex
+	lastOn map[uint8]uint8 // group number -> last non-zero intensity.
+}
+
+// BridgeID returns the bridge ID derived from the serial number, as Hue
+// bridges report it.
+func (b *Bridge) BridgeID() string {
+	s := strings.ToUpper(b.SerialNumber)
+	if len(s) != 12 {
+		return s
+	}
+	return s[:6] + "FFFE" + s[6:]
+}
+
+// UDN returns the bridge's UPnP unique device name.
+func (b *Bridge) UDN() string {
+	return "uuid:2f402f80-da50-11e1-9b23-" + strings.ToLower(b.SerialNumber)
+}
+
+func (b *Bridge) name() string {
+	if b.Name != "" {
+		return b.Name
+	}
+	return "Luxor"
+}
+
+// hueError is one entry of an error response.
+type hueError struct {
+	Type        int    `json:"type"`
+	Address     string `json:"address"`
+	Description string `json:"description"`
+}
+
+func (e *hueError) Error() string { return e.Description }
+
+func notAvailable(address string) *hueError {
+	return &hueError{errNotAvailable, address, fmt.Sprintf("resource, %s, not available", address)}
+}
+
+// controllerError wraps an error from the controller.
+func controllerError(address string, err error) *hueError {
+	if s := protocol.StatusOf(err); s == protocol.StatusPreconditionFailed || s == protocol.StatusThemeIndexOutOfRange {
+		return notAvailable(address)
+	}
+	return &hueError{errInternal, address, "Internal error, " + err.Error()}
+}
+
+type lightState struct {
+	On        bool   `json:"on"`
+	Bri       int    `json:"bri"`
+	Alert     string `json:"alert"`
+	Mode      string `json:"mode"`
+	Reachable bool   `json:"reachable"`
+}
+
+type light struct {
+	State            lightState `json:"state"`
+	Type             string     `json:"type"`
+	Name             string     `json:"name"`
+	ModelID          string     `json:"modelid"`
+	ManufacturerName string     `json:"manufacturername"`
+	ProductName      string     `json:"productname"`
+	UniqueID         string     `json:"uniqueid"`
+	SWVersion        string     `json:"swversion"`
+}
+
+type groupAction struct {
+	On    bool   `json:"on"`
+	Bri   int    `json:"bri"`
+	Alert string `json:"alert"`
+}
+
+type groupState struct {
+	AllOn bool `json:"all_on"`
+	AnyOn bool `json:"any_on"`
+}
+
+type group struct {
+	Name   string      `json:"name"`
+	Lights []string    `json:"lights"`
+	Type   string      `json:"type"`
+	Class  string      `json:"class,omitempty"`
+	State  groupState  `json:"state"`
+	Action groupAction `json:"action"`
+}
+
+type scene struct {
+	Name        string                `json:"name"`
+	Type        string                `json:"type"`
+	Lights      []string              `json:"lights"`
+	Owner       string                `json:"owner"`
+	Recycle     bool                  `json:"recycle"`
+	Locked      bool                  `json:"locked"`
+	Version     int                   `json:"version"`
+	LightStates map[string]lightState `json:"lightstates,omitempty"`
+}
+
+type config struct {
+	Name             string `json:"name"`
+	BridgeID         string `json:"bridgeid"`
+	MAC              string `json:"mac"`
+	ModelID          string `json:"modelid"`
+	APIVersion       string `json:"apiversion"`
+	SWVersion        string `json:"swversion"`
+	DataStoreVersion string `json:"datastoreversion"`
+	LinkButton       bool   `json:"linkbutton"`
+	FactoryNew       bool   `json:"factorynew"`
+}
+
+// IntensityToBri converts a Luxor intensity to a Hue brightness.
+func IntensityToBri(intensity uint8) int {
+	if intensity == 0 {
+		return 1
+	}
+	return (int(intensity)*MaxBri + protocol.MaxIntensity/2) / protocol.MaxIntensity
+}
+
+// BriToIntensity converts a Hue brightness to a Luxor intensity. Any
+// brightness is lit, so the result is at least 1.
+func BriToIntensity(bri int) uint8 {
+	switch {
+	case bri > MaxBri:
+		bri = MaxBri
+	case bri < 1:
+		bri = 1
+	}
+	i := (bri*protocol.MaxIntensity + MaxBri/2) / MaxBri
+	if i < 1 {
+		i = 1
+	}
+	return uint8(i)
+}
+
+func (b *Bridge) init() {
+	b.lastOn = make(map[uint8]uint8)
+	m := http.NewServeMux()
+	m.HandleFunc("GET /description.xml", b.serveDescription)
+	m.HandleFunc("POST /api", b.register)
+	m.HandleFunc("POST /api/{$}", b.register)
+	b.handle(m, "GET /api/{user}", b.getAll)
+	b.handle(m, "GET /api/{user}/config", b.getConfig)
+	b.handle(m, "GET /api/{user}/lights", b.getLights)
+	b.handle(m, "GET /api/{user}/lights/{id}", b.getLight)
+	b.handle(m, "PUT /api/{user}/lights/{id}/state", b.putLightState)
+	b.handle(m, "GET /api/{user}/groups", b.getGroups)
+	b.handle(m, "GET /api/{user}/groups/{id}", b.getGroup)
+	b.handle(m, "PUT /api/{user}/groups/{id}/action", b.putGroupAction)
+	b.handle(m, "GET /api/{user}/scenes", b.getScenes)
+	b.handle(m, "GET /api/{user}/scenes/{id}", b.getScene)
+	b.handle(m, "/api/{user}/{rest...}", func(r *http.Request) (interface{}, error) {
+		address := "/" + r.PathValue("rest")
+		return nil, &hueError{errMethodNotAllowed, address,
+			fmt.Sprintf("method, %s, not available for resource, %s", r.Method, address)}
+	})
+	b.mux = m
+}
+
+func (b *Bridge) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.once.Do(b.init)
+	b.mux.ServeHTTP(w, r)
+}
+
+// handle registers an API handler. Errors are reported in Hue's format,
+// which always uses HTTP status 200.
+func (b *Bridge) handle(m *http.ServeMux, pattern string, f func(*http.Request) (interface{}, error)) {
+	m.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		resp, err := f(r)
+		if err != nil {
+			e, ok := err.(*hueError)
+			if !ok {
+				e = controllerError(strings.TrimPrefix(r.URL.Path, "/api/"+r.PathValue("user")), err)
+			}
+			resp = []map[string]*hueError{{"error": e}}
+		}
+		writeJSON(w, resp)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func (b *Bridge) register(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		DeviceType string `json:"devicetype"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, []map[string]*hueError{{"error": {errInvalidJSON, "", "body contains invalid json"}}})
+		return
+	}
+	if req.DeviceType == "" {
+		writeJSON(w, []map[string]*hueError{{"error": {errMissingParameter, "/", "invalid/missing parameters in body"}}})
+		return
+	}
+	// Derive a stable username from the device type, so a device which
+	// re-registers keeps its identity.
+	user := fmt.Sprintf("luxor%x", []byte(req.DeviceType))
+	if len(user) > 40 {
+		user = user[:40]
+	}
+	writeJSON(w, []map[string]map[string]string{{"success": {"username": user}}})
+}
+
+func (b *Bridge) config() config {
+	return config{
+		Name:             b.name(),
+		BridgeID:         b.BridgeID(),
+		MAC:              macAddress(b.SerialNumber),
+		ModelID:          "BSB002",
+		APIVersion:       apiVersion,
+		SWVersion:        swVersion,
+		DataStoreVersion: "63",
+		LinkButton:       true,
+	}
+}
+
+func macAddress(serial string) string {
+	if len(serial) != 12 {
+		return serial
+	}
+	parts := make([]string, 6)
+	for i := range parts {
+		parts[i] = strings.ToLower(serial[2*i : 2*i+2])
+	}
+	return strings.Join(parts, ":")
+}
+
+func (b *Bridge) getConfig(r *http.Request) (interface{}, error) {
+	return b.config(), nil
+}
+
+// fetchGroups returns the controller's groups, remembering lit intensities.
+func (b *Bridge) fetchGroups(ctx context.Context) ([]protocol.Group, error) {
+	resp, err := b.Controller.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	if err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	for _, g := range resp.GroupList {
+		if g.Intensity > 0 {
+			b.lastOn[g.GroupNumber] = g.Intensity
+		}
+	}
+	b.mu.Unlock()
+	return resp.GroupList, nil
+}
+
+func (b *Bridge) toLight(g protocol.Group) light {
+	bri := IntensityToBri(g.Intensity)
+	if g.Intensity == 0 {
+		b.mu.Lock()
+		if last, ok := b.lastOn[g.GroupNumber]; ok {
+			bri = IntensityToBri(last)
+		}
+		b.mu.Unlock()
+	}
+	return light{
+		State: lightState{
+			On:        g.Intensity > 0,
+			Bri:       bri,
+			Alert:     "none",
+			Mode:      "homeautomation",
+			Reachable: true,
+		},
+		Type:             "Dimmable light",
+		Name:             g.Name,
+		ModelID:          "LWB010",
+		ManufacturerName: "Luxor",
+		ProductName:      "Luxor group",
+		UniqueID:         fmt.Sprintf("%s:%02x-0b", macAddress(b.SerialNumber), g.GroupNumber),
+		SWVersion:        swVersion,
+	}
+}
+
+func lightID(groupNumber uint8) string { return strconv.Itoa(int(groupNumber)) }
+
+func (b *Bridge) lights(ctx context.Context) (map[string]light, []string, error) {
+	groups, err := b.fetchGroups(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	lights := make(map[string]light, len(groups))
+	ids := make([]string, len(groups))
+	for i, g := range groups {
+		ids[i] = lightID(g.GroupNumber)
+		lights[ids[i]] = b.toLight(g)
+	}
+	return lights, ids, nil
+}
+
+func (b *Bridge) getLights(r *http.Request) (interface{}, error) {
+	lights, _, err := b.lights(r.Context())
+	return lights, err
+}
+
+func (b *Bridge) getLight(r *http.Request) (interface{}, error) {
+	lights, _, err := b.lights(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	l, ok := lights[r.PathValue("id")]
+	if !ok {
+		return nil, notAvailable("/lights/" + r.PathValue("id"))
+	}
+	return l, nil
+}
+
+// stateChange is a request body for a light's state or a group's action.
+type stateChange struct {
+	On     *bool   `json:"on"`
+	Bri    *int    `json:"bri"`
+	BriInc *int    `json:"bri_inc"`
+	Scene  *string `json:"scene"`
+
+	// TransitionTime is accepted and ignored; the controller has no fades.
+	TransitionTime *int `json:"transitiontime"`
+}
+
+// decodeChange decodes a state change, rejecting parameters the emulator
+// doesn't support (such as colors) as a real bridge does for a dimmable
+// light.
+func decodeChange(r *http.Request, address string, allowScene bool) (*stateChange, error) {
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		return nil, &hueError{errInvalidJSON, address, "body contains invalid json"}
+	}
+	for k := range raw {
+		switch k {
+		case "on", "bri", "bri_inc", "transitiontime":
+		case "scene":
+			if allowScene {
+				continue
+			}
+			fallthrough
+		default:
+			return nil, &hueError{errParameter, address + "/" + k, fmt.Sprintf("parameter, %s, not available", k)}
+		}
+	}
+	var c stateChange
+	for k, v := range raw {
+		var err error
+		switch k {
+		case "on":
+			err = json.Unmarshal(v, &c.On)
+		case "bri":
+			err = json.Unmarshal(v, &c.Bri)
+		case "bri_inc":
+			err = json.Unmarshal(v, &c.BriInc)
+		case "scene":
+			err = json.Unmarshal(v, &c.Scene)
+		case "transitiontime":
+			err = json.Unmarshal(v, &c.TransitionTime)
+		}
+		if err != nil {
+			return nil, &hueError{errInvalidValue, address + "/" + k,
+				fmt.Sprintf("invalid value, %s, for parameter, %s", v, k)}
+		}
+	}
+	return &c, nil
+}
+
+// successes builds the success response for a state change.
+func successes(address string, c *stateChange, on bool, bri int) interface{} {
+	var out []map[string]map[string]interface{}
+	add := func(k string, v interface{}) {
+		out = append(out, map[string]map[string]interface{}{"success": {address + "/" + k: v}})
+	}
+	if c.On != nil {
+		add("on", on)
+	}
+	if c.Bri != nil {
+		add("bri", bri)
+	}
+	if c.BriInc != nil {
+		add("bri_inc", *c.BriInc)
+	}
+	if c.Scene != nil {
+		add("scene", *c.Scene)
+	}
+	if c.TransitionTime != nil {
+		add("transitiontime", *c.TransitionTime)
+	}
+	return out
+}
+
+// target computes the intensity a state change requests for a light which
+// currently has the given intensity. Dimming an unlit light leaves it unlit.
+func (b *Bridge) target(n, current uint8, c *stateChange) uint8 {
+	if c.On != nil && !*c.On || current == 0 && c.On == nil {
+		return 0
+	}
+	bri := IntensityToBri(current)
+	if current == 0 {
+		b.mu.Lock()
+		last, ok := b.lastOn[n]
+		b.mu.Unlock()
+		if !ok {
+			last = protocol.MaxIntensity
+		}
+		bri = IntensityToBri(last)
+	}
+	if c.Bri != nil {
+		bri = *c.Bri
+	} else if c.BriInc != nil {
+		bri += *c.BriInc
+	}
+	return BriToIntensity(bri)
+}
+
+func (b *Bridge) putLightState(r *http.Request) (interface{}, error) {
+	id := r.PathValue("id")
+	address := "/lights/" + id + "/state"
+	c, err := decodeChange(r, address, false)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := b.fetchGroups(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range groups {
+		if lightID(g.GroupNumber) != id {
+			continue
+		}
+		if g.Intensity == 0 && c.On == nil && (c.Bri != nil || c.BriInc != nil) {
+			return nil, &hueError{errDeviceOff, address + "/bri", "parameter, bri, is not modifiable. Device is set to off."}
+		}
+		intensity := b.target(g.GroupNumber, g.Intensity, c)
+		if _, err := b.Controller.IlluminateGroup(r.Context(), &protocol.IlluminateGroupRequest{
+			GroupNumber: g.GroupNumber,
+			Intensity:   intensity,
+		}); err != nil {
+			return nil, controllerError(address, err)
+		}
+		if intensity > 0 {
+			b.mu.Lock()
+			b.lastOn[g.GroupNumber] = intensity
+			b.mu.Unlock()
+		}
+		return successes(address, c, intensity > 0, IntensityToBri(intensity)), nil
+	}
+	return nil, notAvailable("/lights/" + id)
+}
+
+// allGroup describes group 0 or 1, which both hold every light.
+func (b *Bridge) allGroup(ctx context.Context) (*group, error) {
+	lights, ids, err := b.lights(ctx)
+	if err != nil {
+		return nil, err
+	}
+	g := &group{
+		Name:   b.name(),
+		Lights: ids,
+		Type:   "Room",
+		Class:  "Garden",
+		State:  groupState{AllOn: len(ids) > 0},
+		Action: groupAction{Alert: "none", Bri: 1},
+	}
+	for _, id := range ids {
+		s := lights[id].State
+		g.State.AnyOn = g.State.AnyOn || s.On
+		g.State.AllOn = g.State.AllOn && s.On
+		if s.On {
+			g.Action.On = true
+			if s.Bri > g.Action.Bri {
+				g.Action.Bri = s.Bri
+			}
+		}
+	}
+	return g, nil
+}
+
+func (b *Bridge) getGroups(r *http.Request) (interface{}, error) {
+	g, err := b.allGroup(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	return map[string]*group{"1": g}, nil
+}
+
+func (b *Bridge) getGroup(r *http.Request) (interface{}, error) {
+	id := r.PathValue("id")
+	if id != "0" && id != "1" {
+		return nil, notAvailable("/groups/" + id)
+	}
+	g, err := b.allGroup(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	if id == "0" {
+		g.Name = "Group 0"
+		g.Type = "LightGroup"
+		g.Class = ""
+	}
+	return g, nil
+}
+
+func (b *Bridge) putGroupAction(r *http.Request) (interface{}, error) {
+	id := r.PathValue("id")
+	if id != "0" && id != "1" {
+		return nil, notAvailable("/groups/" + id)
+	}
+	address := "/groups/" + id + "/action"
+	c, err := decodeChange(r, address, true)
+	if err != nil {
+		return nil, err
+	}
+	ctx := r.Context()
+	on, bri := false, 0
+	switch {
+	case c.Scene != nil:
+		index, err := protocol.ParseThemeLetter(*c.Scene)
+		if err != nil {
+			return nil, notAvailable("/scenes/" + *c.Scene)
+		}
+		if _, err := b.Controller.IlluminateTheme(ctx, &protocol.IlluminateThemeRequest{ThemeIndex: index, OnOff: 1}); err != nil {
+			return nil, controllerError("/scenes/"+*c.Scene, err)
+		}
+		on = true
+	case c.On != nil && !*c.On:
+		if _, err := b.Controller.ExtinguishAll(ctx, &protocol.ExtinguishAllRequest{}); err != nil {
+			return nil, controllerError(address, err)
+		}
+	case c.Bri == nil && c.BriInc == nil && c.On != nil:
+		if _, err := b.Controller.IlluminateAll(ctx, &protocol.IlluminateAllRequest{}); err != nil {
+			return nil, controllerError(address, err)
+		}
+		on = true
+	default:
+		groups, err := b.fetchGroups(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range groups {
+			intensity := b.target(g.GroupNumber, g.Intensity, c)
+			if intensity == g.Intensity {
+				continue
+			}
+			if _, err := b.Controller.IlluminateGroup(ctx, &protocol.IlluminateGroupRequest{
+				GroupNumber: g.GroupNumber,
+				Intensity:   intensity,
+			}); err != nil {
+				return nil, controllerError(address, err)
+			}
+			if intensity > 0 {
+				on = true
+				b.mu.Lock()
+				b.lastOn[g.GroupNumber] = intensity
+				b.mu.Unlock()
+				if b := IntensityToBri(intensity); b > bri {
+					bri = b
+				}
+			}
+		}
+	}
+	if c.Bri != nil && bri == 0 {
+		bri = *c.Bri
+	}
+	return successes(address, c, on, bri), nil
+}
+
+func (b *Bridge) scenes(ctx context.Context) (map[string]*scene, error) {
+	_, ids, err := b.lights(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := b.Controller.ThemeListGet(ctx, &protocol.ThemeListGetRequest{})
+	if err != nil {
+		return nil, err
+	}
+	scenes := make(map[string]*scene, len(resp.ThemeList))
+	for _, t := range resp.ThemeList {
+		id := protocol.ThemeLetter(t.ThemeIndex)
+		if _, ok := scenes[id]; ok {
+			continue
+		}
+		scenes[id] = &scene{
+			Name:    t.Name,
+			Type:    "GroupScene",
+			Lights:  ids,
+			Owner:   "luxor",
+			Locked:  true,
+			Version: 2,
+		}
+	}
+	return scenes, nil
+}
+
+func (b *Bridge) getScenes(r *http.Request) (interface{}, error) {
+	return b.scenes(r.Context())
+}
+
+// getScene returns one scene, including its light states from ThemeGet.
+func (b *Bridge) getScene(r *http.Request) (interface{}, error) {
+	id := r.PathValue("id")
+	scenes, err := b.scenes(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	s, ok := scenes[id]
+	if !ok {
+		return nil, notAvailable("/scenes/" + id)
+	}
+	index, _ := protocol.ParseThemeLetter(id)
+	def, err := b.Controller.ThemeGet(r.Context(), &protocol.ThemeGetRequest{ThemeIndex: index})
+	if err != nil {
+		return nil, err
+	}
+	s.Lights = nil
+	s.LightStates = make(map[string]lightState)
+	for _, g := range def.Groups {
+		l := lightID(g.GroupNumber)
+		if _, ok := s.LightStates[l]; !ok {
+			s.Lights = append(s.Lights, l)
+		}
+		s.LightStates[l] = lightState{On: g.Intensity > 0, Bri: IntensityToBri(g.Intensity), Alert: "none", Mode: "homeautomation", Reachable: true}
+	}
+	sort.Slice(s.Lights, func(i, j int) bool {
+		a, _ := strconv.Atoi(s.Lights[i])
+		b, _ := strconv.Atoi(s.Lights[j])
+		return a < b
+	})
+	return s, nil
+}
+
+func (b *Bridge) getAll(r *http.Request) (interface{}, error) {
+	lights, _, err := b.lights(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	g, err := b.allGroup(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	scenes, err := b.scenes(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"lights":    lights,
+		"groups":    map[string]*group{"1": g},
+		"config":    b.config(),
+		"scenes":    scenes,
+		"schedules": map[string]interface{}{},
+		"sensors":   map[string]interface{}{},
+		"rules":     map[string]interface{}{},
+	}, nil
+}
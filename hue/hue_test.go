@@ -0,0 +1,214 @@
+package hue_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/scottlamb/luxor/emulator"
+	"github.com/scottlamb/luxor/hue"
+	"github.com/scottlamb/luxor/protocol"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func do(t *testing.T, method, url, body string) string {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	out, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func setup(t *testing.T) (*emulator.Controller, *hue.Bridge, string) {
+	ctx := context.Background()
+	e := emulator.New("test")
+	if _, err := e.GroupListAdd(ctx, &protocol.GroupListAddRequest{GroupNumber: 3, Name: "Path"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.ThemeListAdd(ctx, &protocol.ThemeListAddRequest{ThemeIndex: 1, Name: "Party"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.ThemeSet(ctx, &protocol.ThemeSetRequest{ThemeIndex: 1, Groups: []protocol.ThemeGroup{{GroupNumber: 3, Intensity: 80}}}); err != nil {
+		t.Fatal(err)
+	}
+	b := &hue.Bridge{Controller: e, SerialNumber: "001788a1b2c3"}
+	server := httptest.NewServer(b)
+	t.Cleanup(server.Close)
+	b.URLBase = server.URL + "/"
+	return e, b, server.URL
+}
+
+func intensity(t *testing.T, e *emulator.Controller) uint8 {
+	t.Helper()
+	groups, err := e.GroupListGet(context.Background(), &protocol.GroupListGetRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return groups.GroupList[0].Intensity
+}
+
+func TestBriConversion(t *testing.T) {
+	for _, tc := range []struct {
+		bri       int
+		intensity uint8
+	}{{1, 1}, {127, 50}, {254, 100}, {300, 100}} {
+		if got := hue.BriToIntensity(tc.bri); got != tc.intensity {
+			t.Errorf("BriToIntensity(%d) = %d; want %d", tc.bri, got, tc.intensity)
+		}
+	}
+	if got := hue.IntensityToBri(100); got != hue.MaxBri {
+		t.Errorf("IntensityToBri(100) = %d", got)
+	}
+	if got := hue.IntensityToBri(50); got != 127 {
+		t.Errorf("IntensityToBri(50) = %d", got)
+	}
+}
+
+func TestLights(t *testing.T) {
+	e, _, url := setup(t)
+	got := do(t, "POST", url+"/api", `{"devicetype":"remote#living room"}`)
+	var reg []struct {
+		Success struct{ Username string }
+	}
+	if err := json.Unmarshal([]byte(got), &reg); err != nil || len(reg) != 1 || reg[0].Success.Username == "" {
+		t.Fatalf("unexpected registration %s", got)
+	}
+	api := url + "/api/" + reg[0].Success.Username
+
+	got = do(t, "GET", api+"/lights", "")
+	var lights map[string]struct {
+		Name  string
+		State struct {
+			On  bool
+			Bri int
+		}
+	}
+	if err := json.Unmarshal([]byte(got), &lights); err != nil || lights["3"].Name != "Path" || lights["3"].State.On {
+		t.Fatalf("unexpected lights %s", got)
+	}
+
+	if got := do(t, "PUT", api+"/lights/3/state", `{"bri":127}`); !strings.Contains(got, `"type":201`) {
+		t.Errorf("dimming an unlit light should fail; got %s", got)
+	}
+	if got := do(t, "PUT", api+"/lights/3/state", `{"on":true,"bri":127}`); got != `[{"success":{"/lights/3/state/on":true}},{"success":{"/lights/3/state/bri":127}}]` {
+		t.Errorf("unexpected response %s", got)
+	}
+	if i := intensity(t, e); i != 50 {
+		t.Errorf("intensity %d; want 50", i)
+	}
+	do(t, "PUT", api+"/lights/3/state", `{"on":false}`)
+	if i := intensity(t, e); i != 0 {
+		t.Errorf("intensity %d; want 0", i)
+	}
+	do(t, "PUT", api+"/lights/3/state", `{"on":true}`)
+	if i := intensity(t, e); i != 50 {
+		t.Errorf("intensity %d; want previous 50", i)
+	}
+	do(t, "PUT", api+"/lights/3/state", `{"bri_inc":-64}`)
+	if i := intensity(t, e); i != 25 {
+		t.Errorf("intensity %d; want 25", i)
+	}
+
+	for _, tc := range []struct{ method, path, body, want string }{
+		{"GET", "/lights/9", "", `"type":3`},
+		{"PUT", "/lights/3/state", `{"hue":100}`, `"type":6`},
+		{"PUT", "/lights/3/state", `{"bri":"x"}`, `"type":7`},
+		{"DELETE", "/lights/3", "", `"type":4`},
+	} {
+		if got := do(t, tc.method, api+tc.path, tc.body); !strings.Contains(got, tc.want) {
+			t.Errorf("%s %s: got %s; want %s", tc.method, tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestGroupsAndScenes(t *testing.T) {
+	e, _, url := setup(t)
+	api := url + "/api/anyone"
+	got := do(t, "GET", api+"/scenes/B", "")
+	var s struct {
+		Name        string
+		Lights      []string
+		LightStates map[string]struct{ Bri int }
+	}
+	if err := json.Unmarshal([]byte(got), &s); err != nil || s.Name != "Party" || len(s.Lights) != 1 || s.LightStates["3"].Bri != 203 {
+		t.Errorf("unexpected scene %s", got)
+	}
+
+	do(t, "PUT", api+"/groups/0/action", `{"scene":"B"}`)
+	themes, err := e.ThemeListGet(context.Background(), &protocol.ThemeListGetRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if themes.ThemeList[0].OnOff != 1 || intensity(t, e) != 80 {
+		t.Errorf("scene not recalled: %+v", themes.ThemeList)
+	}
+	got = do(t, "GET", api+"/groups/1", "")
+	if !strings.Contains(got, `"any_on":true`) || !strings.Contains(got, `"lights":["3"]`) {
+		t.Errorf("unexpected group %s", got)
+	}
+
+	do(t, "PUT", api+"/groups/1/action", `{"on":false}`)
+	if i := intensity(t, e); i != 0 {
+		t.Errorf("intensity %d after all off", i)
+	}
+	if got := do(t, "PUT", api+"/groups/1/action", `{"scene":"Q"}`); !strings.Contains(got, `"type":3`) {
+		t.Errorf("recalling a missing scene: got %s", got)
+	}
+	if got := do(t, "GET", api+"/groups/2", ""); !strings.Contains(got, `"type":3`) {
+		t.Errorf("missing group: got %s", got)
+	}
+}
+
+func TestDiscovery(t *testing.T) {
+	_, b, url := setup(t)
+	got := do(t, "GET", url+"/description.xml", "")
+	for _, want := range []string{"<modelName>Philips hue bridge 2015</modelName>", "<serialNumber>001788a1b2c3</serialNumber>", "<URLBase>" + url + "/</URLBase>"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("description lacks %s:\n%s", want, got)
+		}
+	}
+
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go b.ServeSSDP(ctx, conn)
+
+	searcher, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer searcher.Close()
+	search := "M-SEARCH * HTTP/1.1\r\nHOST: 239.255.255.250:1900\r\nMAN: \"ssdp:discover\"\r\nMX: 1\r\nST: upnp:rootdevice\r\n\r\n"
+	if _, err := searcher.WriteTo([]byte(search), conn.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+	searcher.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 2048)
+	n, _, err := searcher.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := string(buf[:n])
+	for _, want := range []string{"LOCATION: " + url + "/description.xml", "hue-bridgeid: 001788FFFEA1B2C3", "ST: upnp:rootdevice"} {
+		if !strings.Contains(resp, want) {
+			t.Errorf("SSDP response lacks %s:\n%s", want, resp)
+		}
+	}
+}
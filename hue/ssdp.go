@@ -0,0 +1,136 @@
+package hue
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// SSDPAddr is the multicast address on which clients search for bridges.
+const SSDPAddr = "239.255.255.250:1900"
+
+type deviceDescription struct {
+	XMLName     xml.Name `xml:"urn:schemas-upnp-org:device-1-0 root"`
+	SpecVersion struct {
+		Major int `xml:"major"`
+		Minor int `xml:"minor"`
+	} `xml:"specVersion"`
+	URLBase string `xml:"URLBase"`
+	Device  struct {
+		DeviceType       string `xml:"deviceType"`
+		FriendlyName     string `xml:"friendlyName"`
+		Manufacturer     string `xml:"manufacturer"`
+		ManufacturerURL  string `xml:"manufacturerURL"`
+		ModelDescription string `xml:"modelDescription"`
+		ModelName        string `xml:"modelName"`
+		ModelNumber      string `xml:"modelNumber"`
+		ModelURL         string `xml:"modelURL"`
+		SerialNumber     string `xml:"serialNumber"`
+		UDN              string `xml:"UDN"`
+		PresentationURL  string `xml:"presentationURL"`
+	} `xml:"device"`
+}
+
+// serveDescription serves the UPnP description which SSDP responses point
+// to. Clients check the model name and number to decide this is a Hue
+// bridge.
+func (b *Bridge) serveDescription(w http.ResponseWriter, r *http.Request) {
+	var d deviceDescription
+	d.SpecVersion.Major = 1
+	d.URLBase = b.URLBase
+	d.Device.DeviceType = "urn:schemas-upnp-org:device:Basic:1"
+	d.Device.FriendlyName = b.name()
+	d.Device.Manufacturer = "Royal Philips Electronics"
+	d.Device.ManufacturerURL = "http://www.philips.com"
+	d.Device.ModelDescription = "Philips hue Personal Wireless Lighting"
+	d.Device.ModelName = "Philips hue bridge 2015"
+	d.Device.ModelNumber = "BSB002"
+	d.Device.ModelURL = "http://www.meethue.com"
+	d.Device.SerialNumber = strings.ToLower(b.SerialNumber)
+	d.Device.UDN = b.UDN()
+	d.Device.PresentationURL = "index.html"
+	out, err := xml.MarshalIndent(&d, "", "  ")
+	if err != nil {
+		panic(err) // the description always marshals.
+	}
+	w.Header().Set("Content-Type", "text/xml")
+	w.Write([]byte(xml.Header))
+	w.Write(out)
+}
+
+// ListenSSDP joins the SSDP multicast group on ifi (or the system default
+// interface, if nil) and answers searches until ctx is done.
+func (b *Bridge) ListenSSDP(ctx context.Context, ifi *net.Interface) error {
+	addr, err := net.ResolveUDPAddr("udp4", SSDPAddr)
+	if err != nil {
+		return err
+	}
+	conn, err := net.ListenMulticastUDP("udp4", ifi, addr)
+	if err != nil {
+		return err
+	}
+	return b.ServeSSDP(ctx, conn)
+}
+
+// ServeSSDP answers M-SEARCH requests received on conn until ctx is done,
+// then closes conn. Responses are sent by unicast to the searcher.
+func (b *Bridge) ServeSSDP(ctx context.Context, conn net.PacketConn) error {
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+	buf := make([]byte, 2048)
+	for {
+		n, from, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		req, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(buf[:n])))
+		if err != nil || req.Method != "M-SEARCH" || req.Header.Get("MAN") != `"ssdp:discover"` {
+			continue
+		}
+		for _, st := range b.searchTargets(req.Header.Get("ST")) {
+			conn.WriteTo(b.ssdpResponse(st), from)
+		}
+	}
+}
+
+// searchTargets returns the targets to answer for a search target. Like a
+// real bridge, it answers "ssdp:all" with all of them.
+func (b *Bridge) searchTargets(st string) []string {
+	all := []string{"upnp:rootdevice", b.UDN(), "urn:schemas-upnp-org:device:basic:1"}
+	if st == "ssdp:all" {
+		return all
+	}
+	for _, t := range all {
+		if strings.EqualFold(st, t) {
+			return []string{st}
+		}
+	}
+	return nil
+}
+
+func (b *Bridge) ssdpResponse(st string) []byte {
+	usn := b.UDN()
+	if st != usn {
+		usn += "::" + st
+	}
+	return []byte(fmt.Sprintf("HTTP/1.1 200 OK\r\n"+
+		"HOST: %s\r\n"+
+		"EXT:\r\n"+
+		"CACHE-CONTROL: max-age=100\r\n"+
+		"LOCATION: %sdescription.xml\r\n"+
+		"SERVER: Linux/3.14.0 UPnP/1.0 IpBridge/%s\r\n"+
+		"hue-bridgeid: %s\r\n"+
+		"ST: %s\r\n"+
+		"USN: %s\r\n"+
+		"\r\n", SSDPAddr, b.URLBase, apiVersion, b.BridgeID(), st, usn))
+}
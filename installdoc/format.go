@@ -0,0 +1,205 @@
+package installdoc
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Formats, as accepted by Sheet.Write.
+const (
+	FormatCSV      = "csv"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// Formats lists the supported formats.
+var Formats = []string{FormatCSV, FormatMarkdown, FormatHTML}
+
+// FormatForPath guesses a format from a file name's extension, returning ""
+// if it's not recognized.
+func FormatForPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV
+	case ".md", ".markdown":
+		return FormatMarkdown
+	case ".html", ".htm":
+		return FormatHTML
+	}
+	return ""
+}
+
+// Write writes the sheet in the given format.
+func (s *Sheet) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatCSV:
+		return s.WriteCSV(w)
+	case FormatMarkdown:
+		return s.WriteMarkdown(w)
+	case FormatHTML:
+		return s.WriteHTML(w)
+	}
+	return fmt.Errorf("unknown format %q; want one of %q", format, Formats)
+}
+
+// themeHeading labels a theme's column in the matrix.
+func themeHeading(t Theme) string {
+	return t.Letter + ": " + t.Name
+}
+
+// matrixCell describes a theme's setting for a group: its intensity, or ""
+// if the theme leaves the group alone.
+func matrixCell(t Theme, groupNumber uint8) string {
+	intensity, ok := t.Intensity(groupNumber)
+	if !ok {
+		return ""
+	}
+	return strconv.Itoa(int(intensity)) + "%"
+}
+
+func fixtures(g Group) string {
+	if g.Fixtures == 0 {
+		return ""
+	}
+	return strconv.Itoa(g.Fixtures)
+}
+
+// WriteCSV writes one row per group, with the group table's columns followed
+// by one column per theme giving the intensity it sets, so the file opens as
+// a single spreadsheet. Sheet and theme notes aren't included.
+func (s *Sheet) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := []string{"Number", "Name", "Order", "Intensity", "Fixtures", "Notes"}
+	for i := range s.Themes {
+		header = append(header, themeHeading(s.Themes[i]))
+	}
+	cw.Write(header)
+	for i := range s.Groups {
+		g := &s.Groups[i]
+		row := []string{
+			strconv.Itoa(int(g.GroupNumber)),
+			g.Name,
+			strconv.Itoa(g.Order),
+			strconv.Itoa(int(g.Intensity)),
+			fixtures(*g),
+			g.Notes,
+		}
+		for j := range s.Themes {
+			intensity, ok := s.Themes[j].Intensity(g.GroupNumber)
+			cell := ""
+			if ok {
+				cell = strconv.Itoa(int(intensity))
+			}
+			row = append(row, cell)
+		}
+		cw.Write(row)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// markdownEscaper escapes characters which would break a Markdown table cell
+// or be taken as formatting.
+var markdownEscaper = strings.NewReplacer(
+	"\\", "\\\\", "|", "\\|", "*", "\\*", "_", "\\_", "`", "\\`",
+	"[", "\\[", "]", "\\]", "<", "&lt;", "\n", " ",
+)
+
+// WriteMarkdown writes the sheet as a Markdown document with a group table,
+// a theme matrix, and theme notes.
+func (s *Sheet) WriteMarkdown(w io.Writer) error {
+	md := markdownEscaper.Replace
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", md(s.Title))
+	fmt.Fprintf(&b, "Controller %s, as of %s.\n\n", md(s.ControllerName), s.Generated.Format("January 2, 2006 15:04"))
+	if s.Notes != "" {
+		fmt.Fprintf(&b, "%s\n\n", strings.TrimSpace(s.Notes))
+	}
+
+	fmt.Fprintf(&b, "## Groups\n\n")
+	fmt.Fprintf(&b, "| Number | Name | Order | Intensity | Fixtures | Notes |\n")
+	fmt.Fprintf(&b, "| ---: | --- | ---: | ---: | ---: | --- |\n")
+	for i := range s.Groups {
+		g := &s.Groups[i]
+		fmt.Fprintf(&b, "| %d | %s | %d | %d%% | %s | %s |\n",
+			g.GroupNumber, md(g.Name), g.Order, g.Intensity, fixtures(*g), md(g.Notes))
+	}
+
+	if len(s.Themes) > 0 {
+		fmt.Fprintf(&b, "\n## Themes\n\n")
+		fmt.Fprintf(&b, "| Group |")
+		for i := range s.Themes {
+			fmt.Fprintf(&b, " %s |", md(themeHeading(s.Themes[i])))
+		}
+		fmt.Fprintf(&b, "\n| --- |%s\n", strings.Repeat(" ---: |", len(s.Themes)))
+		for i := range s.Groups {
+			g := &s.Groups[i]
+			fmt.Fprintf(&b, "| %d %s |", g.GroupNumber, md(g.Name))
+			for j := range s.Themes {
+				fmt.Fprintf(&b, " %s |", matrixCell(s.Themes[j], g.GroupNumber))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+		for i := range s.Themes {
+			t := &s.Themes[i]
+			if t.Notes != "" {
+				fmt.Fprintf(&b, "- **%s**: %s\n", md(themeHeading(*t)), md(t.Notes))
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteHTML writes the sheet as a standalone HTML page, styled for printing.
+func (s *Sheet) WriteHTML(w io.Writer) error {
+	return htmlTemplate.Execute(w, s)
+}
+
+var htmlTemplate = template.Must(template.New("sheet").Funcs(template.FuncMap{
+	"heading":   themeHeading,
+	"cell":      matrixCell,
+	"fixtures":  fixtures,
+	"paragraph": strings.TrimSpace,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #000; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #888; padding: 0.25em 0.6em; }
+th { background: #eee; }
+td.n { text-align: right; }
+.notes { white-space: pre-wrap; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>Controller {{.ControllerName}}, as of {{.Generated.Format "January 2, 2006 15:04"}}.</p>
+{{with .Notes}}<p class="notes">{{paragraph .}}</p>
+{{end}}
+<h2>Groups</h2>
+<table>
+<tr><th>Number</th><th>Name</th><th>Order</th><th>Intensity</th><th>Fixtures</th><th>Notes</th></tr>
+{{range .Groups}}<tr><td class="n">{{.GroupNumber}}</td><td>{{.Name}}</td><td class="n">{{.Order}}</td><td class="n">{{.Intensity}}%</td><td class="n">{{fixtures .}}</td><td>{{.Notes}}</td></tr>
+{{end}}</table>
+{{if .Themes}}
+<h2>Themes</h2>
+<table>
+<tr><th>Group</th>{{range .Themes}}<th>{{heading .}}</th>{{end}}</tr>
+{{$themes := .Themes}}{{range .Groups}}{{$g := .}}<tr><td>{{.GroupNumber}} {{.Name}}</td>{{range $themes}}<td class="n">{{cell . $g.GroupNumber}}</td>{{end}}</tr>
+{{end}}</table>
+{{range .Themes}}{{if .Notes}}<p><strong>{{heading .}}</strong>: {{.Notes}}</p>
+{{end}}{{end}}{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,245 @@
+// Package installdoc produces installation documentation: the sheet an
+// installer leaves with a homeowner, listing which group number is which
+// fixture and what each theme does. A Sheet is collected from a controller,
+// optionally merged with local Metadata such as notes and fixture counts
+// which the controller has no place to store, and written as CSV, Markdown,
+// or a standalone HTML file.
+package installdoc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/scottlamb/luxor/protocol"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Metadata is local information about an installation, read from a JSON file
+// such as:
+//
+//	{
+//	  "Site": "12 Elm Street",
+//	  "Notes": "Transformer is in the garage, left of the panel.",
+//	  "Groups": {
+//	    "1": {"Fixtures": 6, "Notes": "path lights along the driveway"},
+//	    "Patio": {"Fixtures": 4}
+//	  },
+//	  "Themes": {"C": {"Notes": "dusk to 11pm via the timer"}}
+//	}
+//
+// Groups are keyed by number or name, and themes by letter or name.
+type Metadata struct {
+	// Site titles the sheet; it defaults to the controller's name.
+	Site string `json:",omitempty"`
+
+	Notes  string                `json:",omitempty"`
+	Groups map[string]*GroupInfo `json:",omitempty"`
+	Themes map[string]*ThemeInfo `json:",omitempty"`
+}
+
+// GroupInfo is local information about one group.
+type GroupInfo struct {
+	// Fixtures is the number of fixtures wired to the group, or 0 if unknown.
+	Fixtures int    `json:",omitempty"`
+	Notes    string `json:",omitempty"`
+}
+
+// ThemeInfo is local information about one theme.
+type ThemeInfo struct {
+	Notes string `json:",omitempty"`
+}
+
+// LoadMetadata reads Metadata from a JSON file. Unknown fields are errors, to
+// catch typos.
+func LoadMetadata(path string) (*Metadata, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m := &Metadata{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(m); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	for k, g := range m.Groups {
+		if g == nil {
+			return nil, fmt.Errorf("%s: group %q is null", path, k)
+		}
+	}
+	for k, t := range m.Themes {
+		if t == nil {
+			return nil, fmt.Errorf("%s: theme %q is null", path, k)
+		}
+	}
+	return m, nil
+}
+
+// Sheet is the contents of the installation documentation.
+type Sheet struct {
+	Title          string
+	ControllerName string
+	Generated      time.Time
+	Notes          string `json:",omitempty"`
+
+	// Groups are in the controller's (UI) order.
+	Groups []Group
+
+	// Themes are in index order.
+	Themes []Theme
+
+	// Unmatched lists Metadata keys which matched no group or theme, as
+	// "group KEY" or "theme KEY".
+	Unmatched []string `json:",omitempty"`
+}
+
+// Group is a row of the group table.
+type Group struct {
+	GroupNumber uint8
+	Name        string
+
+	// Order is the group's position in the controller's list.
+	Order int
+
+	// Intensity is the group's intensity when the sheet was collected.
+	Intensity uint8
+
+	Fixtures int    `json:",omitempty"`
+	Notes    string `json:",omitempty"`
+}
+
+// Theme is a column of the theme matrix.
+type Theme struct {
+	ThemeIndex uint8
+	Letter     string
+	Name       string
+	Notes      string `json:",omitempty"`
+
+	// Groups is the theme's definition, as from ThemeGet.
+	Groups []protocol.ThemeGroup
+}
+
+// Intensity returns the intensity the theme sets for a group, and whether the
+// theme includes the group at all. Should the definition list a group more
+// than once, the last entry wins, as on the controller.
+func (t *Theme) Intensity(groupNumber uint8) (intensity uint8, ok bool) {
+	for _, g := range t.Groups {
+		if g.GroupNumber == groupNumber {
+			intensity, ok = g.Intensity, true
+		}
+	}
+	return intensity, ok
+}
+
+// Collect reads the groups and themes from a controller and merges in
+// metadata, which may be nil.
+func Collect(ctx context.Context, controller protocol.Controller, metadata *Metadata) (*Sheet, error) {
+	name, err := controller.ControllerName(ctx, &protocol.ControllerNameRequest{})
+	if err != nil {
+		return nil, err
+	}
+	groups, err := controller.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	if err != nil {
+		return nil, err
+	}
+	themes, err := controller.ThemeListGet(ctx, &protocol.ThemeListGetRequest{})
+	if err != nil {
+		return nil, err
+	}
+	s := &Sheet{
+		Title:          name.Controller,
+		ControllerName: name.Controller,
+		Generated:      time.Now(),
+	}
+	for i, g := range groups.GroupList {
+		s.Groups = append(s.Groups, Group{
+			GroupNumber: g.GroupNumber,
+			Name:        g.Name,
+			Order:       i,
+			Intensity:   g.Intensity,
+		})
+	}
+	for _, t := range themes.ThemeList {
+		resp, err := controller.ThemeGet(ctx, &protocol.ThemeGetRequest{ThemeIndex: t.ThemeIndex})
+		if err != nil {
+			return nil, fmt.Errorf("theme %s (%s): %w", protocol.ThemeLetter(t.ThemeIndex), t.Name, err)
+		}
+		s.Themes = append(s.Themes, Theme{
+			ThemeIndex: t.ThemeIndex,
+			Letter:     protocol.ThemeLetter(t.ThemeIndex),
+			Name:       t.Name,
+			Groups:     resp.Groups,
+		})
+	}
+	sort.SliceStable(s.Themes, func(i, j int) bool { return s.Themes[i].ThemeIndex < s.Themes[j].ThemeIndex })
+	if metadata != nil {
+		s.merge(metadata)
+	}
+	return s, nil
+}
+
+func (s *Sheet) merge(m *Metadata) {
+	if m.Site != "" {
+		s.Title = m.Site
+	}
+	s.Notes = m.Notes
+	for key, info := range m.Groups {
+		g := s.findGroup(key)
+		if g == nil {
+			s.Unmatched = append(s.Unmatched, "group "+key)
+			continue
+		}
+		g.Fixtures = info.Fixtures
+		g.Notes = info.Notes
+	}
+	for key, info := range m.Themes {
+		t := s.findTheme(key)
+		if t == nil {
+			s.Unmatched = append(s.Unmatched, "theme "+key)
+			continue
+		}
+		t.Notes = info.Notes
+	}
+	sort.Strings(s.Unmatched)
+}
+
+// findGroup finds a group by number or, failing that, by name. Names are
+// matched case-insensitively.
+func (s *Sheet) findGroup(key string) *Group {
+	if n, err := strconv.ParseUint(key, 10, 8); err == nil {
+		for i := range s.Groups {
+			if s.Groups[i].GroupNumber == uint8(n) {
+				return &s.Groups[i]
+			}
+		}
+	}
+	for i := range s.Groups {
+		if strings.EqualFold(s.Groups[i].Name, key) {
+			return &s.Groups[i]
+		}
+	}
+	return nil
+}
+
+// findTheme finds a theme by letter or, failing that, by name. Names are
+// matched case-insensitively.
+func (s *Sheet) findTheme(key string) *Theme {
+	if index, err := protocol.ParseThemeLetter(key); err == nil {
+		for i := range s.Themes {
+			if s.Themes[i].ThemeIndex == index {
+				return &s.Themes[i]
+			}
+		}
+	}
+	for i := range s.Themes {
+		if strings.EqualFold(s.Themes[i].Name, key) {
+			return &s.Themes[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package installdoc_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/scottlamb/luxor/emulator"
+	"github.com/scottlamb/luxor/installdoc"
+	"github.com/scottlamb/luxor/protocol"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func collect(t *testing.T, metadata *installdoc.Metadata) *installdoc.Sheet {
+	t.Helper()
+	ctx := context.Background()
+	e := emulator.New("Elm St")
+	for _, g := range []protocol.GroupListAddRequest{
+		{GroupNumber: 3, Name: "Front Path"},
+		{GroupNumber: 1, Name: "Patio | Deck"},
+	} {
+		if _, err := e.GroupListAdd(ctx, &g); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, th := range []protocol.ThemeListAddRequest{{ThemeIndex: 3, Name: "Late"}, {ThemeIndex: 0, Name: "Evening"}} {
+		if _, err := e.ThemeListAdd(ctx, &th); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := e.ThemeSet(ctx, &protocol.ThemeSetRequest{ThemeIndex: 0, Groups: []protocol.ThemeGroup{
+		{GroupNumber: 1, Intensity: 80},
+		{GroupNumber: 3, Intensity: 50},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.ThemeSet(ctx, &protocol.ThemeSetRequest{ThemeIndex: 3, Groups: []protocol.ThemeGroup{
+		{GroupNumber: 3, Intensity: 20},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	s, err := installdoc.Collect(ctx, e, metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Generated = time.Date(2024, 5, 1, 18, 30, 0, 0, time.UTC)
+	return s
+}
+
+func TestCollect(t *testing.T) {
+	s := collect(t, &installdoc.Metadata{
+		Site:  "12 Elm Street",
+		Notes: "Transformer is in the garage.",
+		Groups: map[string]*installdoc.GroupInfo{
+			"3":            {Fixtures: 6, Notes: "driveway"},
+			"patio | deck": {Fixtures: 4},
+			"Pool":         {Fixtures: 2},
+		},
+		Themes: map[string]*installdoc.ThemeInfo{
+			"a":    {Notes: "dusk to 11pm"},
+			"Late": {Notes: "after 11pm"},
+			"Q":    {Notes: "gone"},
+		},
+	})
+	if s.Title != "12 Elm Street" || s.ControllerName != "Elm St" {
+		t.Errorf("title %q, controller %q", s.Title, s.ControllerName)
+	}
+	wantGroups := []installdoc.Group{
+		{GroupNumber: 3, Name: "Front Path", Order: 0, Fixtures: 6, Notes: "driveway"},
+		{GroupNumber: 1, Name: "Patio | Deck", Order: 1, Fixtures: 4},
+	}
+	if !reflect.DeepEqual(s.Groups, wantGroups) {
+		t.Errorf("groups = %+v; want %+v", s.Groups, wantGroups)
+	}
+	var themes []string
+	for _, th := range s.Themes {
+		themes = append(themes, th.Letter+" "+th.Name+" "+th.Notes)
+	}
+	if want := []string{"A Evening dusk to 11pm", "D Late after 11pm"}; !reflect.DeepEqual(themes, want) {
+		t.Errorf("themes = %q; want %q", themes, want)
+	}
+	if want := []string{"group Pool", "theme Q"}; !reflect.DeepEqual(s.Unmatched, want) {
+		t.Errorf("unmatched = %q; want %q", s.Unmatched, want)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	s := collect(t, &installdoc.Metadata{
+		Groups: map[string]*installdoc.GroupInfo{"3": {Fixtures: 6, Notes: "driveway, 2700K"}},
+		Themes: map[string]*installdoc.ThemeInfo{"A": {Notes: "dusk to 11pm"}},
+	})
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{installdoc.FormatCSV, `Number,Name,Order,Intensity,Fixtures,Notes,A: Evening,D: Late
+3,Front Path,0,0,6,"driveway, 2700K",50,20
+1,Patio | Deck,1,0,,,80,
+`},
+		{installdoc.FormatMarkdown, `# Elm St
+
+Controller Elm St, as of May 1, 2024 18:30.
+
+## Groups
+
+| Number | Name | Order | Intensity | Fixtures | Notes |
+| ---: | --- | ---: | ---: | ---: | --- |
+| 3 | Front Path | 0 | 0% | 6 | driveway, 2700K |
+| 1 | Patio \| Deck | 1 | 0% |  |  |
+
+## Themes
+
+| Group | A: Evening | D: Late |
+| --- | ---: | ---: |
+| 3 Front Path | 50% | 20% |
+| 1 Patio \| Deck | 80% |  |
+
+- **A: Evening**: dusk to 11pm
+`},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := s.Write(&buf, test.format); err != nil {
+			t.Errorf("%s: %v", test.format, err)
+		} else if got := buf.String(); got != test.want {
+			t.Errorf("%s: got:\n%s\nwant:\n%s", test.format, got, test.want)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := s.Write(&buf, installdoc.FormatHTML); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"<title>Elm St</title>",
+		"<tr><th>Group</th><th>A: Evening</th><th>D: Late</th></tr>",
+		`<tr><td>1 Patio | Deck</td><td class="n">80%</td><td class="n"></td></tr>`,
+		"<p><strong>A: Evening</strong>: dusk to 11pm</p>",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("HTML lacks %q:\n%s", want, buf.String())
+		}
+	}
+
+	if err := s.Write(&buf, "pdf"); err == nil {
+		t.Error("pdf: expected error")
+	}
+}
+
+func TestLoadMetadata(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.json")
+	if err := os.WriteFile(good, []byte(`{"Site": "Elm", "Groups": {"1": {"Fixtures": 3}}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	m, err := installdoc.LoadMetadata(good)
+	if err != nil || m.Site != "Elm" || m.Groups["1"].Fixtures != 3 {
+		t.Errorf("LoadMetadata = %+v, %v", m, err)
+	}
+	for name, contents := range map[string]string{
+		"typo.json": `{"Groups": {"1": {"Fixture": 3}}}`,
+		"null.json": `{"Themes": {"A": null}}`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := installdoc.LoadMetadata(path); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,379 @@
+// Package live tracks a controller's group and theme state and streams
+// changes to clients as Server-Sent Events.
+//
+// A Tracker wraps a protocol.Controller. Mutations made through the Tracker
+// (for example by a rest.Gateway built on it) are noticed immediately;
+// changes made elsewhere, such as from the vendor's app, are noticed by
+// background polling of GroupListGet and ThemeListGet. Each change is a
+// numbered Event. Numbers restart with each Tracker, so a Tracker also has an
+// epoch which distinguishes its numbers from those of earlier processes. A
+// stream starts with a full Snapshot and continues with Events; a client
+// which reconnects with the last epoch and sequence number it saw receives
+// only the Events it missed, if they're still buffered.
+package live
+
+import (
+	"context"
+	"github.com/scottlamb/luxor/protocol"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultPollInterval = 10 * time.Second
+
+	// DefaultBufferSize is the number of recent events kept for resuming
+	// clients.
+	DefaultBufferSize = 1024
+)
+
+type Group struct {
+	GroupNumber uint8
+	Name        string
+	Intensity   uint8
+
+	// Order is the group's zero-based position in the app's UI.
+	Order int
+}
+
+type Theme struct {
+	ThemeIndex uint8
+	Name       string
+	OnOff      uint8
+
+	// Order is the theme's zero-based position in the app's UI.
+	Order int
+}
+
+// Snapshot is the full tracked state as of event Seq.
+type Snapshot struct {
+	// Epoch identifies the Tracker, as in Tracker.Epoch.
+	Epoch  string
+	Seq    uint64
+	Groups []Group
+	Themes []Theme
+}
+
+// Event is a change to one group or theme. Exactly one of Group and Theme is
+// set; if Removed, it holds the last known state before removal.
+type Event struct {
+	Seq     uint64
+	Group   *Group `json:",omitempty"`
+	Theme   *Theme `json:",omitempty"`
+	Removed bool   `json:",omitempty"`
+}
+
+// Tracker is a protocol.Controller which tracks the state of the controller
+// it wraps. Run must be called for background polling.
+type Tracker struct {
+	protocol.Controller
+
+	// PollInterval defaults to DefaultPollInterval.
+	PollInterval time.Duration
+
+	bufferSize int
+	epoch      string
+
+	// refreshMu serializes refreshes so their diffs apply in order.
+	refreshMu sync.Mutex
+
+	mu          sync.Mutex
+	seq         uint64
+	groups      []Group
+	themes      []Theme
+	events      []Event // ring of the most recent events, oldest first.
+	subscribers map[chan struct{}]bool
+}
+
+// NewTracker returns a tracker wrapping controller, buffering bufferSize
+// events (or DefaultBufferSize if zero) for resuming clients.
+func NewTracker(controller protocol.Controller, bufferSize int) *Tracker {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Tracker{
+		Controller:  controller,
+		bufferSize:  bufferSize,
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		subscribers: make(map[chan struct{}]bool),
+	}
+}
+
+// Epoch returns a string which is unique to this Tracker, so event sequence
+// numbers from a restarted process aren't mistaken for its own.
+func (t *Tracker) Epoch() string {
+	return t.epoch
+}
+
+// Run polls the controller until ctx is done.
+func (t *Tracker) Run(ctx context.Context) error {
+	interval := t.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := t.Refresh(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("live: refresh failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh polls the controller now, recording any changes as events.
+func (t *Tracker) Refresh(ctx context.Context) error {
+	t.refreshMu.Lock()
+	defer t.refreshMu.Unlock()
+	groupsResp, err := t.Controller.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	if err != nil {
+		return err
+	}
+	themesResp, err := t.Controller.ThemeListGet(ctx, &protocol.ThemeListGetRequest{})
+	if err != nil {
+		return err
+	}
+	groups := make([]Group, len(groupsResp.GroupList))
+	for i, g := range groupsResp.GroupList {
+		groups[i] = Group{GroupNumber: g.GroupNumber, Name: g.Name, Intensity: g.Intensity, Order: i}
+	}
+	var themes []Theme
+	seen := make(map[uint8]bool)
+	for _, th := range themesResp.ThemeList {
+		// Duplicate indexes are possible but ambiguous; track the first.
+		if seen[th.ThemeIndex] {
+			continue
+		}
+		seen[th.ThemeIndex] = true
+		themes = append(themes, Theme{ThemeIndex: th.ThemeIndex, Name: th.Name, OnOff: th.OnOff, Order: len(themes)})
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n := len(t.events)
+	t.diffGroups(groups)
+	t.diffThemes(themes)
+	t.groups = groups
+	t.themes = themes
+	if len(t.events) != n {
+		for ch := range t.subscribers {
+			select {
+			case ch <- struct{}{}:
+			default:
+			}
+		}
+	}
+	return nil
+}
+
+// append records an event. Caller must hold t.mu.
+func (t *Tracker) append(e Event) {
+	t.seq++
+	e.Seq = t.seq
+	if len(t.events) == t.bufferSize {
+		copy(t.events, t.events[1:])
+		t.events = t.events[:len(t.events)-1]
+	}
+	t.events = append(t.events, e)
+}
+
+// diffGroups records events for differences between t.groups and groups.
+// Caller must hold t.mu.
+func (t *Tracker) diffGroups(groups []Group) {
+	old := make(map[uint8]Group, len(t.groups))
+	for _, g := range t.groups {
+		old[g.GroupNumber] = g
+	}
+	for _, g := range groups {
+		if o, ok := old[g.GroupNumber]; !ok || o != g {
+			g := g
+			t.append(Event{Group: &g})
+		}
+		delete(old, g.GroupNumber)
+	}
+	for _, g := range t.groups {
+		if _, ok := old[g.GroupNumber]; ok {
+			g := g
+			t.append(Event{Group: &g, Removed: true})
+		}
+	}
+}
+
+// diffThemes records events for differences between t.themes and themes.
+// Caller must hold t.mu.
+func (t *Tracker) diffThemes(themes []Theme) {
+	old := make(map[uint8]Theme, len(t.themes))
+	for _, th := range t.themes {
+		old[th.ThemeIndex] = th
+	}
+	for _, th := range themes {
+		if o, ok := old[th.ThemeIndex]; !ok || o != th {
+			th := th
+			t.append(Event{Theme: &th})
+		}
+		delete(old, th.ThemeIndex)
+	}
+	for _, th := range t.themes {
+		if _, ok := old[th.ThemeIndex]; ok {
+			th := th
+			t.append(Event{Theme: &th, Removed: true})
+		}
+	}
+}
+
+// Snapshot returns the current state.
+func (t *Tracker) Snapshot() Snapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return Snapshot{
+		Epoch:  t.epoch,
+		Seq:    t.seq,
+		Groups: append([]Group{}, t.groups...),
+		Themes: append([]Theme{}, t.themes...),
+	}
+}
+
+// Since returns the events after seq, which is one of this Tracker's
+// sequence numbers (see Epoch). ok is false if some of them are no longer
+// buffered, in which case the caller should start over from a Snapshot.
+func (t *Tracker) Since(seq uint64) (events []Event, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if seq > t.seq {
+		return nil, false // from another Tracker, most likely.
+	}
+	if seq == t.seq {
+		return nil, true
+	}
+	if len(t.events) == 0 || t.events[0].Seq > seq+1 {
+		return nil, false
+	}
+	i := int(seq + 1 - t.events[0].Seq)
+	return append([]Event{}, t.events[i:]...), true
+}
+
+// Subscribe returns a channel which receives a value (coalesced) whenever
+// new events are recorded, and a function to stop receiving.
+func (t *Tracker) Subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+	t.mu.Lock()
+	t.subscribers[ch] = true
+	t.mu.Unlock()
+	return ch, func() {
+		t.mu.Lock()
+		delete(t.subscribers, ch)
+		t.mu.Unlock()
+	}
+}
+
+// refreshAfter refreshes after a successful mutation.
+func (t *Tracker) refreshAfter(ctx context.Context, err error) {
+	if err != nil {
+		return
+	}
+	if err := t.Refresh(ctx); err != nil {
+		log.Printf("live: refresh after mutation failed: %v", err)
+	}
+}
+
+// The methods below are all boilerplate: mutations which may change groups or
+// themes are passed through, then followed by a refresh.
+
+func (t *Tracker) ExtinguishAll(ctx context.Context, req *protocol.ExtinguishAllRequest) (*protocol.ExtinguishAllResponse, error) {
+	resp, err := t.Controller.ExtinguishAll(ctx, req)
+	t.refreshAfter(ctx, err)
+	return resp, err
+}
+
+func (t *Tracker) FlashLights(ctx context.Context, req *protocol.FlashLightsRequest) (*protocol.FlashLightsResponse, error) {
+	resp, err := t.Controller.FlashLights(ctx, req)
+	t.refreshAfter(ctx, err)
+	return resp, err
+}
+
+func (t *Tracker) GroupListAdd(ctx context.Context, req *protocol.GroupListAddRequest) (*protocol.GroupListAddResponse, error) {
+	resp, err := t.Controller.GroupListAdd(ctx, req)
+	t.refreshAfter(ctx, err)
+	return resp, err
+}
+
+func (t *Tracker) GroupListClear(ctx context.Context, req *protocol.GroupListClearRequest) (*protocol.GroupListClearResponse, error) {
+	resp, err := t.Controller.GroupListClear(ctx, req)
+	t.refreshAfter(ctx, err)
+	return resp, err
+}
+
+func (t *Tracker) GroupListDelete(ctx context.Context, req *protocol.GroupListDeleteRequest) (*protocol.GroupListDeleteResponse, error) {
+	resp, err := t.Controller.GroupListDelete(ctx, req)
+	t.refreshAfter(ctx, err)
+	return resp, err
+}
+
+func (t *Tracker) GroupListRename(ctx context.Context, req *protocol.GroupListRenameRequest) (*protocol.GroupListRenameResponse, error) {
+	resp, err := t.Controller.GroupListRename(ctx, req)
+	t.refreshAfter(ctx, err)
+	return resp, err
+}
+
+func (t *Tracker) GroupListReorder(ctx context.Context, req *protocol.GroupListReorderRequest) (*protocol.GroupListReorderResponse, error) {
+	resp, err := t.Controller.GroupListReorder(ctx, req)
+	t.refreshAfter(ctx, err)
+	return resp, err
+}
+
+func (t *Tracker) IlluminateAll(ctx context.Context, req *protocol.IlluminateAllRequest) (*protocol.IlluminateAllResponse, error) {
+	resp, err := t.Controller.IlluminateAll(ctx, req)
+	t.refreshAfter(ctx, err)
+	return resp, err
+}
+
+func (t *Tracker) IlluminateGroup(ctx context.Context, req *protocol.IlluminateGroupRequest) (*protocol.IlluminateGroupResponse, error) {
+	resp, err := t.Controller.IlluminateGroup(ctx, req)
+	t.refreshAfter(ctx, err)
+	return resp, err
+}
+
+func (t *Tracker) IlluminateTheme(ctx context.Context, req *protocol.IlluminateThemeRequest) (*protocol.IlluminateThemeResponse, error) {
+	resp, err := t.Controller.IlluminateTheme(ctx, req)
+	t.refreshAfter(ctx, err)
+	return resp, err
+}
+
+func (t *Tracker) ThemeListAdd(ctx context.Context, req *protocol.ThemeListAddRequest) (*protocol.ThemeListAddResponse, error) {
+	resp, err := t.Controller.ThemeListAdd(ctx, req)
+	t.refreshAfter(ctx, err)
+	return resp, err
+}
+
+func (t *Tracker) ThemeListClear(ctx context.Context, req *protocol.ThemeListClearRequest) (*protocol.ThemeListClearResponse, error) {
+	resp, err := t.Controller.ThemeListClear(ctx, req)
+	t.refreshAfter(ctx, err)
+	return resp, err
+}
+
+func (t *Tracker) ThemeListDelete(ctx context.Context, req *protocol.ThemeListDeleteRequest) (*protocol.ThemeListDeleteResponse, error) {
+	resp, err := t.Controller.ThemeListDelete(ctx, req)
+	t.refreshAfter(ctx, err)
+	return resp, err
+}
+
+func (t *Tracker) ThemeListRename(ctx context.Context, req *protocol.ThemeListRenameRequest) (*protocol.ThemeListRenameResponse, error) {
+	resp, err := t.Controller.ThemeListRename(ctx, req)
+	t.refreshAfter(ctx, err)
+	return resp, err
+}
+
+func (t *Tracker) ThemeListReorder(ctx context.Context, req *protocol.ThemeListReorderRequest) (*protocol.ThemeListReorderResponse, error) {
+	resp, err := t.Controller.ThemeListReorder(ctx, req)
+	t.refreshAfter(ctx, err)
+	return resp, err
+}
+
+// Ensure *Tracker implements protocol.Controller.
+var _ protocol.Controller = (*Tracker)(nil)
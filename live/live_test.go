@@ -0,0 +1,176 @@
+package live_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"github.com/scottlamb/luxor/emulator"
+	"github.com/scottlamb/luxor/live"
+	"github.com/scottlamb/luxor/protocol"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type sseEvent struct {
+	id, kind, data string
+}
+
+func readEvent(t *testing.T, r *bufio.Reader) sseEvent {
+	t.Helper()
+	var e sseEvent
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			if e.kind != "" {
+				return e
+			}
+		case strings.HasPrefix(line, "id: "):
+			e.id = line[len("id: "):]
+		case strings.HasPrefix(line, "event: "):
+			e.kind = line[len("event: "):]
+		case strings.HasPrefix(line, "data: "):
+			e.data = line[len("data: "):]
+		}
+	}
+}
+
+func connect(t *testing.T, url, lastID string) (*bufio.Reader, func()) {
+	t.Helper()
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastID != "" {
+		req.Header.Set("Last-Event-ID", lastID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bufio.NewReader(resp.Body), func() { resp.Body.Close() }
+}
+
+func TestStream(t *testing.T) {
+	ctx := context.Background()
+	e := emulator.New("test")
+	if _, err := e.GroupListAdd(ctx, &protocol.GroupListAddRequest{GroupNumber: 1, Name: "Path"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.ThemeListAdd(ctx, &protocol.ThemeListAddRequest{ThemeIndex: 0, Name: "Party"}); err != nil {
+		t.Fatal(err)
+	}
+	tracker := live.NewTracker(e, 0)
+	if err := tracker.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(tracker)
+	defer server.Close()
+
+	r, closeStream := connect(t, server.URL, "")
+	ev := readEvent(t, r)
+	var snapshot live.Snapshot
+	if err := json.Unmarshal([]byte(ev.data), &snapshot); err != nil {
+		t.Fatal(err)
+	}
+	epoch := tracker.Epoch()
+	if ev.kind != "snapshot" || ev.id != epoch+"-2" || snapshot.Epoch != epoch || snapshot.Seq != 2 ||
+		len(snapshot.Groups) != 1 || snapshot.Groups[0].Name != "Path" ||
+		len(snapshot.Themes) != 1 || snapshot.Themes[0].Name != "Party" {
+		t.Fatalf("unexpected snapshot %+v: %+v", ev, snapshot)
+	}
+
+	// A mutation through the tracker is streamed right away.
+	if _, err := tracker.IlluminateGroup(ctx, &protocol.IlluminateGroupRequest{GroupNumber: 1, Intensity: 40}); err != nil {
+		t.Fatal(err)
+	}
+	ev = readEvent(t, r)
+	var change live.Event
+	if err := json.Unmarshal([]byte(ev.data), &change); err != nil {
+		t.Fatal(err)
+	}
+	if ev.kind != "change" || change.Seq != 3 || change.Group == nil || change.Group.Intensity != 40 {
+		t.Fatalf("unexpected change %+v", ev)
+	}
+	closeStream()
+
+	// Changes made elsewhere are found by polling; a resuming client gets
+	// just those.
+	if _, err := e.ThemeListDelete(ctx, &protocol.ThemeListDeleteRequest{Name: "Party"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.GroupListRename(ctx, &protocol.GroupListRenameRequest{OldName: "Path", NewName: "Walk"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tracker.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	r, closeStream = connect(t, server.URL, epoch+"-3")
+	defer closeStream()
+	ev = readEvent(t, r)
+	change = live.Event{}
+	if err := json.Unmarshal([]byte(ev.data), &change); err != nil {
+		t.Fatal(err)
+	}
+	if ev.id != epoch+"-4" || change.Group == nil || change.Group.Name != "Walk" {
+		t.Errorf("unexpected change %+v", ev)
+	}
+	ev = readEvent(t, r)
+	change = live.Event{}
+	if err := json.Unmarshal([]byte(ev.data), &change); err != nil {
+		t.Fatal(err)
+	}
+	if ev.id != epoch+"-5" || change.Theme == nil || !change.Removed {
+		t.Errorf("unexpected change %+v", ev)
+	}
+}
+
+// TestStreamRestart checks that ids from another process, or from the
+// future, start the stream over rather than resuming it.
+func TestStreamRestart(t *testing.T) {
+	ctx := context.Background()
+	e := emulator.New("test")
+	if _, err := e.GroupListAdd(ctx, &protocol.GroupListAddRequest{GroupNumber: 1, Name: "Path"}); err != nil {
+		t.Fatal(err)
+	}
+	tracker := live.NewTracker(e, 0)
+	if err := tracker.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(tracker)
+	defer server.Close()
+	for _, lastID := range []string{"0", "earlier-0", "earlier-1", tracker.Epoch() + "-9"} {
+		r, closeStream := connect(t, server.URL, lastID)
+		ev := readEvent(t, r)
+		closeStream()
+		if ev.kind != "snapshot" || ev.id != tracker.Epoch()+"-1" {
+			t.Errorf("resuming from %q: got %+v; want snapshot", lastID, ev)
+		}
+	}
+}
+
+func TestSinceOverflow(t *testing.T) {
+	ctx := context.Background()
+	e := emulator.New("test")
+	tracker := live.NewTracker(e, 2)
+	for i := uint8(1); i <= 4; i++ {
+		if _, err := tracker.GroupListAdd(ctx, &protocol.GroupListAddRequest{GroupNumber: i, Name: string('A' + rune(i))}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if events, ok := tracker.Since(2); !ok || len(events) != 2 || events[0].Seq != 3 {
+		t.Errorf("Since(2) = %+v, %v", events, ok)
+	}
+	if _, ok := tracker.Since(1); ok {
+		t.Error("Since(1) should report events no longer buffered")
+	}
+	if _, ok := tracker.Since(10); ok {
+		t.Error("Since(10) should report a sequence number from the future")
+	}
+}
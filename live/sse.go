@@ -0,0 +1,124 @@
+package live
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// keepaliveInterval is how often an idle stream sends a comment line, so
+// proxies and clients don't consider it dead.
+const keepaliveInterval = 30 * time.Second
+
+// ServeHTTP streams the tracker's state as Server-Sent Events:
+//
+//	id: m3x1k2-17
+//	event: snapshot
+//	data: {"Epoch":"m3x1k2","Seq":17,"Groups":[...],"Themes":[...]}
+//
+//	id: m3x1k2-18
+//	event: change
+//	data: {"Seq":18,"Group":{"GroupNumber":3,"Name":"Patio","Intensity":60,"Order":0}}
+//
+// Each id is the tracker's epoch and the event's sequence number. A client
+// resuming a stream sends the last id it received in the Last-Event-ID
+// header (as browsers' EventSource does automatically) or the last_event_id
+// query parameter. If the id is from this tracker and the events since then
+// are still buffered, only they are sent; otherwise, as after a restart, the
+// stream starts over with a snapshot.
+func (t *Tracker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("last_event_id")
+	}
+	notify, unsubscribe := t.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	var seq uint64
+	resumed := false
+	if n, ok := t.parseID(lastID); ok {
+		var events []Event
+		if events, resumed = t.Since(n); resumed {
+			seq = n
+			if !writeEvents(w, events, t.epoch, &seq) {
+				return
+			}
+		}
+	}
+	if !resumed {
+		snapshot := t.Snapshot()
+		if !writeEvent(w, t.epoch, snapshot.Seq, "snapshot", &snapshot) {
+			return
+		}
+		seq = snapshot.Seq
+	}
+	flusher.Flush()
+
+	keepalive := time.NewTicker(keepaliveInterval)
+	defer keepalive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		case <-notify:
+			events, ok := t.Since(seq)
+			if !ok {
+				// This client fell further behind than the buffer.
+				snapshot := t.Snapshot()
+				if !writeEvent(w, t.epoch, snapshot.Seq, "snapshot", &snapshot) {
+					return
+				}
+				seq = snapshot.Seq
+			} else if !writeEvents(w, events, t.epoch, &seq) {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// parseID returns the sequence number of an event id, if it has this
+// tracker's epoch.
+func (t *Tracker) parseID(id string) (uint64, bool) {
+	epoch, seq, ok := strings.Cut(id, "-")
+	if !ok || epoch != t.epoch {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(seq, 10, 64)
+	return n, err == nil
+}
+
+func writeEvents(w http.ResponseWriter, events []Event, epoch string, seq *uint64) bool {
+	for i := range events {
+		if !writeEvent(w, epoch, events[i].Seq, "change", &events[i]) {
+			return false
+		}
+		*seq = events[i].Seq
+	}
+	return true
+}
+
+func writeEvent(w http.ResponseWriter, epoch string, seq uint64, kind string, v interface{}) bool {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err) // these types always marshal.
+	}
+	_, err = fmt.Fprintf(w, "id: %s-%d\nevent: %s\ndata: %s\n\n", epoch, seq, kind, data)
+	return err == nil
+}
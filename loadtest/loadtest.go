@@ -0,0 +1,558 @@
+// Package loadtest characterizes a controller's reliability under load. A
+// Test drives a mix of read and write calls at a target rate or concurrency,
+// optionally ramping up in stages, and measures latency percentiles, errors
+// by class and protocol status, and the point at which the controller stops
+// answering.
+//
+// Writes are non-destructive: each sets a group back to the intensity it had
+// when the test started, so the lights don't visibly change unless someone
+// else changes them during the test.
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"github.com/scottlamb/luxor/client"
+	"github.com/scottlamb/luxor/protocol"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Defaults for Test fields.
+const (
+	DefaultStageDuration     = 30 * time.Second
+	DefaultCallTimeout       = 5 * time.Second
+	DefaultUnresponsiveAfter = 5
+	DefaultRecoveryTimeout   = time.Minute
+
+	// DefaultRateConcurrency is the concurrency used with a Rate, bounding
+	// how many calls may be outstanding while the controller is slow.
+	DefaultRateConcurrency = 8
+)
+
+// Test describes a load test.
+type Test struct {
+	Controller protocol.Controller
+
+	// Rate, if non-zero, is the target calls per second. Calls are started
+	// on schedule whether or not earlier calls have finished, up to
+	// Concurrency outstanding at once; calls which can't start for lack of
+	// a free worker are counted as Skipped. If Rate is zero, Concurrency
+	// workers issue calls back to back.
+	Rate float64
+
+	// Concurrency defaults to DefaultRateConcurrency with a Rate, or 1
+	// without.
+	Concurrency int
+
+	// WriteFraction, from 0 to 1, is the fraction of calls which are writes.
+	WriteFraction float64
+
+	// Stages is the number of stages, at least 1. After each stage, Ramp is
+	// added to Rate or, if Rate is zero, to Concurrency.
+	Stages int
+	Ramp   float64
+
+	// StageDuration defaults to DefaultStageDuration.
+	StageDuration time.Duration
+
+	// CallTimeout bounds each call; it defaults to DefaultCallTimeout.
+	CallTimeout time.Duration
+
+	// UnresponsiveAfter is how many calls in a row must go unanswered for
+	// the controller to be considered to have stopped answering, ending
+	// the test. It defaults to DefaultUnresponsiveAfter.
+	UnresponsiveAfter int
+
+	// RecoveryTimeout is how long to wait for an unresponsive controller
+	// to answer again; it defaults to DefaultRecoveryTimeout. Negative
+	// means not to wait.
+	RecoveryTimeout time.Duration
+
+	// Progress, if non-nil, is called after each stage.
+	Progress func(*Stage)
+}
+
+// Result is the outcome of a Test, meant to be saved as JSON and compared
+// across firmware versions.
+type Result struct {
+	ControllerName string
+	Start          time.Time
+	Settings       Settings
+
+	Stages []*Stage
+	Total  Stats
+
+	// Unresponsive is non-nil if the controller stopped answering.
+	Unresponsive *Unresponsive `json:",omitempty"`
+}
+
+// Settings records the parameters a Test ran with, after defaults.
+type Settings struct {
+	Rate                   float64 `json:",omitempty"`
+	Concurrency            int
+	WriteFraction          float64
+	Stages                 int
+	Ramp                   float64 `json:",omitempty"`
+	StageSeconds           float64
+	CallTimeoutSeconds     float64
+	UnresponsiveAfter      int
+	Groups                 int
+	RecoveryTimeoutSeconds float64
+}
+
+// Stage is one stage of a Test.
+type Stage struct {
+	Stage       int     // 1-based.
+	Rate        float64 `json:",omitempty"`
+	Concurrency int
+
+	// StartSeconds is the stage's start, relative to the test's.
+	StartSeconds float64
+	Stats
+}
+
+// Stats summarizes calls.
+type Stats struct {
+	Calls  int // calls finished, whether or not they succeeded.
+	Reads  int
+	Writes int
+	Errors int
+
+	// Skipped counts calls not started because Concurrency calls were
+	// already outstanding, so the Rate couldn't be met.
+	Skipped int `json:",omitempty"`
+
+	Seconds float64
+
+	// CallsPerSecond is the rate of finished calls achieved.
+	CallsPerSecond float64
+
+	// Latency covers calls which were answered, successfully or not.
+	Latency *Latency `json:",omitempty"`
+
+	// ErrorClasses counts errors by client.ErrorClass.
+	ErrorClasses map[string]int `json:",omitempty"`
+
+	// Statuses counts errors by the controller's status, for errors of
+	// class client.ClassStatus.
+	Statuses map[int]int `json:",omitempty"`
+
+	latencies []time.Duration
+}
+
+// Latency is a latency distribution, in seconds.
+type Latency struct {
+	Min, Mean, P50, P90, P99, Max float64
+}
+
+// Unresponsive describes the controller's failure to answer.
+type Unresponsive struct {
+	Stage       int
+	Rate        float64 `json:",omitempty"`
+	Concurrency int
+
+	// AfterSeconds is when the first of the unanswered calls started,
+	// relative to the test's start, and AfterCalls is how many calls had
+	// finished by then.
+	AfterSeconds float64
+	AfterCalls   int
+
+	Error string
+
+	// Recovered is true if the controller answered again within the
+	// recovery timeout, RecoverySeconds after it was found unresponsive.
+	Recovered       bool
+	RecoverySeconds float64 `json:",omitempty"`
+}
+
+// unanswered is true for errors which suggest the controller didn't answer
+// at all, rather than answered with an error.
+func unanswered(err error) bool {
+	switch client.ErrorClass(err) {
+	case client.ClassTimeout, client.ClassConnect, client.ClassDNS, client.ClassOther:
+		return true
+	}
+	return false
+}
+
+// Run runs the test. It returns an error only if the test couldn't start,
+// as when the controller can't be reached at all; a controller which stops
+// answering during the test is reported in Result.Unresponsive.
+func (t *Test) Run(ctx context.Context) (*Result, error) {
+	t = t.withDefaults()
+	r := &Result{Start: time.Now()}
+	if _, err := t.call(ctx, func(ctx context.Context) error {
+		resp, err := t.Controller.ControllerName(ctx, &protocol.ControllerNameRequest{})
+		if err == nil {
+			r.ControllerName = resp.Controller
+		}
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	var groups []protocol.Group
+	if _, err := t.call(ctx, func(ctx context.Context) error {
+		resp, err := t.Controller.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+		if err == nil {
+			groups = resp.GroupList
+		}
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	writeFraction := t.WriteFraction
+	if len(groups) == 0 {
+		writeFraction = 0 // nothing to write harmlessly.
+	}
+	r.Settings = Settings{
+		Rate:                   t.Rate,
+		Concurrency:            t.Concurrency,
+		WriteFraction:          writeFraction,
+		Stages:                 t.Stages,
+		Ramp:                   t.Ramp,
+		StageSeconds:           t.StageDuration.Seconds(),
+		CallTimeoutSeconds:     t.CallTimeout.Seconds(),
+		UnresponsiveAfter:      t.UnresponsiveAfter,
+		Groups:                 len(groups),
+		RecoveryTimeoutSeconds: math.Max(t.RecoveryTimeout.Seconds(), 0),
+	}
+
+	run := &run{
+		test:          t,
+		groups:        groups,
+		writeFraction: writeFraction,
+		start:         r.Start,
+	}
+	rate, concurrency := t.Rate, t.Concurrency
+	for i := 1; i <= t.Stages && ctx.Err() == nil; i++ {
+		s := &Stage{Stage: i, Rate: rate, Concurrency: concurrency, StartSeconds: time.Since(r.Start).Seconds()}
+		r.Stages = append(r.Stages, s)
+		run.stage(ctx, s)
+		if t.Progress != nil {
+			t.Progress(s)
+		}
+		if run.unresponsive != nil {
+			r.Unresponsive = run.unresponsive
+			t.awaitRecovery(ctx, r.Unresponsive)
+			break
+		}
+		if t.Rate != 0 {
+			rate += t.Ramp
+		} else {
+			concurrency += int(math.Round(t.Ramp))
+		}
+		if rate < 0 || concurrency < 1 {
+			break
+		}
+	}
+	for _, s := range r.Stages {
+		r.Total.add(&s.Stats)
+	}
+	r.Total.finish(time.Since(r.Start))
+	return r, nil
+}
+
+// withDefaults returns a copy of t with defaults filled in.
+func (t *Test) withDefaults() *Test {
+	c := *t
+	t = &c
+	if t.Concurrency <= 0 {
+		t.Concurrency = 1
+		if t.Rate > 0 {
+			t.Concurrency = DefaultRateConcurrency
+		}
+	}
+	if t.Stages <= 0 {
+		t.Stages = 1
+	}
+	if t.StageDuration <= 0 {
+		t.StageDuration = DefaultStageDuration
+	}
+	if t.CallTimeout <= 0 {
+		t.CallTimeout = DefaultCallTimeout
+	}
+	if t.UnresponsiveAfter <= 0 {
+		t.UnresponsiveAfter = DefaultUnresponsiveAfter
+	}
+	if t.RecoveryTimeout == 0 {
+		t.RecoveryTimeout = DefaultRecoveryTimeout
+	}
+	return t
+}
+
+// call makes one call with the call timeout, returning its latency.
+func (t *Test) call(ctx context.Context, f func(context.Context) error) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.CallTimeout)
+	defer cancel()
+	start := time.Now()
+	err := f(ctx)
+	return time.Since(start), err
+}
+
+// awaitRecovery probes the controller until it answers or the recovery
+// timeout passes.
+func (t *Test) awaitRecovery(ctx context.Context, u *Unresponsive) {
+	if t.RecoveryTimeout < 0 {
+		return
+	}
+	start := time.Now()
+	deadline := start.Add(t.RecoveryTimeout)
+	for ctx.Err() == nil && time.Now().Before(deadline) {
+		_, err := t.call(ctx, func(ctx context.Context) error {
+			_, err := t.Controller.ControllerName(ctx, &protocol.ControllerNameRequest{})
+			return err
+		})
+		if !unanswered(err) {
+			u.Recovered = true
+			u.RecoverySeconds = time.Since(start).Seconds()
+			return
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// run is the state of a running Test.
+type run struct {
+	test          *Test
+	groups        []protocol.Group
+	writeFraction float64
+	start         time.Time
+
+	mu           sync.Mutex
+	n            int // calls started, to choose among calls.
+	calls        int // calls finished.
+	failing      int // unanswered calls in a row.
+	failingSince time.Time
+	failingCalls int
+	unresponsive *Unresponsive
+}
+
+// next returns the next call to make: every call is a read unless the
+// running fraction of writes is below writeFraction.
+func (r *run) next() (write bool, n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n = r.n
+	r.n++
+	write = math.Floor(float64(n+1)*r.writeFraction) > math.Floor(float64(n)*r.writeFraction)
+	return write, n
+}
+
+// reads are the calls used as reads, in rotation.
+var reads = []string{"GroupListGet", "ThemeListGet", "ControllerName"}
+
+func (r *run) do(ctx context.Context, write bool, n int) (method string, latency time.Duration, err error) {
+	c := r.test.Controller
+	if write {
+		g := r.groups[n%len(r.groups)]
+		latency, err = r.test.call(ctx, func(ctx context.Context) error {
+			_, err := c.IlluminateGroup(ctx, &protocol.IlluminateGroupRequest{GroupNumber: g.GroupNumber, Intensity: g.Intensity})
+			return err
+		})
+		return "IlluminateGroup", latency, err
+	}
+	method = reads[n%len(reads)]
+	latency, err = r.test.call(ctx, func(ctx context.Context) error {
+		var err error
+		switch method {
+		case "GroupListGet":
+			_, err = c.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+		case "ThemeListGet":
+			_, err = c.ThemeListGet(ctx, &protocol.ThemeListGetRequest{})
+		default:
+			_, err = c.ControllerName(ctx, &protocol.ControllerNameRequest{})
+		}
+		return err
+	})
+	return method, latency, err
+}
+
+// stage runs one stage, stopping early if the controller stops answering.
+func (r *run) stage(ctx context.Context, s *Stage) {
+	ctx, cancel := context.WithTimeout(ctx, r.test.StageDuration)
+	defer cancel()
+	start := time.Now()
+	var wg sync.WaitGroup
+	work := make(chan struct{})
+	worker := func() {
+		defer wg.Done()
+		for range work {
+			r.one(ctx, cancel, s)
+		}
+	}
+	wg.Add(s.Concurrency)
+	for i := 0; i < s.Concurrency; i++ {
+		go worker()
+	}
+	if s.Rate > 0 {
+		interval := time.Duration(float64(time.Second) / s.Rate)
+		ticker := time.NewTicker(interval)
+	schedule:
+		for {
+			select {
+			case work <- struct{}{}:
+			default:
+				r.mu.Lock()
+				s.Skipped++
+				r.mu.Unlock()
+			}
+			select {
+			case <-ctx.Done():
+				break schedule
+			case <-ticker.C:
+			}
+		}
+		ticker.Stop()
+	} else {
+	fill:
+		for {
+			select {
+			case <-ctx.Done():
+				break fill
+			case work <- struct{}{}:
+			}
+		}
+	}
+	close(work)
+	wg.Wait()
+	s.finish(time.Since(start))
+}
+
+// one makes one call and records its outcome in s.
+func (r *run) one(ctx context.Context, stop context.CancelFunc, s *Stage) {
+	if ctx.Err() != nil {
+		return
+	}
+	write, n := r.next()
+	callStart := time.Now()
+	_, latency, err := r.do(ctx, write, n)
+	if err != nil && ctx.Err() != nil {
+		return // cut short by the stage's end; don't count it.
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls++
+	s.Calls++
+	if write {
+		s.Writes++
+	} else {
+		s.Reads++
+	}
+	if err != nil {
+		s.Errors++
+		class := client.ErrorClass(err)
+		if s.ErrorClasses == nil {
+			s.ErrorClasses = make(map[string]int)
+		}
+		s.ErrorClasses[class]++
+		if class == client.ClassStatus {
+			if s.Statuses == nil {
+				s.Statuses = make(map[int]int)
+			}
+			s.Statuses[protocol.StatusOf(err)]++
+		}
+	}
+	if !unanswered(err) {
+		s.latencies = append(s.latencies, latency)
+		r.failing = 0
+		return
+	}
+	if r.failing == 0 || callStart.Before(r.failingSince) {
+		r.failingSince = callStart
+		r.failingCalls = r.calls - 1
+	}
+	r.failing++
+	if r.failing >= r.test.UnresponsiveAfter && r.unresponsive == nil {
+		r.unresponsive = &Unresponsive{
+			Stage:        s.Stage,
+			Rate:         s.Rate,
+			Concurrency:  s.Concurrency,
+			AfterSeconds: r.failingSince.Sub(r.start).Seconds(),
+			AfterCalls:   r.failingCalls,
+			Error:        err.Error(),
+		}
+		stop()
+	}
+}
+
+// add accumulates o's counts and latencies into s.
+func (s *Stats) add(o *Stats) {
+	s.Calls += o.Calls
+	s.Reads += o.Reads
+	s.Writes += o.Writes
+	s.Errors += o.Errors
+	s.Skipped += o.Skipped
+	for k, v := range o.ErrorClasses {
+		if s.ErrorClasses == nil {
+			s.ErrorClasses = make(map[string]int)
+		}
+		s.ErrorClasses[k] += v
+	}
+	for k, v := range o.Statuses {
+		if s.Statuses == nil {
+			s.Statuses = make(map[int]int)
+		}
+		s.Statuses[k] += v
+	}
+	s.latencies = append(s.latencies, o.latencies...)
+}
+
+// finish computes rates and the latency distribution.
+func (s *Stats) finish(elapsed time.Duration) {
+	s.Seconds = elapsed.Seconds()
+	if s.Seconds > 0 {
+		s.CallsPerSecond = float64(s.Calls) / s.Seconds
+	}
+	s.Latency = distribution(s.latencies)
+}
+
+func distribution(latencies []time.Duration) *Latency {
+	if len(latencies) == 0 {
+		return nil
+	}
+	sorted := append([]time.Duration(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var sum time.Duration
+	for _, l := range sorted {
+		sum += l
+	}
+	return &Latency{
+		Min:  sorted[0].Seconds(),
+		Mean: (sum / time.Duration(len(sorted))).Seconds(),
+		P50:  percentile(sorted, 50),
+		P90:  percentile(sorted, 90),
+		P99:  percentile(sorted, 99),
+		Max:  sorted[len(sorted)-1].Seconds(),
+	}
+}
+
+// percentile returns the p-th percentile of sorted latencies, by the
+// nearest-rank method.
+func percentile(sorted []time.Duration, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1].Seconds()
+}
+
+// String summarizes a stage for progress messages.
+func (s *Stage) String() string {
+	load := fmt.Sprintf("concurrency %d", s.Concurrency)
+	if s.Rate > 0 {
+		load = fmt.Sprintf("rate %.1f/s", s.Rate)
+	}
+	p50, p99 := "-", "-"
+	if s.Latency != nil {
+		p50 = time.Duration(s.Latency.P50 * float64(time.Second)).Round(time.Microsecond).String()
+		p99 = time.Duration(s.Latency.P99 * float64(time.Second)).Round(time.Microsecond).String()
+	}
+	return fmt.Sprintf("stage %d (%s): %d calls, %.1f/s, %d errors, %d skipped, p50 %s, p99 %s",
+		s.Stage, load, s.Calls, s.CallsPerSecond, s.Errors, s.Skipped, p50, p99)
+}
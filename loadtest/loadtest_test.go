@@ -0,0 +1,132 @@
+package loadtest_test
+
+import (
+	"context"
+	"github.com/scottlamb/luxor/emulator"
+	"github.com/scottlamb/luxor/loadtest"
+	"github.com/scottlamb/luxor/protocol"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	e := emulator.New("test")
+	for _, g := range []protocol.GroupListAddRequest{{GroupNumber: 1, Name: "Path"}, {GroupNumber: 2, Name: "Patio"}} {
+		if _, err := e.GroupListAdd(ctx, &g); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := e.IlluminateGroup(ctx, &protocol.IlluminateGroupRequest{GroupNumber: 2, Intensity: 40}); err != nil {
+		t.Fatal(err)
+	}
+	before, err := e.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var progress []int
+	test := &loadtest.Test{
+		Controller:    e,
+		Concurrency:   2,
+		WriteFraction: 0.25,
+		Stages:        2,
+		Ramp:          1,
+		StageDuration: 50 * time.Millisecond,
+		Progress:      func(s *loadtest.Stage) { progress = append(progress, s.Stage) },
+	}
+	r, err := test.Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.ControllerName != "test" || r.Unresponsive != nil || len(r.Stages) != 2 || !reflect.DeepEqual(progress, []int{1, 2}) {
+		t.Fatalf("unexpected result: %v, %+v", r.Stages, r.Unresponsive)
+	}
+	if r.Stages[0].Concurrency != 2 || r.Stages[1].Concurrency != 3 {
+		t.Errorf("concurrency didn't ramp: %d, %d", r.Stages[0].Concurrency, r.Stages[1].Concurrency)
+	}
+	total := r.Total
+	if total.Calls == 0 || total.Errors != 0 || total.Reads+total.Writes != total.Calls || total.Latency == nil {
+		t.Errorf("unexpected totals: %+v", total)
+	}
+	if want := total.Calls / 4; total.Writes < want-1 || total.Writes > want+1 {
+		t.Errorf("%d writes of %d calls; want about a quarter", total.Writes, total.Calls)
+	}
+	if l := total.Latency; !(l.Min <= l.P50 && l.P50 <= l.P90 && l.P90 <= l.P99 && l.P99 <= l.Max) {
+		t.Errorf("latencies out of order: %+v", l)
+	}
+	after, err := e.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(before.GroupList, after.GroupList) {
+		t.Errorf("writes changed groups: %+v -> %+v", before.GroupList, after.GroupList)
+	}
+}
+
+// flaky stops answering reads after some calls, then answers again after
+// some failures.
+type flaky struct {
+	protocol.Controller
+	mu                 sync.Mutex
+	answers, failures  int
+	failAfter, recover int
+}
+
+func (f *flaky) fail() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.answers >= f.failAfter && f.failures < f.recover {
+		f.failures++
+		return context.DeadlineExceeded
+	}
+	f.answers++
+	return nil
+}
+
+func (f *flaky) ControllerName(ctx context.Context, req *protocol.ControllerNameRequest) (*protocol.ControllerNameResponse, error) {
+	if err := f.fail(); err != nil {
+		return nil, err
+	}
+	return f.Controller.ControllerName(ctx, req)
+}
+
+func (f *flaky) GroupListGet(ctx context.Context, req *protocol.GroupListGetRequest) (*protocol.GroupListGetResponse, error) {
+	if err := f.fail(); err != nil {
+		return nil, err
+	}
+	return f.Controller.GroupListGet(ctx, req)
+}
+
+func (f *flaky) ThemeListGet(ctx context.Context, req *protocol.ThemeListGetRequest) (*protocol.ThemeListGetResponse, error) {
+	if err := f.fail(); err != nil {
+		return nil, err
+	}
+	return f.Controller.ThemeListGet(ctx, req)
+}
+
+func TestUnresponsive(t *testing.T) {
+	f := &flaky{Controller: emulator.New("test"), failAfter: 10, recover: 4}
+	test := &loadtest.Test{
+		Controller:        f,
+		Stages:            3,
+		StageDuration:     time.Second,
+		UnresponsiveAfter: 3,
+	}
+	r, err := test.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := r.Unresponsive
+	if u == nil {
+		t.Fatalf("not unresponsive: %v", r.Stages)
+	}
+	// Run makes two calls of its own before the test proper.
+	if u.Stage != 1 || u.AfterCalls != 8 || u.Error != context.DeadlineExceeded.Error() || !u.Recovered {
+		t.Errorf("unexpected unresponsive: %+v", u)
+	}
+	if len(r.Stages) != 1 || r.Total.Calls != 11 || r.Total.ErrorClasses["timeout"] != 3 {
+		t.Errorf("unexpected stages: %v", r.Stages)
+	}
+}
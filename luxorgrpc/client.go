@@ -0,0 +1,260 @@
+package luxorgrpc
+
+import (
+	"context"
+	"github.com/scottlamb/luxor/luxorpb"
+	"github.com/scottlamb/luxor/protocol"
+	"google.golang.org/grpc"
+)
+
+// *Controller implements protocol.ColorController by calling a remote gRPC
+// service, such as one created by NewServer.
+type Controller struct {
+	client luxorpb.ControllerClient
+}
+
+// NewController returns a controller which uses the given connection.
+func NewController(conn grpc.ClientConnInterface) *Controller {
+	return &Controller{client: luxorpb.NewControllerClient(conn)}
+}
+
+func (c *Controller) ColorListGet(ctx context.Context, req *protocol.ColorListGetRequest) (*protocol.ColorListGetResponse, error) {
+	pbResp, err := c.client.ColorListGet(ctx, &luxorpb.ColorListGetRequest{})
+	if err != nil {
+		return nil, err
+	}
+	var cv converter
+	resp := &protocol.ColorListGetResponse{Status: int(pbResp.Status), ColorList: cv.colors(pbResp.ColorList)}
+	if cv.err != nil {
+		return nil, cv.err
+	}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) ColorListSet(ctx context.Context, req *protocol.ColorListSetRequest) (*protocol.ColorListSetResponse, error) {
+	pbResp, err := c.client.ColorListSet(ctx, &luxorpb.ColorListSetRequest{ColorNumber: uint32(req.ColorNumber), Hue: uint32(req.Hue), Saturation: uint32(req.Saturation)})
+	if err != nil {
+		return nil, err
+	}
+	resp := &protocol.ColorListSetResponse{Status: int(pbResp.Status)}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+// The methods below are all boilerplate.
+
+func (c *Controller) AssignLight(ctx context.Context, req *protocol.AssignLightRequest) (*protocol.AssignLightResponse, error) {
+	pbResp, err := c.client.AssignLight(ctx, &luxorpb.AssignLightRequest{SerialNumber: int64(req.SerialNumber), GroupNumber: uint32(req.GroupNumber)})
+	if err != nil {
+		return nil, err
+	}
+	resp := &protocol.AssignLightResponse{Status: int(pbResp.Status)}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) ControllerName(ctx context.Context, req *protocol.ControllerNameRequest) (*protocol.ControllerNameResponse, error) {
+	pbResp, err := c.client.ControllerName(ctx, &luxorpb.ControllerNameRequest{})
+	if err != nil {
+		return nil, err
+	}
+	resp := &protocol.ControllerNameResponse{Status: int(pbResp.Status), Controller: pbResp.Controller}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) ExtinguishAll(ctx context.Context, req *protocol.ExtinguishAllRequest) (*protocol.ExtinguishAllResponse, error) {
+	pbResp, err := c.client.ExtinguishAll(ctx, &luxorpb.ExtinguishAllRequest{})
+	if err != nil {
+		return nil, err
+	}
+	resp := &protocol.ExtinguishAllResponse{Status: int(pbResp.Status)}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) FlashLights(ctx context.Context, req *protocol.FlashLightsRequest) (*protocol.FlashLightsResponse, error) {
+	pbResp, err := c.client.FlashLights(ctx, &luxorpb.FlashLightsRequest{OnOff: uint32(req.OnOff)})
+	if err != nil {
+		return nil, err
+	}
+	resp := &protocol.FlashLightsResponse{Status: int(pbResp.Status)}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) GroupListAdd(ctx context.Context, req *protocol.GroupListAddRequest) (*protocol.GroupListAddResponse, error) {
+	pbResp, err := c.client.GroupListAdd(ctx, &luxorpb.GroupListAddRequest{GroupNumber: uint32(req.GroupNumber), Name: req.Name, Type: uint32(req.Type)})
+	if err != nil {
+		return nil, err
+	}
+	resp := &protocol.GroupListAddResponse{Status: int(pbResp.Status)}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) GroupListClear(ctx context.Context, req *protocol.GroupListClearRequest) (*protocol.GroupListClearResponse, error) {
+	pbResp, err := c.client.GroupListClear(ctx, &luxorpb.GroupListClearRequest{})
+	if err != nil {
+		return nil, err
+	}
+	var cv converter
+	resp := &protocol.GroupListClearResponse{Status: int(pbResp.Status), GroupList: cv.groups(pbResp.GroupList)}
+	if cv.err != nil {
+		return nil, cv.err
+	}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) GroupListDelete(ctx context.Context, req *protocol.GroupListDeleteRequest) (*protocol.GroupListDeleteResponse, error) {
+	pbResp, err := c.client.GroupListDelete(ctx, &luxorpb.GroupListDeleteRequest{Name: req.Name})
+	if err != nil {
+		return nil, err
+	}
+	resp := &protocol.GroupListDeleteResponse{Status: int(pbResp.Status)}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) GroupListGet(ctx context.Context, req *protocol.GroupListGetRequest) (*protocol.GroupListGetResponse, error) {
+	pbResp, err := c.client.GroupListGet(ctx, &luxorpb.GroupListGetRequest{})
+	if err != nil {
+		return nil, err
+	}
+	var cv converter
+	resp := &protocol.GroupListGetResponse{Status: int(pbResp.Status), GroupList: cv.groups(pbResp.GroupList)}
+	if cv.err != nil {
+		return nil, cv.err
+	}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) GroupListRename(ctx context.Context, req *protocol.GroupListRenameRequest) (*protocol.GroupListRenameResponse, error) {
+	pbResp, err := c.client.GroupListRename(ctx, &luxorpb.GroupListRenameRequest{OldName: req.OldName, NewName: req.NewName})
+	if err != nil {
+		return nil, err
+	}
+	resp := &protocol.GroupListRenameResponse{Status: int(pbResp.Status)}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) GroupListReorder(ctx context.Context, req *protocol.GroupListReorderRequest) (*protocol.GroupListReorderResponse, error) {
+	pbResp, err := c.client.GroupListReorder(ctx, &luxorpb.GroupListReorderRequest{GroupNumbers: uint32s(req.GroupNumbers)})
+	if err != nil {
+		return nil, err
+	}
+	resp := &protocol.GroupListReorderResponse{Status: int(pbResp.Status)}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) IlluminateAll(ctx context.Context, req *protocol.IlluminateAllRequest) (*protocol.IlluminateAllResponse, error) {
+	pbResp, err := c.client.IlluminateAll(ctx, &luxorpb.IlluminateAllRequest{})
+	if err != nil {
+		return nil, err
+	}
+	resp := &protocol.IlluminateAllResponse{Status: int(pbResp.Status)}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) IlluminateGroup(ctx context.Context, req *protocol.IlluminateGroupRequest) (*protocol.IlluminateGroupResponse, error) {
+	pbResp, err := c.client.IlluminateGroup(ctx, &luxorpb.IlluminateGroupRequest{GroupNumber: uint32(req.GroupNumber), Intensity: uint32(req.Intensity), Color: uint32(req.Color)})
+	if err != nil {
+		return nil, err
+	}
+	resp := &protocol.IlluminateGroupResponse{Status: int(pbResp.Status)}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) IlluminateTheme(ctx context.Context, req *protocol.IlluminateThemeRequest) (*protocol.IlluminateThemeResponse, error) {
+	pbResp, err := c.client.IlluminateTheme(ctx, &luxorpb.IlluminateThemeRequest{ThemeIndex: uint32(req.ThemeIndex), OnOff: uint32(req.OnOff)})
+	if err != nil {
+		return nil, err
+	}
+	resp := &protocol.IlluminateThemeResponse{Status: int(pbResp.Status)}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) ThemeClear(ctx context.Context, req *protocol.ThemeClearRequest) (*protocol.ThemeClearResponse, error) {
+	pbResp, err := c.client.ThemeClear(ctx, &luxorpb.ThemeClearRequest{ThemeIndex: uint32(req.ThemeIndex)})
+	if err != nil {
+		return nil, err
+	}
+	resp := &protocol.ThemeClearResponse{Status: int(pbResp.Status)}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) ThemeGet(ctx context.Context, req *protocol.ThemeGetRequest) (*protocol.ThemeGetResponse, error) {
+	pbResp, err := c.client.ThemeGet(ctx, &luxorpb.ThemeGetRequest{ThemeIndex: uint32(req.ThemeIndex)})
+	if err != nil {
+		return nil, err
+	}
+	var cv converter
+	resp := &protocol.ThemeGetResponse{Status: int(pbResp.Status), Groups: cv.themeGroups(pbResp.Groups)}
+	if cv.err != nil {
+		return nil, cv.err
+	}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) ThemeListAdd(ctx context.Context, req *protocol.ThemeListAddRequest) (*protocol.ThemeListAddResponse, error) {
+	pbResp, err := c.client.ThemeListAdd(ctx, &luxorpb.ThemeListAddRequest{ThemeIndex: uint32(req.ThemeIndex), Name: req.Name})
+	if err != nil {
+		return nil, err
+	}
+	resp := &protocol.ThemeListAddResponse{Status: int(pbResp.Status)}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) ThemeListClear(ctx context.Context, req *protocol.ThemeListClearRequest) (*protocol.ThemeListClearResponse, error) {
+	pbResp, err := c.client.ThemeListClear(ctx, &luxorpb.ThemeListClearRequest{})
+	if err != nil {
+		return nil, err
+	}
+	resp := &protocol.ThemeListClearResponse{Status: int(pbResp.Status)}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) ThemeListDelete(ctx context.Context, req *protocol.ThemeListDeleteRequest) (*protocol.ThemeListDeleteResponse, error) {
+	pbResp, err := c.client.ThemeListDelete(ctx, &luxorpb.ThemeListDeleteRequest{Name: req.Name})
+	if err != nil {
+		return nil, err
+	}
+	resp := &protocol.ThemeListDeleteResponse{Status: int(pbResp.Status)}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) ThemeListGet(ctx context.Context, req *protocol.ThemeListGetRequest) (*protocol.ThemeListGetResponse, error) {
+	pbResp, err := c.client.ThemeListGet(ctx, &luxorpb.ThemeListGetRequest{})
+	if err != nil {
+		return nil, err
+	}
+	var cv converter
+	resp := &protocol.ThemeListGetResponse{Status: int(pbResp.Status), Restricted: int(pbResp.Restricted), ThemeList: cv.themes(pbResp.ThemeList)}
+	if cv.err != nil {
+		return nil, cv.err
+	}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) ThemeListRename(ctx context.Context, req *protocol.ThemeListRenameRequest) (*protocol.ThemeListRenameResponse, error) {
+	pbResp, err := c.client.ThemeListRename(ctx, &luxorpb.ThemeListRenameRequest{OldName: req.OldName, NewName: req.NewName})
+	if err != nil {
+		return nil, err
+	}
+	resp := &protocol.ThemeListRenameResponse{Status: int(pbResp.Status)}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) ThemeListReorder(ctx context.Context, req *protocol.ThemeListReorderRequest) (*protocol.ThemeListReorderResponse, error) {
+	pbResp, err := c.client.ThemeListReorder(ctx, &luxorpb.ThemeListReorderRequest{ThemeIndexes: uint32s(req.ThemeIndexes)})
+	if err != nil {
+		return nil, err
+	}
+	resp := &protocol.ThemeListReorderResponse{Status: int(pbResp.Status)}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+func (c *Controller) ThemeSet(ctx context.Context, req *protocol.ThemeSetRequest) (*protocol.ThemeSetResponse, error) {
+	pbResp, err := c.client.ThemeSet(ctx, &luxorpb.ThemeSetRequest{ThemeIndex: uint32(req.ThemeIndex), Groups: themeGroupsToPB(req.Groups)})
+	if err != nil {
+		return nil, err
+	}
+	resp := &protocol.ThemeSetResponse{Status: int(pbResp.Status)}
+	return resp, protocol.ErrorForStatus(resp.Status)
+}
+
+// Ensure *Controller implements protocol.ColorController.
+var _ protocol.ColorController = (*Controller)(nil)
@@ -0,0 +1,181 @@
+// Package luxorgrpc adapts protocol.Controller to and from the gRPC service
+// defined in package luxorpb, so code written against the interface can run
+// unchanged against a remote gateway.
+package luxorgrpc
+
+import (
+	"context"
+	"errors"
+	"github.com/scottlamb/luxor/luxorpb"
+	"github.com/scottlamb/luxor/protocol"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"math"
+)
+
+// serverError converts an error from a protocol.Controller method which
+// returned no response. If err carries an application-level status, it
+// returns that status to report in the response; otherwise, a gRPC error.
+func serverError(err error) (int32, error) {
+	if s := protocol.StatusOf(err); s > 0 {
+		return int32(s), nil
+	}
+	if err == nil {
+		return 0, status.Error(codes.Internal, "controller returned neither response nor error")
+	}
+	if _, ok := status.FromError(err); ok {
+		return 0, err // already a gRPC error, as from a chained Controller.
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return 0, status.FromContextError(err).Err()
+	}
+	return 0, status.Error(codes.Unavailable, err.Error())
+}
+
+// converter narrows protobuf's uint32s to uint8s, remembering the first
+// out-of-range value as an InvalidArgument error.
+type converter struct {
+	err error
+}
+
+func (c *converter) u8(field string, v uint32) uint8 {
+	if v > math.MaxUint8 && c.err == nil {
+		c.err = status.Errorf(codes.InvalidArgument, "%s %d is out of range", field, v)
+	}
+	return uint8(v)
+}
+
+func (c *converter) u16(field string, v uint32) uint16 {
+	if v > math.MaxUint16 && c.err == nil {
+		c.err = status.Errorf(codes.InvalidArgument, "%s %d is out of range", field, v)
+	}
+	return uint16(v)
+}
+
+func (c *converter) u8s(field string, vs []uint32) []uint8 {
+	if vs == nil {
+		return nil
+	}
+	out := make([]uint8, len(vs))
+	for i, v := range vs {
+		out[i] = c.u8(field, v)
+	}
+	return out
+}
+
+func (c *converter) groups(pbGroups []*luxorpb.Group) []protocol.Group {
+	if pbGroups == nil {
+		return nil
+	}
+	groups := make([]protocol.Group, len(pbGroups))
+	for i, g := range pbGroups {
+		groups[i] = protocol.Group{
+			GroupNumber: c.u8("GroupNumber", g.GroupNumber),
+			Intensity:   c.u8("Intensity", g.Intensity),
+			Name:        g.Name,
+			Type:        c.u8("Type", g.Type),
+			Color:       c.u8("Color", g.Color),
+		}
+	}
+	return groups
+}
+
+func (c *converter) themeGroups(pbGroups []*luxorpb.ThemeGroup) []protocol.ThemeGroup {
+	if pbGroups == nil {
+		return nil
+	}
+	groups := make([]protocol.ThemeGroup, len(pbGroups))
+	for i, g := range pbGroups {
+		groups[i] = protocol.ThemeGroup{
+			GroupNumber: c.u8("GroupNumber", g.GroupNumber),
+			Intensity:   c.u8("Intensity", g.Intensity),
+			Color:       c.u8("Color", g.Color),
+		}
+	}
+	return groups
+}
+
+func (c *converter) colors(pbColors []*luxorpb.Color) []protocol.Color {
+	if pbColors == nil {
+		return nil
+	}
+	colors := make([]protocol.Color, len(pbColors))
+	for i, color := range pbColors {
+		colors[i] = protocol.Color{
+			ColorNumber: c.u8("ColorNumber", color.ColorNumber),
+			Hue:         c.u16("Hue", color.Hue),
+			Saturation:  c.u8("Saturation", color.Saturation),
+		}
+	}
+	return colors
+}
+
+func (c *converter) themes(pbThemes []*luxorpb.Theme) []protocol.Theme {
+	if pbThemes == nil {
+		return nil
+	}
+	themes := make([]protocol.Theme, len(pbThemes))
+	for i, t := range pbThemes {
+		themes[i] = protocol.Theme{
+			Name:       t.Name,
+			ThemeIndex: c.u8("ThemeIndex", t.ThemeIndex),
+			OnOff:      c.u8("OnOff", t.OnOff),
+		}
+	}
+	return themes
+}
+
+func uint32s(vs []uint8) []uint32 {
+	if vs == nil {
+		return nil
+	}
+	out := make([]uint32, len(vs))
+	for i, v := range vs {
+		out[i] = uint32(v)
+	}
+	return out
+}
+
+func groupsToPB(groups []protocol.Group) []*luxorpb.Group {
+	if groups == nil {
+		return nil
+	}
+	out := make([]*luxorpb.Group, len(groups))
+	for i, g := range groups {
+		out[i] = &luxorpb.Group{GroupNumber: uint32(g.GroupNumber), Intensity: uint32(g.Intensity), Name: g.Name, Type: uint32(g.Type), Color: uint32(g.Color)}
+	}
+	return out
+}
+
+func themeGroupsToPB(groups []protocol.ThemeGroup) []*luxorpb.ThemeGroup {
+	if groups == nil {
+		return nil
+	}
+	out := make([]*luxorpb.ThemeGroup, len(groups))
+	for i, g := range groups {
+		out[i] = &luxorpb.ThemeGroup{GroupNumber: uint32(g.GroupNumber), Intensity: uint32(g.Intensity), Color: uint32(g.Color)}
+	}
+	return out
+}
+
+func colorsToPB(colors []protocol.Color) []*luxorpb.Color {
+	if colors == nil {
+		return nil
+	}
+	out := make([]*luxorpb.Color, len(colors))
+	for i, c := range colors {
+		out[i] = &luxorpb.Color{ColorNumber: uint32(c.ColorNumber), Hue: uint32(c.Hue), Saturation: uint32(c.Saturation)}
+	}
+	return out
+}
+
+func themesToPB(themes []protocol.Theme) []*luxorpb.Theme {
+	if themes == nil {
+		return nil
+	}
+	out := make([]*luxorpb.Theme, len(themes))
+	for i, t := range themes {
+		out[i] = &luxorpb.Theme{Name: t.Name, ThemeIndex: uint32(t.ThemeIndex), OnOff: uint32(t.OnOff)}
+	}
+	return out
+}
@@ -0,0 +1,161 @@
+package luxorgrpc_test
+
+import (
+	"context"
+	"errors"
+	"github.com/scottlamb/luxor/emulator"
+	"github.com/scottlamb/luxor/luxorgrpc"
+	"github.com/scottlamb/luxor/luxorpb"
+	"github.com/scottlamb/luxor/protocol"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"reflect"
+	"testing"
+)
+
+// dial serves controller over an in-memory connection and returns a client.
+func dial(t *testing.T, controller protocol.Controller) (*luxorgrpc.Controller, *grpc.ClientConn) {
+	lis := bufconn.Listen(1 << 16)
+	s := grpc.NewServer()
+	luxorgrpc.Register(s, controller)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return luxorgrpc.NewController(conn), conn
+}
+
+func TestRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	c, _ := dial(t, emulator.New("luxor"))
+	name, err := c.ControllerName(ctx, &protocol.ControllerNameRequest{})
+	if err != nil || name.Controller != "luxor" {
+		t.Fatalf("unexpected ControllerName result %+v, %v", name, err)
+	}
+	for _, g := range []protocol.GroupListAddRequest{{GroupNumber: 200, Name: "Path"}, {GroupNumber: 3, Name: "Patio"}} {
+		if _, err := c.GroupListAdd(ctx, &g); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := c.GroupListReorder(ctx, &protocol.GroupListReorderRequest{GroupNumbers: []uint8{3, 200}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.IlluminateGroup(ctx, &protocol.IlluminateGroupRequest{GroupNumber: 200, Intensity: 55}); err != nil {
+		t.Fatal(err)
+	}
+	groups, err := c.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantGroups := []protocol.Group{{GroupNumber: 3, Name: "Patio"}, {GroupNumber: 200, Intensity: 55, Name: "Path"}}
+	if !reflect.DeepEqual(groups.GroupList, wantGroups) {
+		t.Errorf("got groups %+v; want %+v", groups.GroupList, wantGroups)
+	}
+
+	if _, err := c.ThemeListAdd(ctx, &protocol.ThemeListAddRequest{ThemeIndex: 4, Name: "Party"}); err != nil {
+		t.Fatal(err)
+	}
+	themeGroups := []protocol.ThemeGroup{{GroupNumber: 3, Intensity: 10}, {GroupNumber: 200, Intensity: 90}}
+	if _, err := c.ThemeSet(ctx, &protocol.ThemeSetRequest{ThemeIndex: 4, Groups: themeGroups}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.IlluminateTheme(ctx, &protocol.IlluminateThemeRequest{ThemeIndex: 4, OnOff: 1}); err != nil {
+		t.Fatal(err)
+	}
+	theme, err := c.ThemeGet(ctx, &protocol.ThemeGetRequest{ThemeIndex: 4})
+	if err != nil || !reflect.DeepEqual(theme.Groups, themeGroups) {
+		t.Errorf("unexpected ThemeGet result %+v, %v", theme, err)
+	}
+	themes, err := c.ThemeListGet(ctx, &protocol.ThemeListGetRequest{})
+	wantThemes := []protocol.Theme{{Name: "Party", ThemeIndex: 4, OnOff: 1}}
+	if err != nil || !reflect.DeepEqual(themes.ThemeList, wantThemes) {
+		t.Errorf("unexpected ThemeListGet result %+v, %v", themes, err)
+	}
+}
+
+func TestColors(t *testing.T) {
+	ctx := context.Background()
+	c, _ := dial(t, emulator.NewZDC("luxor"))
+	if _, err := c.ColorListSet(ctx, &protocol.ColorListSetRequest{ColorNumber: 3, Hue: 300, Saturation: 90}); err != nil {
+		t.Fatal(err)
+	}
+	colors, err := c.ColorListGet(ctx, &protocol.ColorListGetRequest{})
+	if want := []protocol.Color{{ColorNumber: 3, Hue: 300, Saturation: 90}}; err != nil || !reflect.DeepEqual(colors.ColorList, want) {
+		t.Errorf("unexpected ColorListGet result %+v, %v", colors, err)
+	}
+	if _, err := c.GroupListAdd(ctx, &protocol.GroupListAddRequest{GroupNumber: 2, Name: "Trees", Type: protocol.GroupTypeColor}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.IlluminateGroup(ctx, &protocol.IlluminateGroupRequest{GroupNumber: 2, Intensity: 40, Color: 3}); err != nil {
+		t.Fatal(err)
+	}
+	groups, err := c.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	wantGroups := []protocol.Group{{GroupNumber: 2, Intensity: 40, Name: "Trees", Type: protocol.GroupTypeColor, Color: 3}}
+	if err != nil || !reflect.DeepEqual(groups.GroupList, wantGroups) {
+		t.Errorf("unexpected GroupListGet result %+v, %v", groups, err)
+	}
+	if _, err := c.ThemeListAdd(ctx, &protocol.ThemeListAddRequest{ThemeIndex: 1, Name: "Holiday"}); err != nil {
+		t.Fatal(err)
+	}
+	themeGroups := []protocol.ThemeGroup{{GroupNumber: 2, Intensity: 70, Color: 3}}
+	if _, err := c.ThemeSet(ctx, &protocol.ThemeSetRequest{ThemeIndex: 1, Groups: themeGroups}); err != nil {
+		t.Fatal(err)
+	}
+	theme, err := c.ThemeGet(ctx, &protocol.ThemeGetRequest{ThemeIndex: 1})
+	if err != nil || !reflect.DeepEqual(theme.Groups, themeGroups) {
+		t.Errorf("unexpected ThemeGet result %+v, %v", theme, err)
+	}
+
+	// A server whose controller has no color methods reports so.
+	zd, _ := dial(t, struct{ protocol.Controller }{emulator.New("luxor")})
+	if _, err := zd.ColorListGet(ctx, &protocol.ColorListGetRequest{}); protocol.StatusOf(err) != protocol.StatusUnknownMethod {
+		t.Errorf("expected unknown method; got %v", err)
+	}
+}
+
+func TestStatusPreserved(t *testing.T) {
+	ctx := context.Background()
+	c, _ := dial(t, emulator.New("luxor"))
+	resp, err := c.ThemeGet(ctx, &protocol.ThemeGetRequest{ThemeIndex: 30})
+	if resp == nil || resp.Status != protocol.StatusThemeIndexOutOfRange ||
+		protocol.StatusOf(err) != protocol.StatusThemeIndexOutOfRange {
+		t.Errorf("expected theme index out of range; got %+v, %v", resp, err)
+	}
+	if _, err := c.GroupListDelete(ctx, &protocol.GroupListDeleteRequest{Name: "nope"}); protocol.StatusOf(err) != protocol.StatusPreconditionFailed {
+		t.Errorf("expected precondition failed; got %v", err)
+	}
+}
+
+type failingController struct {
+	protocol.Controller
+}
+
+func (failingController) GroupListGet(ctx context.Context, req *protocol.GroupListGetRequest) (*protocol.GroupListGetResponse, error) {
+	return nil, errors.New("no route to host")
+}
+
+func TestTransportError(t *testing.T) {
+	_, conn := dial(t, failingController{})
+	_, err := luxorpb.NewControllerClient(conn).GroupListGet(context.Background(), &luxorpb.GroupListGetRequest{})
+	if s, _ := status.FromError(err); s.Code() != codes.Unavailable || s.Message() != "no route to host" {
+		t.Errorf("expected unavailable; got %v", err)
+	}
+}
+
+func TestOutOfRange(t *testing.T) {
+	_, conn := dial(t, emulator.New("luxor"))
+	_, err := luxorpb.NewControllerClient(conn).IlluminateGroup(context.Background(),
+		&luxorpb.IlluminateGroupRequest{GroupNumber: 256, Intensity: 50})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected invalid argument; got %v", err)
+	}
+}
@@ -0,0 +1,413 @@
+package luxorgrpc
+
+import (
+	"context"
+	"github.com/scottlamb/luxor/luxorpb"
+	"github.com/scottlamb/luxor/protocol"
+	"google.golang.org/grpc"
+)
+
+type server struct {
+	luxorpb.UnimplementedControllerServer
+	controller protocol.Controller
+}
+
+// NewServer returns a gRPC service implementation which forwards each call to
+// controller. Register it with luxorpb.RegisterControllerServer. The color
+// methods answer with StatusUnknownMethod unless controller implements
+// protocol.ColorController.
+//
+// Application-level failures are reported through each response's Status
+// field with an OK gRPC status, just as the JSON protocol reports them with
+// HTTP 200. Other failures, such as an unreachable controller, become gRPC
+// errors.
+func NewServer(controller protocol.Controller) luxorpb.ControllerServer {
+	return &server{controller: controller}
+}
+
+// Register is shorthand for registering NewServer(controller) with s.
+func Register(s grpc.ServiceRegistrar, controller protocol.Controller) {
+	luxorpb.RegisterControllerServer(s, NewServer(controller))
+}
+
+// colorController returns s.controller as a ColorController, or a status
+// response for a controller without color methods.
+func (s *server) colorController() (protocol.ColorController, int32) {
+	if c, ok := s.controller.(protocol.ColorController); ok {
+		return c, 0
+	}
+	return nil, protocol.StatusUnknownMethod
+}
+
+func (s *server) ColorListGet(ctx context.Context, pbReq *luxorpb.ColorListGetRequest) (*luxorpb.ColorListGetResponse, error) {
+	c, status := s.colorController()
+	if c == nil {
+		return &luxorpb.ColorListGetResponse{Status: status}, nil
+	}
+	resp, err := c.ColorListGet(ctx, &protocol.ColorListGetRequest{})
+	if resp == nil {
+		status, err := serverError(err)
+		if err != nil {
+			return nil, err
+		}
+		return &luxorpb.ColorListGetResponse{Status: status}, nil
+	}
+	return &luxorpb.ColorListGetResponse{Status: int32(resp.Status), ColorList: colorsToPB(resp.ColorList)}, nil
+}
+
+func (s *server) ColorListSet(ctx context.Context, pbReq *luxorpb.ColorListSetRequest) (*luxorpb.ColorListSetResponse, error) {
+	var cv converter
+	req := &protocol.ColorListSetRequest{
+		ColorNumber: cv.u8("ColorNumber", pbReq.ColorNumber),
+		Hue:         cv.u16("Hue", pbReq.Hue),
+		Saturation:  cv.u8("Saturation", pbReq.Saturation),
+	}
+	if cv.err != nil {
+		return nil, cv.err
+	}
+	c, status := s.colorController()
+	if c == nil {
+		return &luxorpb.ColorListSetResponse{Status: status}, nil
+	}
+	resp, err := c.ColorListSet(ctx, req)
+	if resp == nil {
+		status, err := serverError(err)
+		if err != nil {
+			return nil, err
+		}
+		return &luxorpb.ColorListSetResponse{Status: status}, nil
+	}
+	return &luxorpb.ColorListSetResponse{Status: int32(resp.Status)}, nil
+}
+
+// The methods below are all boilerplate.
+
+func (s *server) AssignLight(ctx context.Context, pbReq *luxorpb.AssignLightRequest) (*luxorpb.AssignLightResponse, error) {
+	var cv converter
+	req := &protocol.AssignLightRequest{SerialNumber: int(pbReq.SerialNumber), GroupNumber: cv.u8("GroupNumber", pbReq.GroupNumber)}
+	if cv.err != nil {
+		return nil, cv.err
+	}
+	resp, err := s.controller.AssignLight(ctx, req)
+	if resp == nil {
+		status, err := serverError(err)
+		if err != nil {
+			return nil, err
+		}
+		return &luxorpb.AssignLightResponse{Status: status}, nil
+	}
+	return &luxorpb.AssignLightResponse{Status: int32(resp.Status)}, nil
+}
+
+func (s *server) ControllerName(ctx context.Context, pbReq *luxorpb.ControllerNameRequest) (*luxorpb.ControllerNameResponse, error) {
+	req := &protocol.ControllerNameRequest{}
+	resp, err := s.controller.ControllerName(ctx, req)
+	if resp == nil {
+		status, err := serverError(err)
+		if err != nil {
+			return nil, err
+		}
+		return &luxorpb.ControllerNameResponse{Status: status}, nil
+	}
+	return &luxorpb.ControllerNameResponse{Status: int32(resp.Status), Controller: resp.Controller}, nil
+}
+
+func (s *server) ExtinguishAll(ctx context.Context, pbReq *luxorpb.ExtinguishAllRequest) (*luxorpb.ExtinguishAllResponse, error) {
+	req := &protocol.ExtinguishAllRequest{}
+	resp, err := s.controller.ExtinguishAll(ctx, req)
+	if resp == nil {
+		status, err := serverError(err)
+		if err != nil {
+			return nil, err
+		}
+		return &luxorpb.ExtinguishAllResponse{Status: status}, nil
+	}
+	return &luxorpb.ExtinguishAllResponse{Status: int32(resp.Status)}, nil
+}
+
+func (s *server) FlashLights(ctx context.Context, pbReq *luxorpb.FlashLightsRequest) (*luxorpb.FlashLightsResponse, error) {
+	var cv converter
+	req := &protocol.FlashLightsRequest{OnOff: cv.u8("OnOff", pbReq.OnOff)}
+	if cv.err != nil {
+		return nil, cv.err
+	}
+	resp, err := s.controller.FlashLights(ctx, req)
+	if resp == nil {
+		status, err := serverError(err)
+		if err != nil {
+			return nil, err
+		}
+		return &luxorpb.FlashLightsResponse{Status: status}, nil
+	}
+	return &luxorpb.FlashLightsResponse{Status: int32(resp.Status)}, nil
+}
+
+func (s *server) GroupListAdd(ctx context.Context, pbReq *luxorpb.GroupListAddRequest) (*luxorpb.GroupListAddResponse, error) {
+	var cv converter
+	req := &protocol.GroupListAddRequest{GroupNumber: cv.u8("GroupNumber", pbReq.GroupNumber), Name: pbReq.Name, Type: cv.u8("Type", pbReq.Type)}
+	if cv.err != nil {
+		return nil, cv.err
+	}
+	resp, err := s.controller.GroupListAdd(ctx, req)
+	if resp == nil {
+		status, err := serverError(err)
+		if err != nil {
+			return nil, err
+		}
+		return &luxorpb.GroupListAddResponse{Status: status}, nil
+	}
+	return &luxorpb.GroupListAddResponse{Status: int32(resp.Status)}, nil
+}
+
+func (s *server) GroupListClear(ctx context.Context, pbReq *luxorpb.GroupListClearRequest) (*luxorpb.GroupListClearResponse, error) {
+	req := &protocol.GroupListClearRequest{}
+	resp, err := s.controller.GroupListClear(ctx, req)
+	if resp == nil {
+		status, err := serverError(err)
+		if err != nil {
+			return nil, err
+		}
+		return &luxorpb.GroupListClearResponse{Status: status}, nil
+	}
+	return &luxorpb.GroupListClearResponse{Status: int32(resp.Status), GroupList: groupsToPB(resp.GroupList)}, nil
+}
+
+func (s *server) GroupListDelete(ctx context.Context, pbReq *luxorpb.GroupListDeleteRequest) (*luxorpb.GroupListDeleteResponse, error) {
+	req := &protocol.GroupListDeleteRequest{Name: pbReq.Name}
+	resp, err := s.controller.GroupListDelete(ctx, req)
+	if resp == nil {
+		status, err := serverError(err)
+		if err != nil {
+			return nil, err
+		}
+		return &luxorpb.GroupListDeleteResponse{Status: status}, nil
+	}
+	return &luxorpb.GroupListDeleteResponse{Status: int32(resp.Status)}, nil
+}
+
+func (s *server) GroupListGet(ctx context.Context, pbReq *luxorpb.GroupListGetRequest) (*luxorpb.GroupListGetResponse, error) {
+	req := &protocol.GroupListGetRequest{}
+	resp, err := s.controller.GroupListGet(ctx, req)
+	if resp == nil {
+		status, err := serverError(err)
+		if err != nil {
+			return nil, err
+		}
+		return &luxorpb.GroupListGetResponse{Status: status}, nil
+	}
+	return &luxorpb.GroupListGetResponse{Status: int32(resp.Status), GroupList: groupsToPB(resp.GroupList)}, nil
+}
+
+func (s *server) GroupListRename(ctx context.Context, pbReq *luxorpb.GroupListRenameRequest) (*luxorpb.GroupListRenameResponse, error) {
+	req := &protocol.GroupListRenameRequest{OldName: pbReq.OldName, NewName: pbReq.NewName}
+	resp, err := s.controller.GroupListRename(ctx, req)
+	if resp == nil {
+		status, err := serverError(err)
+		if err != nil {
+			return nil, err
+		}
+		return &luxorpb.GroupListRenameResponse{Status: status}, nil
+	}
+	return &luxorpb.GroupListRenameResponse{Status: int32(resp.Status)}, nil
+}
+
+func (s *server) GroupListReorder(ctx context.Context, pbReq *luxorpb.GroupListReorderRequest) (*luxorpb.GroupListReorderResponse, error) {
+	var cv converter
+	req := &protocol.GroupListReorderRequest{GroupNumbers: cv.u8s("GroupNumbers", pbReq.GroupNumbers)}
+	if cv.err != nil {
+		return nil, cv.err
+	}
+	resp, err := s.controller.GroupListReorder(ctx, req)
+	if resp == nil {
+		status, err := serverError(err)
+		if err != nil {
+			return nil, err
+		}
+		return &luxorpb.GroupListReorderResponse{Status: status}, nil
+	}
+	return &luxorpb.GroupListReorderResponse{Status: int32(resp.Status)}, nil
+}
+
+func (s *server) IlluminateAll(ctx context.Context, pbReq *luxorpb.IlluminateAllRequest) (*luxorpb.IlluminateAllResponse, error) {
+	req := &protocol.IlluminateAllRequest{}
+	resp, err := s.controller.IlluminateAll(ctx, req)
+	if resp == nil {
+		status, err := serverError(err)
+		if err != nil {
+			return nil, err
+		}
+		return &luxorpb.IlluminateAllResponse{Status: status}, nil
+	}
+	return &luxorpb.IlluminateAllResponse{Status: int32(resp.Status)}, nil
+}
+
+func (s *server) IlluminateGroup(ctx context.Context, pbReq *luxorpb.IlluminateGroupRequest) (*luxorpb.IlluminateGroupResponse, error) {
+	var cv converter
+	req := &protocol.IlluminateGroupRequest{GroupNumber: cv.u8("GroupNumber", pbReq.GroupNumber), Intensity: cv.u8("Intensity", pbReq.Intensity), Color: cv.u8("Color", pbReq.Color)}
+	if cv.err != nil {
+		return nil, cv.err
+	}
+	resp, err := s.controller.IlluminateGroup(ctx, req)
+	if resp == nil {
+		status, err := serverError(err)
+		if err != nil {
+			return nil, err
+		}
+		return &luxorpb.IlluminateGroupResponse{Status: status}, nil
+	}
+	return &luxorpb.IlluminateGroupResponse{Status: int32(resp.Status)}, nil
+}
+
+func (s *server) IlluminateTheme(ctx context.Context, pbReq *luxorpb.IlluminateThemeRequest) (*luxorpb.IlluminateThemeResponse, error) {
+	var cv converter
+	req := &protocol.IlluminateThemeRequest{ThemeIndex: cv.u8("ThemeIndex", pbReq.ThemeIndex), OnOff: cv.u8("OnOff", pbReq.OnOff)}
+	if cv.err != nil {
+		return nil, cv.err
+	}
+	resp, err := s.controller.IlluminateTheme(ctx, req)
+	if resp == nil {
+		status, err := serverError(err)
+		if err != nil {
+			return nil, err
+		}
+		return &luxorpb.IlluminateThemeResponse{Status: status}, nil
+	}
+	return &luxorpb.IlluminateThemeResponse{Status: int32(resp.Status)}, nil
+}
+
+func (s *server) ThemeClear(ctx context.Context, pbReq *luxorpb.ThemeClearRequest) (*luxorpb.ThemeClearResponse, error) {
+	var cv converter
+	req := &protocol.ThemeClearRequest{ThemeIndex: cv.u8("ThemeIndex", pbReq.ThemeIndex)}
+	if cv.err != nil {
+		return nil, cv.err
+	}
+	resp, err := s.controller.ThemeClear(ctx, req)
+	if resp == nil {
+		status, err := serverError(err)
+		if err != nil {
+			return nil, err
+		}
+		return &luxorpb.ThemeClearResponse{Status: status}, nil
+	}
+	return &luxorpb.ThemeClearResponse{Status: int32(resp.Status)}, nil
+}
+
+func (s *server) ThemeGet(ctx context.Context, pbReq *luxorpb.ThemeGetRequest) (*luxorpb.ThemeGetResponse, error) {
+	var cv converter
+	req := &protocol.ThemeGetRequest{ThemeIndex: cv.u8("ThemeIndex", pbReq.ThemeIndex)}
+	if cv.err != nil {
+		return nil, cv.err
+	}
+	resp, err := s.controller.ThemeGet(ctx, req)
+	if resp == nil {
+		status, err := serverError(err)
+		if err != nil {
+			return nil, err
+		}
+		return &luxorpb.ThemeGetResponse{Status: status}, nil
+	}
+	return &luxorpb.ThemeGetResponse{Status: int32(resp.Status), Groups: themeGroupsToPB(resp.Groups)}, nil
+}
+
+func (s *server) ThemeListAdd(ctx context.Context, pbReq *luxorpb.ThemeListAddRequest) (*luxorpb.ThemeListAddResponse, error) {
+	var cv converter
+	req := &protocol.ThemeListAddRequest{ThemeIndex: cv.u8("ThemeIndex", pbReq.ThemeIndex), Name: pbReq.Name}
+	if cv.err != nil {
+		return nil, cv.err
+	}
+	resp, err := s.controller.ThemeListAdd(ctx, req)
+	if resp == nil {
+		status, err := serverError(err)
+		if err != nil {
+			return nil, err
+		}
+		return &luxorpb.ThemeListAddResponse{Status: status}, nil
+	}
+	return &luxorpb.ThemeListAddResponse{Status: int32(resp.Status)}, nil
+}
+
+func (s *server) ThemeListClear(ctx context.Context, pbReq *luxorpb.ThemeListClearRequest) (*luxorpb.ThemeListClearResponse, error) {
+	req := &protocol.ThemeListClearRequest{}
+	resp, err := s.controller.ThemeListClear(ctx, req)
+	if resp == nil {
+		status, err := serverError(err)
+		if err != nil {
+			return nil, err
+		}
+		return &luxorpb.ThemeListClearResponse{Status: status}, nil
+	}
+	return &luxorpb.ThemeListClearResponse{Status: int32(resp.Status)}, nil
+}
+
+func (s *server) ThemeListDelete(ctx context.Context, pbReq *luxorpb.ThemeListDeleteRequest) (*luxorpb.ThemeListDeleteResponse, error) {
+	req := &protocol.ThemeListDeleteRequest{Name: pbReq.Name}
+	resp, err := s.controller.ThemeListDelete(ctx, req)
+	if resp == nil {
+		status, err := serverError(err)
+		if err != nil {
+			return nil, err
+		}
+		return &luxorpb.ThemeListDeleteResponse{Status: status}, nil
+	}
+	return &luxorpb.ThemeListDeleteResponse{Status: int32(resp.Status)}, nil
+}
+
+func (s *server) ThemeListGet(ctx context.Context, pbReq *luxorpb.ThemeListGetRequest) (*luxorpb.ThemeListGetResponse, error) {
+	req := &protocol.ThemeListGetRequest{}
+	resp, err := s.controller.ThemeListGet(ctx, req)
+	if resp == nil {
+		status, err := serverError(err)
+		if err != nil {
+			return nil, err
+		}
+		return &luxorpb.ThemeListGetResponse{Status: status}, nil
+	}
+	return &luxorpb.ThemeListGetResponse{Status: int32(resp.Status), Restricted: int32(resp.Restricted), ThemeList: themesToPB(resp.ThemeList)}, nil
+}
+
+func (s *server) ThemeListRename(ctx context.Context, pbReq *luxorpb.ThemeListRenameRequest) (*luxorpb.ThemeListRenameResponse, error) {
+	req := &protocol.ThemeListRenameRequest{OldName: pbReq.OldName, NewName: pbReq.NewName}
+	resp, err := s.controller.ThemeListRename(ctx, req)
+	if resp == nil {
+		status, err := serverError(err)
+		if err != nil {
+			return nil, err
+		}
+		return &luxorpb.ThemeListRenameResponse{Status: status}, nil
+	}
+	return &luxorpb.ThemeListRenameResponse{Status: int32(resp.Status)}, nil
+}
+
+func (s *server) ThemeListReorder(ctx context.Context, pbReq *luxorpb.ThemeListReorderRequest) (*luxorpb.ThemeListReorderResponse, error) {
+	var cv converter
+	req := &protocol.ThemeListReorderRequest{ThemeIndexes: cv.u8s("ThemeIndexes", pbReq.ThemeIndexes)}
+	if cv.err != nil {
+		return nil, cv.err
+	}
+	resp, err := s.controller.ThemeListReorder(ctx, req)
+	if resp == nil {
+		status, err := serverError(err)
+		if err != nil {
+			return nil, err
+		}
+		return &luxorpb.ThemeListReorderResponse{Status: status}, nil
+	}
+	return &luxorpb.ThemeListReorderResponse{Status: int32(resp.Status)}, nil
+}
+
+func (s *server) ThemeSet(ctx context.Context, pbReq *luxorpb.ThemeSetRequest) (*luxorpb.ThemeSetResponse, error) {
+	var cv converter
+	req := &protocol.ThemeSetRequest{ThemeIndex: cv.u8("ThemeIndex", pbReq.ThemeIndex), Groups: cv.themeGroups(pbReq.Groups)}
+	if cv.err != nil {
+		return nil, cv.err
+	}
+	resp, err := s.controller.ThemeSet(ctx, req)
+	if resp == nil {
+		status, err := serverError(err)
+		if err != nil {
+			return nil, err
+		}
+		return &luxorpb.ThemeSetResponse{Status: status}, nil
+	}
+	return &luxorpb.ThemeSetResponse{Status: int32(resp.Status)}, nil
+}
@@ -0,0 +1,6 @@
+// Package luxorpb contains the protobuf messages and gRPC service generated
+// from luxor.proto. See package luxorgrpc for adapters to and from
+// protocol.Controller.
+package luxorpb
+
+//go:generate buf generate
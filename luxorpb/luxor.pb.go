@@ -0,0 +1,2815 @@
+// gRPC mirror of the protocol.ColorController interface. Messages correspond
+// field-for-field to the protocol package's request and response types;
+// uint8 fields are widened to uint32. Each response's status is the
+// controller's application-level status, exactly as in the JSON protocol.
+//
+// Regenerate with: go generate ./luxorpb
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: luxor.proto
+
+package luxorpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AssignLightRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SerialNumber  int64                  `protobuf:"varint,1,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	GroupNumber   uint32                 `protobuf:"varint,2,opt,name=group_number,json=groupNumber,proto3" json:"group_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignLightRequest) Reset() {
+	*x = AssignLightRequest{}
+	mi := &file_luxor_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignLightRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignLightRequest) ProtoMessage() {}
+
+func (x *AssignLightRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignLightRequest.ProtoReflect.Descriptor instead.
+func (*AssignLightRequest) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AssignLightRequest) GetSerialNumber() int64 {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return 0
+}
+
+func (x *AssignLightRequest) GetGroupNumber() uint32 {
+	if x != nil {
+		return x.GroupNumber
+	}
+	return 0
+}
+
+type AssignLightResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignLightResponse) Reset() {
+	*x = AssignLightResponse{}
+	mi := &file_luxor_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignLightResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignLightResponse) ProtoMessage() {}
+
+func (x *AssignLightResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignLightResponse.ProtoReflect.Descriptor instead.
+func (*AssignLightResponse) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AssignLightResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+type Color struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ColorNumber   uint32                 `protobuf:"varint,1,opt,name=color_number,json=colorNumber,proto3" json:"color_number,omitempty"`
+	Hue           uint32                 `protobuf:"varint,2,opt,name=hue,proto3" json:"hue,omitempty"`
+	Saturation    uint32                 `protobuf:"varint,3,opt,name=saturation,proto3" json:"saturation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Color) Reset() {
+	*x = Color{}
+	mi := &file_luxor_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Color) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Color) ProtoMessage() {}
+
+func (x *Color) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Color.ProtoReflect.Descriptor instead.
+func (*Color) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Color) GetColorNumber() uint32 {
+	if x != nil {
+		return x.ColorNumber
+	}
+	return 0
+}
+
+func (x *Color) GetHue() uint32 {
+	if x != nil {
+		return x.Hue
+	}
+	return 0
+}
+
+func (x *Color) GetSaturation() uint32 {
+	if x != nil {
+		return x.Saturation
+	}
+	return 0
+}
+
+type ColorListGetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ColorListGetRequest) Reset() {
+	*x = ColorListGetRequest{}
+	mi := &file_luxor_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ColorListGetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ColorListGetRequest) ProtoMessage() {}
+
+func (x *ColorListGetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ColorListGetRequest.ProtoReflect.Descriptor instead.
+func (*ColorListGetRequest) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{3}
+}
+
+type ColorListGetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	ColorList     []*Color               `protobuf:"bytes,2,rep,name=color_list,json=colorList,proto3" json:"color_list,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ColorListGetResponse) Reset() {
+	*x = ColorListGetResponse{}
+	mi := &file_luxor_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ColorListGetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ColorListGetResponse) ProtoMessage() {}
+
+func (x *ColorListGetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ColorListGetResponse.ProtoReflect.Descriptor instead.
+func (*ColorListGetResponse) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ColorListGetResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *ColorListGetResponse) GetColorList() []*Color {
+	if x != nil {
+		return x.ColorList
+	}
+	return nil
+}
+
+type ColorListSetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ColorNumber   uint32                 `protobuf:"varint,1,opt,name=color_number,json=colorNumber,proto3" json:"color_number,omitempty"`
+	Hue           uint32                 `protobuf:"varint,2,opt,name=hue,proto3" json:"hue,omitempty"`
+	Saturation    uint32                 `protobuf:"varint,3,opt,name=saturation,proto3" json:"saturation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ColorListSetRequest) Reset() {
+	*x = ColorListSetRequest{}
+	mi := &file_luxor_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ColorListSetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ColorListSetRequest) ProtoMessage() {}
+
+func (x *ColorListSetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ColorListSetRequest.ProtoReflect.Descriptor instead.
+func (*ColorListSetRequest) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ColorListSetRequest) GetColorNumber() uint32 {
+	if x != nil {
+		return x.ColorNumber
+	}
+	return 0
+}
+
+func (x *ColorListSetRequest) GetHue() uint32 {
+	if x != nil {
+		return x.Hue
+	}
+	return 0
+}
+
+func (x *ColorListSetRequest) GetSaturation() uint32 {
+	if x != nil {
+		return x.Saturation
+	}
+	return 0
+}
+
+type ColorListSetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ColorListSetResponse) Reset() {
+	*x = ColorListSetResponse{}
+	mi := &file_luxor_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ColorListSetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ColorListSetResponse) ProtoMessage() {}
+
+func (x *ColorListSetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ColorListSetResponse.ProtoReflect.Descriptor instead.
+func (*ColorListSetResponse) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ColorListSetResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+type ControllerNameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ControllerNameRequest) Reset() {
+	*x = ControllerNameRequest{}
+	mi := &file_luxor_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ControllerNameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ControllerNameRequest) ProtoMessage() {}
+
+func (x *ControllerNameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ControllerNameRequest.ProtoReflect.Descriptor instead.
+func (*ControllerNameRequest) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{7}
+}
+
+type ControllerNameResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Controller    string                 `protobuf:"bytes,2,opt,name=controller,proto3" json:"controller,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ControllerNameResponse) Reset() {
+	*x = ControllerNameResponse{}
+	mi := &file_luxor_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ControllerNameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ControllerNameResponse) ProtoMessage() {}
+
+func (x *ControllerNameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ControllerNameResponse.ProtoReflect.Descriptor instead.
+func (*ControllerNameResponse) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ControllerNameResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *ControllerNameResponse) GetController() string {
+	if x != nil {
+		return x.Controller
+	}
+	return ""
+}
+
+type ExtinguishAllRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExtinguishAllRequest) Reset() {
+	*x = ExtinguishAllRequest{}
+	mi := &file_luxor_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtinguishAllRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtinguishAllRequest) ProtoMessage() {}
+
+func (x *ExtinguishAllRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtinguishAllRequest.ProtoReflect.Descriptor instead.
+func (*ExtinguishAllRequest) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{9}
+}
+
+type ExtinguishAllResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExtinguishAllResponse) Reset() {
+	*x = ExtinguishAllResponse{}
+	mi := &file_luxor_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtinguishAllResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtinguishAllResponse) ProtoMessage() {}
+
+func (x *ExtinguishAllResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtinguishAllResponse.ProtoReflect.Descriptor instead.
+func (*ExtinguishAllResponse) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ExtinguishAllResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+type FlashLightsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OnOff         uint32                 `protobuf:"varint,1,opt,name=on_off,json=onOff,proto3" json:"on_off,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FlashLightsRequest) Reset() {
+	*x = FlashLightsRequest{}
+	mi := &file_luxor_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FlashLightsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlashLightsRequest) ProtoMessage() {}
+
+func (x *FlashLightsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlashLightsRequest.ProtoReflect.Descriptor instead.
+func (*FlashLightsRequest) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *FlashLightsRequest) GetOnOff() uint32 {
+	if x != nil {
+		return x.OnOff
+	}
+	return 0
+}
+
+type FlashLightsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FlashLightsResponse) Reset() {
+	*x = FlashLightsResponse{}
+	mi := &file_luxor_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FlashLightsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlashLightsResponse) ProtoMessage() {}
+
+func (x *FlashLightsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlashLightsResponse.ProtoReflect.Descriptor instead.
+func (*FlashLightsResponse) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *FlashLightsResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+type Group struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupNumber   uint32                 `protobuf:"varint,1,opt,name=group_number,json=groupNumber,proto3" json:"group_number,omitempty"`
+	Intensity     uint32                 `protobuf:"varint,2,opt,name=intensity,proto3" json:"intensity,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Type          uint32                 `protobuf:"varint,4,opt,name=type,proto3" json:"type,omitempty"`
+	Color         uint32                 `protobuf:"varint,5,opt,name=color,proto3" json:"color,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Group) Reset() {
+	*x = Group{}
+	mi := &file_luxor_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Group) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Group) ProtoMessage() {}
+
+func (x *Group) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Group.ProtoReflect.Descriptor instead.
+func (*Group) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Group) GetGroupNumber() uint32 {
+	if x != nil {
+		return x.GroupNumber
+	}
+	return 0
+}
+
+func (x *Group) GetIntensity() uint32 {
+	if x != nil {
+		return x.Intensity
+	}
+	return 0
+}
+
+func (x *Group) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Group) GetType() uint32 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *Group) GetColor() uint32 {
+	if x != nil {
+		return x.Color
+	}
+	return 0
+}
+
+type GroupListAddRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupNumber   uint32                 `protobuf:"varint,1,opt,name=group_number,json=groupNumber,proto3" json:"group_number,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Type          uint32                 `protobuf:"varint,3,opt,name=type,proto3" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupListAddRequest) Reset() {
+	*x = GroupListAddRequest{}
+	mi := &file_luxor_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupListAddRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupListAddRequest) ProtoMessage() {}
+
+func (x *GroupListAddRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupListAddRequest.ProtoReflect.Descriptor instead.
+func (*GroupListAddRequest) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GroupListAddRequest) GetGroupNumber() uint32 {
+	if x != nil {
+		return x.GroupNumber
+	}
+	return 0
+}
+
+func (x *GroupListAddRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GroupListAddRequest) GetType() uint32 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+type GroupListAddResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupListAddResponse) Reset() {
+	*x = GroupListAddResponse{}
+	mi := &file_luxor_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupListAddResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupListAddResponse) ProtoMessage() {}
+
+func (x *GroupListAddResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupListAddResponse.ProtoReflect.Descriptor instead.
+func (*GroupListAddResponse) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GroupListAddResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+type GroupListClearRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupListClearRequest) Reset() {
+	*x = GroupListClearRequest{}
+	mi := &file_luxor_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupListClearRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupListClearRequest) ProtoMessage() {}
+
+func (x *GroupListClearRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupListClearRequest.ProtoReflect.Descriptor instead.
+func (*GroupListClearRequest) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{16}
+}
+
+type GroupListClearResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	GroupList     []*Group               `protobuf:"bytes,2,rep,name=group_list,json=groupList,proto3" json:"group_list,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupListClearResponse) Reset() {
+	*x = GroupListClearResponse{}
+	mi := &file_luxor_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupListClearResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupListClearResponse) ProtoMessage() {}
+
+func (x *GroupListClearResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupListClearResponse.ProtoReflect.Descriptor instead.
+func (*GroupListClearResponse) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GroupListClearResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *GroupListClearResponse) GetGroupList() []*Group {
+	if x != nil {
+		return x.GroupList
+	}
+	return nil
+}
+
+type GroupListDeleteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupListDeleteRequest) Reset() {
+	*x = GroupListDeleteRequest{}
+	mi := &file_luxor_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupListDeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupListDeleteRequest) ProtoMessage() {}
+
+func (x *GroupListDeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupListDeleteRequest.ProtoReflect.Descriptor instead.
+func (*GroupListDeleteRequest) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GroupListDeleteRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GroupListDeleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupListDeleteResponse) Reset() {
+	*x = GroupListDeleteResponse{}
+	mi := &file_luxor_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupListDeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupListDeleteResponse) ProtoMessage() {}
+
+func (x *GroupListDeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupListDeleteResponse.ProtoReflect.Descriptor instead.
+func (*GroupListDeleteResponse) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GroupListDeleteResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+type GroupListGetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupListGetRequest) Reset() {
+	*x = GroupListGetRequest{}
+	mi := &file_luxor_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupListGetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupListGetRequest) ProtoMessage() {}
+
+func (x *GroupListGetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupListGetRequest.ProtoReflect.Descriptor instead.
+func (*GroupListGetRequest) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{20}
+}
+
+type GroupListGetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	GroupList     []*Group               `protobuf:"bytes,2,rep,name=group_list,json=groupList,proto3" json:"group_list,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupListGetResponse) Reset() {
+	*x = GroupListGetResponse{}
+	mi := &file_luxor_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupListGetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupListGetResponse) ProtoMessage() {}
+
+func (x *GroupListGetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupListGetResponse.ProtoReflect.Descriptor instead.
+func (*GroupListGetResponse) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GroupListGetResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *GroupListGetResponse) GetGroupList() []*Group {
+	if x != nil {
+		return x.GroupList
+	}
+	return nil
+}
+
+type GroupListRenameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OldName       string                 `protobuf:"bytes,1,opt,name=old_name,json=oldName,proto3" json:"old_name,omitempty"`
+	NewName       string                 `protobuf:"bytes,2,opt,name=new_name,json=newName,proto3" json:"new_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupListRenameRequest) Reset() {
+	*x = GroupListRenameRequest{}
+	mi := &file_luxor_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupListRenameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupListRenameRequest) ProtoMessage() {}
+
+func (x *GroupListRenameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupListRenameRequest.ProtoReflect.Descriptor instead.
+func (*GroupListRenameRequest) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GroupListRenameRequest) GetOldName() string {
+	if x != nil {
+		return x.OldName
+	}
+	return ""
+}
+
+func (x *GroupListRenameRequest) GetNewName() string {
+	if x != nil {
+		return x.NewName
+	}
+	return ""
+}
+
+type GroupListRenameResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupListRenameResponse) Reset() {
+	*x = GroupListRenameResponse{}
+	mi := &file_luxor_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupListRenameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupListRenameResponse) ProtoMessage() {}
+
+func (x *GroupListRenameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupListRenameResponse.ProtoReflect.Descriptor instead.
+func (*GroupListRenameResponse) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GroupListRenameResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+type GroupListReorderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupNumbers  []uint32               `protobuf:"varint,1,rep,packed,name=group_numbers,json=groupNumbers,proto3" json:"group_numbers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupListReorderRequest) Reset() {
+	*x = GroupListReorderRequest{}
+	mi := &file_luxor_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupListReorderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupListReorderRequest) ProtoMessage() {}
+
+func (x *GroupListReorderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupListReorderRequest.ProtoReflect.Descriptor instead.
+func (*GroupListReorderRequest) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GroupListReorderRequest) GetGroupNumbers() []uint32 {
+	if x != nil {
+		return x.GroupNumbers
+	}
+	return nil
+}
+
+type GroupListReorderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupListReorderResponse) Reset() {
+	*x = GroupListReorderResponse{}
+	mi := &file_luxor_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupListReorderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupListReorderResponse) ProtoMessage() {}
+
+func (x *GroupListReorderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupListReorderResponse.ProtoReflect.Descriptor instead.
+func (*GroupListReorderResponse) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GroupListReorderResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+type IlluminateAllRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IlluminateAllRequest) Reset() {
+	*x = IlluminateAllRequest{}
+	mi := &file_luxor_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IlluminateAllRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IlluminateAllRequest) ProtoMessage() {}
+
+func (x *IlluminateAllRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IlluminateAllRequest.ProtoReflect.Descriptor instead.
+func (*IlluminateAllRequest) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{26}
+}
+
+type IlluminateAllResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IlluminateAllResponse) Reset() {
+	*x = IlluminateAllResponse{}
+	mi := &file_luxor_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IlluminateAllResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IlluminateAllResponse) ProtoMessage() {}
+
+func (x *IlluminateAllResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IlluminateAllResponse.ProtoReflect.Descriptor instead.
+func (*IlluminateAllResponse) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *IlluminateAllResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+type IlluminateGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupNumber   uint32                 `protobuf:"varint,1,opt,name=group_number,json=groupNumber,proto3" json:"group_number,omitempty"`
+	Intensity     uint32                 `protobuf:"varint,2,opt,name=intensity,proto3" json:"intensity,omitempty"`
+	Color         uint32                 `protobuf:"varint,3,opt,name=color,proto3" json:"color,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IlluminateGroupRequest) Reset() {
+	*x = IlluminateGroupRequest{}
+	mi := &file_luxor_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IlluminateGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IlluminateGroupRequest) ProtoMessage() {}
+
+func (x *IlluminateGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IlluminateGroupRequest.ProtoReflect.Descriptor instead.
+func (*IlluminateGroupRequest) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *IlluminateGroupRequest) GetGroupNumber() uint32 {
+	if x != nil {
+		return x.GroupNumber
+	}
+	return 0
+}
+
+func (x *IlluminateGroupRequest) GetIntensity() uint32 {
+	if x != nil {
+		return x.Intensity
+	}
+	return 0
+}
+
+func (x *IlluminateGroupRequest) GetColor() uint32 {
+	if x != nil {
+		return x.Color
+	}
+	return 0
+}
+
+type IlluminateGroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IlluminateGroupResponse) Reset() {
+	*x = IlluminateGroupResponse{}
+	mi := &file_luxor_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IlluminateGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IlluminateGroupResponse) ProtoMessage() {}
+
+func (x *IlluminateGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IlluminateGroupResponse.ProtoReflect.Descriptor instead.
+func (*IlluminateGroupResponse) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *IlluminateGroupResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+type IlluminateThemeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ThemeIndex    uint32                 `protobuf:"varint,1,opt,name=theme_index,json=themeIndex,proto3" json:"theme_index,omitempty"`
+	OnOff         uint32                 `protobuf:"varint,2,opt,name=on_off,json=onOff,proto3" json:"on_off,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IlluminateThemeRequest) Reset() {
+	*x = IlluminateThemeRequest{}
+	mi := &file_luxor_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IlluminateThemeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IlluminateThemeRequest) ProtoMessage() {}
+
+func (x *IlluminateThemeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IlluminateThemeRequest.ProtoReflect.Descriptor instead.
+func (*IlluminateThemeRequest) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *IlluminateThemeRequest) GetThemeIndex() uint32 {
+	if x != nil {
+		return x.ThemeIndex
+	}
+	return 0
+}
+
+func (x *IlluminateThemeRequest) GetOnOff() uint32 {
+	if x != nil {
+		return x.OnOff
+	}
+	return 0
+}
+
+type IlluminateThemeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IlluminateThemeResponse) Reset() {
+	*x = IlluminateThemeResponse{}
+	mi := &file_luxor_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IlluminateThemeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IlluminateThemeResponse) ProtoMessage() {}
+
+func (x *IlluminateThemeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IlluminateThemeResponse.ProtoReflect.Descriptor instead.
+func (*IlluminateThemeResponse) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *IlluminateThemeResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+type Theme struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ThemeIndex    uint32                 `protobuf:"varint,2,opt,name=theme_index,json=themeIndex,proto3" json:"theme_index,omitempty"`
+	OnOff         uint32                 `protobuf:"varint,3,opt,name=on_off,json=onOff,proto3" json:"on_off,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Theme) Reset() {
+	*x = Theme{}
+	mi := &file_luxor_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Theme) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Theme) ProtoMessage() {}
+
+func (x *Theme) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Theme.ProtoReflect.Descriptor instead.
+func (*Theme) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *Theme) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Theme) GetThemeIndex() uint32 {
+	if x != nil {
+		return x.ThemeIndex
+	}
+	return 0
+}
+
+func (x *Theme) GetOnOff() uint32 {
+	if x != nil {
+		return x.OnOff
+	}
+	return 0
+}
+
+type ThemeGroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupNumber   uint32                 `protobuf:"varint,1,opt,name=group_number,json=groupNumber,proto3" json:"group_number,omitempty"`
+	Intensity     uint32                 `protobuf:"varint,2,opt,name=intensity,proto3" json:"intensity,omitempty"`
+	Color         uint32                 `protobuf:"varint,3,opt,name=color,proto3" json:"color,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThemeGroup) Reset() {
+	*x = ThemeGroup{}
+	mi := &file_luxor_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThemeGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThemeGroup) ProtoMessage() {}
+
+func (x *ThemeGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThemeGroup.ProtoReflect.Descriptor instead.
+func (*ThemeGroup) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ThemeGroup) GetGroupNumber() uint32 {
+	if x != nil {
+		return x.GroupNumber
+	}
+	return 0
+}
+
+func (x *ThemeGroup) GetIntensity() uint32 {
+	if x != nil {
+		return x.Intensity
+	}
+	return 0
+}
+
+func (x *ThemeGroup) GetColor() uint32 {
+	if x != nil {
+		return x.Color
+	}
+	return 0
+}
+
+type ThemeClearRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ThemeIndex    uint32                 `protobuf:"varint,1,opt,name=theme_index,json=themeIndex,proto3" json:"theme_index,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThemeClearRequest) Reset() {
+	*x = ThemeClearRequest{}
+	mi := &file_luxor_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThemeClearRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThemeClearRequest) ProtoMessage() {}
+
+func (x *ThemeClearRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThemeClearRequest.ProtoReflect.Descriptor instead.
+func (*ThemeClearRequest) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ThemeClearRequest) GetThemeIndex() uint32 {
+	if x != nil {
+		return x.ThemeIndex
+	}
+	return 0
+}
+
+type ThemeClearResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThemeClearResponse) Reset() {
+	*x = ThemeClearResponse{}
+	mi := &file_luxor_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThemeClearResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThemeClearResponse) ProtoMessage() {}
+
+func (x *ThemeClearResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThemeClearResponse.ProtoReflect.Descriptor instead.
+func (*ThemeClearResponse) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ThemeClearResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+type ThemeGetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ThemeIndex    uint32                 `protobuf:"varint,1,opt,name=theme_index,json=themeIndex,proto3" json:"theme_index,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThemeGetRequest) Reset() {
+	*x = ThemeGetRequest{}
+	mi := &file_luxor_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThemeGetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThemeGetRequest) ProtoMessage() {}
+
+func (x *ThemeGetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThemeGetRequest.ProtoReflect.Descriptor instead.
+func (*ThemeGetRequest) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ThemeGetRequest) GetThemeIndex() uint32 {
+	if x != nil {
+		return x.ThemeIndex
+	}
+	return 0
+}
+
+type ThemeGetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Groups        []*ThemeGroup          `protobuf:"bytes,2,rep,name=groups,proto3" json:"groups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThemeGetResponse) Reset() {
+	*x = ThemeGetResponse{}
+	mi := &file_luxor_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThemeGetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThemeGetResponse) ProtoMessage() {}
+
+func (x *ThemeGetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThemeGetResponse.ProtoReflect.Descriptor instead.
+func (*ThemeGetResponse) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ThemeGetResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *ThemeGetResponse) GetGroups() []*ThemeGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+type ThemeListAddRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ThemeIndex    uint32                 `protobuf:"varint,1,opt,name=theme_index,json=themeIndex,proto3" json:"theme_index,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThemeListAddRequest) Reset() {
+	*x = ThemeListAddRequest{}
+	mi := &file_luxor_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThemeListAddRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThemeListAddRequest) ProtoMessage() {}
+
+func (x *ThemeListAddRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThemeListAddRequest.ProtoReflect.Descriptor instead.
+func (*ThemeListAddRequest) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ThemeListAddRequest) GetThemeIndex() uint32 {
+	if x != nil {
+		return x.ThemeIndex
+	}
+	return 0
+}
+
+func (x *ThemeListAddRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type ThemeListAddResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThemeListAddResponse) Reset() {
+	*x = ThemeListAddResponse{}
+	mi := &file_luxor_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThemeListAddResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThemeListAddResponse) ProtoMessage() {}
+
+func (x *ThemeListAddResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThemeListAddResponse.ProtoReflect.Descriptor instead.
+func (*ThemeListAddResponse) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ThemeListAddResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+type ThemeListClearRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThemeListClearRequest) Reset() {
+	*x = ThemeListClearRequest{}
+	mi := &file_luxor_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThemeListClearRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThemeListClearRequest) ProtoMessage() {}
+
+func (x *ThemeListClearRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThemeListClearRequest.ProtoReflect.Descriptor instead.
+func (*ThemeListClearRequest) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{40}
+}
+
+type ThemeListClearResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThemeListClearResponse) Reset() {
+	*x = ThemeListClearResponse{}
+	mi := &file_luxor_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThemeListClearResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThemeListClearResponse) ProtoMessage() {}
+
+func (x *ThemeListClearResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThemeListClearResponse.ProtoReflect.Descriptor instead.
+func (*ThemeListClearResponse) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ThemeListClearResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+type ThemeListDeleteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThemeListDeleteRequest) Reset() {
+	*x = ThemeListDeleteRequest{}
+	mi := &file_luxor_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThemeListDeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThemeListDeleteRequest) ProtoMessage() {}
+
+func (x *ThemeListDeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThemeListDeleteRequest.ProtoReflect.Descriptor instead.
+func (*ThemeListDeleteRequest) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ThemeListDeleteRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type ThemeListDeleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThemeListDeleteResponse) Reset() {
+	*x = ThemeListDeleteResponse{}
+	mi := &file_luxor_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThemeListDeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThemeListDeleteResponse) ProtoMessage() {}
+
+func (x *ThemeListDeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThemeListDeleteResponse.ProtoReflect.Descriptor instead.
+func (*ThemeListDeleteResponse) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ThemeListDeleteResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+type ThemeListGetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThemeListGetRequest) Reset() {
+	*x = ThemeListGetRequest{}
+	mi := &file_luxor_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThemeListGetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThemeListGetRequest) ProtoMessage() {}
+
+func (x *ThemeListGetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThemeListGetRequest.ProtoReflect.Descriptor instead.
+func (*ThemeListGetRequest) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{44}
+}
+
+type ThemeListGetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Restricted    int32                  `protobuf:"varint,2,opt,name=restricted,proto3" json:"restricted,omitempty"`
+	ThemeList     []*Theme               `protobuf:"bytes,3,rep,name=theme_list,json=themeList,proto3" json:"theme_list,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThemeListGetResponse) Reset() {
+	*x = ThemeListGetResponse{}
+	mi := &file_luxor_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThemeListGetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThemeListGetResponse) ProtoMessage() {}
+
+func (x *ThemeListGetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThemeListGetResponse.ProtoReflect.Descriptor instead.
+func (*ThemeListGetResponse) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ThemeListGetResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *ThemeListGetResponse) GetRestricted() int32 {
+	if x != nil {
+		return x.Restricted
+	}
+	return 0
+}
+
+func (x *ThemeListGetResponse) GetThemeList() []*Theme {
+	if x != nil {
+		return x.ThemeList
+	}
+	return nil
+}
+
+type ThemeListRenameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OldName       string                 `protobuf:"bytes,1,opt,name=old_name,json=oldName,proto3" json:"old_name,omitempty"`
+	NewName       string                 `protobuf:"bytes,2,opt,name=new_name,json=newName,proto3" json:"new_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThemeListRenameRequest) Reset() {
+	*x = ThemeListRenameRequest{}
+	mi := &file_luxor_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThemeListRenameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThemeListRenameRequest) ProtoMessage() {}
+
+func (x *ThemeListRenameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThemeListRenameRequest.ProtoReflect.Descriptor instead.
+func (*ThemeListRenameRequest) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ThemeListRenameRequest) GetOldName() string {
+	if x != nil {
+		return x.OldName
+	}
+	return ""
+}
+
+func (x *ThemeListRenameRequest) GetNewName() string {
+	if x != nil {
+		return x.NewName
+	}
+	return ""
+}
+
+type ThemeListRenameResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThemeListRenameResponse) Reset() {
+	*x = ThemeListRenameResponse{}
+	mi := &file_luxor_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThemeListRenameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThemeListRenameResponse) ProtoMessage() {}
+
+func (x *ThemeListRenameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThemeListRenameResponse.ProtoReflect.Descriptor instead.
+func (*ThemeListRenameResponse) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ThemeListRenameResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+type ThemeListReorderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ThemeIndexes  []uint32               `protobuf:"varint,1,rep,packed,name=theme_indexes,json=themeIndexes,proto3" json:"theme_indexes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThemeListReorderRequest) Reset() {
+	*x = ThemeListReorderRequest{}
+	mi := &file_luxor_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThemeListReorderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThemeListReorderRequest) ProtoMessage() {}
+
+func (x *ThemeListReorderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThemeListReorderRequest.ProtoReflect.Descriptor instead.
+func (*ThemeListReorderRequest) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ThemeListReorderRequest) GetThemeIndexes() []uint32 {
+	if x != nil {
+		return x.ThemeIndexes
+	}
+	return nil
+}
+
+type ThemeListReorderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThemeListReorderResponse) Reset() {
+	*x = ThemeListReorderResponse{}
+	mi := &file_luxor_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThemeListReorderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThemeListReorderResponse) ProtoMessage() {}
+
+func (x *ThemeListReorderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThemeListReorderResponse.ProtoReflect.Descriptor instead.
+func (*ThemeListReorderResponse) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *ThemeListReorderResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+type ThemeSetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ThemeIndex    uint32                 `protobuf:"varint,1,opt,name=theme_index,json=themeIndex,proto3" json:"theme_index,omitempty"`
+	Groups        []*ThemeGroup          `protobuf:"bytes,2,rep,name=groups,proto3" json:"groups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThemeSetRequest) Reset() {
+	*x = ThemeSetRequest{}
+	mi := &file_luxor_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThemeSetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThemeSetRequest) ProtoMessage() {}
+
+func (x *ThemeSetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThemeSetRequest.ProtoReflect.Descriptor instead.
+func (*ThemeSetRequest) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ThemeSetRequest) GetThemeIndex() uint32 {
+	if x != nil {
+		return x.ThemeIndex
+	}
+	return 0
+}
+
+func (x *ThemeSetRequest) GetGroups() []*ThemeGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+type ThemeSetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThemeSetResponse) Reset() {
+	*x = ThemeSetResponse{}
+	mi := &file_luxor_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThemeSetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThemeSetResponse) ProtoMessage() {}
+
+func (x *ThemeSetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_luxor_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThemeSetResponse.ProtoReflect.Descriptor instead.
+func (*ThemeSetResponse) Descriptor() ([]byte, []int) {
+	return file_luxor_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *ThemeSetResponse) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+var File_luxor_proto protoreflect.FileDescriptor
+
+const file_luxor_proto_rawDesc = "" +
+	"\n" +
+	"\vluxor.proto\x12\x05luxor\"\\\n" +
+	"\x12AssignLightRequest\x12#\n" +
+	"\rserial_number\x18\x01 \x01(\x03R\fserialNumber\x12!\n" +
+	"\fgroup_number\x18\x02 \x01(\rR\vgroupNumber\"-\n" +
+	"\x13AssignLightResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\"\\\n" +
+	"\x05Color\x12!\n" +
+	"\fcolor_number\x18\x01 \x01(\rR\vcolorNumber\x12\x10\n" +
+	"\x03hue\x18\x02 \x01(\rR\x03hue\x12\x1e\n" +
+	"\n" +
+	"saturation\x18\x03 \x01(\rR\n" +
+	"saturation\"\x15\n" +
+	"\x13ColorListGetRequest\"[\n" +
+	"\x14ColorListGetResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\x12+\n" +
+	"\n" +
+	"color_list\x18\x02 \x03(\v2\f.luxor.ColorR\tcolorList\"j\n" +
+	"\x13ColorListSetRequest\x12!\n" +
+	"\fcolor_number\x18\x01 \x01(\rR\vcolorNumber\x12\x10\n" +
+	"\x03hue\x18\x02 \x01(\rR\x03hue\x12\x1e\n" +
+	"\n" +
+	"saturation\x18\x03 \x01(\rR\n" +
+	"saturation\".\n" +
+	"\x14ColorListSetResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\"\x17\n" +
+	"\x15ControllerNameRequest\"P\n" +
+	"\x16ControllerNameResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\x12\x1e\n" +
+	"\n" +
+	"controller\x18\x02 \x01(\tR\n" +
+	"controller\"\x16\n" +
+	"\x14ExtinguishAllRequest\"/\n" +
+	"\x15ExtinguishAllResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\"+\n" +
+	"\x12FlashLightsRequest\x12\x15\n" +
+	"\x06on_off\x18\x01 \x01(\rR\x05onOff\"-\n" +
+	"\x13FlashLightsResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\"\x86\x01\n" +
+	"\x05Group\x12!\n" +
+	"\fgroup_number\x18\x01 \x01(\rR\vgroupNumber\x12\x1c\n" +
+	"\tintensity\x18\x02 \x01(\rR\tintensity\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x04 \x01(\rR\x04type\x12\x14\n" +
+	"\x05color\x18\x05 \x01(\rR\x05color\"`\n" +
+	"\x13GroupListAddRequest\x12!\n" +
+	"\fgroup_number\x18\x01 \x01(\rR\vgroupNumber\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\rR\x04type\".\n" +
+	"\x14GroupListAddResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\"\x17\n" +
+	"\x15GroupListClearRequest\"]\n" +
+	"\x16GroupListClearResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\x12+\n" +
+	"\n" +
+	"group_list\x18\x02 \x03(\v2\f.luxor.GroupR\tgroupList\",\n" +
+	"\x16GroupListDeleteRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"1\n" +
+	"\x17GroupListDeleteResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\"\x15\n" +
+	"\x13GroupListGetRequest\"[\n" +
+	"\x14GroupListGetResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\x12+\n" +
+	"\n" +
+	"group_list\x18\x02 \x03(\v2\f.luxor.GroupR\tgroupList\"N\n" +
+	"\x16GroupListRenameRequest\x12\x19\n" +
+	"\bold_name\x18\x01 \x01(\tR\aoldName\x12\x19\n" +
+	"\bnew_name\x18\x02 \x01(\tR\anewName\"1\n" +
+	"\x17GroupListRenameResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\">\n" +
+	"\x17GroupListReorderRequest\x12#\n" +
+	"\rgroup_numbers\x18\x01 \x03(\rR\fgroupNumbers\"2\n" +
+	"\x18GroupListReorderResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\"\x16\n" +
+	"\x14IlluminateAllRequest\"/\n" +
+	"\x15IlluminateAllResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\"o\n" +
+	"\x16IlluminateGroupRequest\x12!\n" +
+	"\fgroup_number\x18\x01 \x01(\rR\vgroupNumber\x12\x1c\n" +
+	"\tintensity\x18\x02 \x01(\rR\tintensity\x12\x14\n" +
+	"\x05color\x18\x03 \x01(\rR\x05color\"1\n" +
+	"\x17IlluminateGroupResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\"P\n" +
+	"\x16IlluminateThemeRequest\x12\x1f\n" +
+	"\vtheme_index\x18\x01 \x01(\rR\n" +
+	"themeIndex\x12\x15\n" +
+	"\x06on_off\x18\x02 \x01(\rR\x05onOff\"1\n" +
+	"\x17IlluminateThemeResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\"S\n" +
+	"\x05Theme\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1f\n" +
+	"\vtheme_index\x18\x02 \x01(\rR\n" +
+	"themeIndex\x12\x15\n" +
+	"\x06on_off\x18\x03 \x01(\rR\x05onOff\"c\n" +
+	"\n" +
+	"ThemeGroup\x12!\n" +
+	"\fgroup_number\x18\x01 \x01(\rR\vgroupNumber\x12\x1c\n" +
+	"\tintensity\x18\x02 \x01(\rR\tintensity\x12\x14\n" +
+	"\x05color\x18\x03 \x01(\rR\x05color\"4\n" +
+	"\x11ThemeClearRequest\x12\x1f\n" +
+	"\vtheme_index\x18\x01 \x01(\rR\n" +
+	"themeIndex\",\n" +
+	"\x12ThemeClearResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\"2\n" +
+	"\x0fThemeGetRequest\x12\x1f\n" +
+	"\vtheme_index\x18\x01 \x01(\rR\n" +
+	"themeIndex\"U\n" +
+	"\x10ThemeGetResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\x12)\n" +
+	"\x06groups\x18\x02 \x03(\v2\x11.luxor.ThemeGroupR\x06groups\"J\n" +
+	"\x13ThemeListAddRequest\x12\x1f\n" +
+	"\vtheme_index\x18\x01 \x01(\rR\n" +
+	"themeIndex\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\".\n" +
+	"\x14ThemeListAddResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\"\x17\n" +
+	"\x15ThemeListClearRequest\"0\n" +
+	"\x16ThemeListClearResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\",\n" +
+	"\x16ThemeListDeleteRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"1\n" +
+	"\x17ThemeListDeleteResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\"\x15\n" +
+	"\x13ThemeListGetRequest\"{\n" +
+	"\x14ThemeListGetResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\x12\x1e\n" +
+	"\n" +
+	"restricted\x18\x02 \x01(\x05R\n" +
+	"restricted\x12+\n" +
+	"\n" +
+	"theme_list\x18\x03 \x03(\v2\f.luxor.ThemeR\tthemeList\"N\n" +
+	"\x16ThemeListRenameRequest\x12\x19\n" +
+	"\bold_name\x18\x01 \x01(\tR\aoldName\x12\x19\n" +
+	"\bnew_name\x18\x02 \x01(\tR\anewName\"1\n" +
+	"\x17ThemeListRenameResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\">\n" +
+	"\x17ThemeListReorderRequest\x12#\n" +
+	"\rtheme_indexes\x18\x01 \x03(\rR\fthemeIndexes\"2\n" +
+	"\x18ThemeListReorderResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\"]\n" +
+	"\x0fThemeSetRequest\x12\x1f\n" +
+	"\vtheme_index\x18\x01 \x01(\rR\n" +
+	"themeIndex\x12)\n" +
+	"\x06groups\x18\x02 \x03(\v2\x11.luxor.ThemeGroupR\x06groups\"*\n" +
+	"\x10ThemeSetResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status2\xa6\x0e\n" +
+	"\n" +
+	"Controller\x12D\n" +
+	"\vAssignLight\x12\x19.luxor.AssignLightRequest\x1a\x1a.luxor.AssignLightResponse\x12G\n" +
+	"\fColorListGet\x12\x1a.luxor.ColorListGetRequest\x1a\x1b.luxor.ColorListGetResponse\x12G\n" +
+	"\fColorListSet\x12\x1a.luxor.ColorListSetRequest\x1a\x1b.luxor.ColorListSetResponse\x12M\n" +
+	"\x0eControllerName\x12\x1c.luxor.ControllerNameRequest\x1a\x1d.luxor.ControllerNameResponse\x12J\n" +
+	"\rExtinguishAll\x12\x1b.luxor.ExtinguishAllRequest\x1a\x1c.luxor.ExtinguishAllResponse\x12D\n" +
+	"\vFlashLights\x12\x19.luxor.FlashLightsRequest\x1a\x1a.luxor.FlashLightsResponse\x12G\n" +
+	"\fGroupListAdd\x12\x1a.luxor.GroupListAddRequest\x1a\x1b.luxor.GroupListAddResponse\x12M\n" +
+	"\x0eGroupListClear\x12\x1c.luxor.GroupListClearRequest\x1a\x1d.luxor.GroupListClearResponse\x12P\n" +
+	"\x0fGroupListDelete\x12\x1d.luxor.GroupListDeleteRequest\x1a\x1e.luxor.GroupListDeleteResponse\x12G\n" +
+	"\fGroupListGet\x12\x1a.luxor.GroupListGetRequest\x1a\x1b.luxor.GroupListGetResponse\x12P\n" +
+	"\x0fGroupListRename\x12\x1d.luxor.GroupListRenameRequest\x1a\x1e.luxor.GroupListRenameResponse\x12S\n" +
+	"\x10GroupListReorder\x12\x1e.luxor.GroupListReorderRequest\x1a\x1f.luxor.GroupListReorderResponse\x12J\n" +
+	"\rIlluminateAll\x12\x1b.luxor.IlluminateAllRequest\x1a\x1c.luxor.IlluminateAllResponse\x12P\n" +
+	"\x0fIlluminateGroup\x12\x1d.luxor.IlluminateGroupRequest\x1a\x1e.luxor.IlluminateGroupResponse\x12P\n" +
+	"\x0fIlluminateTheme\x12\x1d.luxor.IlluminateThemeRequest\x1a\x1e.luxor.IlluminateThemeResponse\x12A\n" +
+	"\n" +
+	"ThemeClear\x12\x18.luxor.ThemeClearRequest\x1a\x19.luxor.ThemeClearResponse\x12;\n" +
+	"\bThemeGet\x12\x16.luxor.ThemeGetRequest\x1a\x17.luxor.ThemeGetResponse\x12G\n" +
+	"\fThemeListAdd\x12\x1a.luxor.ThemeListAddRequest\x1a\x1b.luxor.ThemeListAddResponse\x12M\n" +
+	"\x0eThemeListClear\x12\x1c.luxor.ThemeListClearRequest\x1a\x1d.luxor.ThemeListClearResponse\x12P\n" +
+	"\x0fThemeListDelete\x12\x1d.luxor.ThemeListDeleteRequest\x1a\x1e.luxor.ThemeListDeleteResponse\x12G\n" +
+	"\fThemeListGet\x12\x1a.luxor.ThemeListGetRequest\x1a\x1b.luxor.ThemeListGetResponse\x12P\n" +
+	"\x0fThemeListRename\x12\x1d.luxor.ThemeListRenameRequest\x1a\x1e.luxor.ThemeListRenameResponse\x12S\n" +
+	"\x10ThemeListReorder\x12\x1e.luxor.ThemeListReorderRequest\x1a\x1f.luxor.ThemeListReorderResponse\x12;\n" +
+	"\bThemeSet\x12\x16.luxor.ThemeSetRequest\x1a\x17.luxor.ThemeSetResponseB$Z\"github.com/scottlamb/luxor/luxorpbb\x06proto3"
+
+var (
+	file_luxor_proto_rawDescOnce sync.Once
+	file_luxor_proto_rawDescData []byte
+)
+
+func file_luxor_proto_rawDescGZIP() []byte {
+	file_luxor_proto_rawDescOnce.Do(func() {
+		file_luxor_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_luxor_proto_rawDesc), len(file_luxor_proto_rawDesc)))
+	})
+	return file_luxor_proto_rawDescData
+}
+
+var file_luxor_proto_msgTypes = make([]protoimpl.MessageInfo, 52)
+var file_luxor_proto_goTypes = []any{
+	(*AssignLightRequest)(nil),       // 0: luxor.AssignLightRequest
+	(*AssignLightResponse)(nil),      // 1: luxor.AssignLightResponse
+	(*Color)(nil),                    // 2: luxor.Color
+	(*ColorListGetRequest)(nil),      // 3: luxor.ColorListGetRequest
+	(*ColorListGetResponse)(nil),     // 4: luxor.ColorListGetResponse
+	(*ColorListSetRequest)(nil),      // 5: luxor.ColorListSetRequest
+	(*ColorListSetResponse)(nil),     // 6: luxor.ColorListSetResponse
+	(*ControllerNameRequest)(nil),    // 7: luxor.ControllerNameRequest
+	(*ControllerNameResponse)(nil),   // 8: luxor.ControllerNameResponse
+	(*ExtinguishAllRequest)(nil),     // 9: luxor.ExtinguishAllRequest
+	(*ExtinguishAllResponse)(nil),    // 10: luxor.ExtinguishAllResponse
+	(*FlashLightsRequest)(nil),       // 11: luxor.FlashLightsRequest
+	(*FlashLightsResponse)(nil),      // 12: luxor.FlashLightsResponse
+	(*Group)(nil),                    // 13: luxor.Group
+	(*GroupListAddRequest)(nil),      // 14: luxor.GroupListAddRequest
+	(*GroupListAddResponse)(nil),     // 15: luxor.GroupListAddResponse
+	(*GroupListClearRequest)(nil),    // 16: luxor.GroupListClearRequest
+	(*GroupListClearResponse)(nil),   // 17: luxor.GroupListClearResponse
+	(*GroupListDeleteRequest)(nil),   // 18: luxor.GroupListDeleteRequest
+	(*GroupListDeleteResponse)(nil),  // 19: luxor.GroupListDeleteResponse
+	(*GroupListGetRequest)(nil),      // 20: luxor.GroupListGetRequest
+	(*GroupListGetResponse)(nil),     // 21: luxor.GroupListGetResponse
+	(*GroupListRenameRequest)(nil),   // 22: luxor.GroupListRenameRequest
+	(*GroupListRenameResponse)(nil),  // 23: luxor.GroupListRenameResponse
+	(*GroupListReorderRequest)(nil),  // 24: luxor.GroupListReorderRequest
+	(*GroupListReorderResponse)(nil), // 25: luxor.GroupListReorderResponse
+	(*IlluminateAllRequest)(nil),     // 26: luxor.IlluminateAllRequest
+	(*IlluminateAllResponse)(nil),    // 27: luxor.IlluminateAllResponse
+	(*IlluminateGroupRequest)(nil),   // 28: luxor.IlluminateGroupRequest
+	(*IlluminateGroupResponse)(nil),  // 29: luxor.IlluminateGroupResponse
+	(*IlluminateThemeRequest)(nil),   // 30: luxor.IlluminateThemeRequest
+	(*IlluminateThemeResponse)(nil),  // 31: luxor.IlluminateThemeResponse
+	(*Theme)(nil),                    // 32: luxor.Theme
+	(*ThemeGroup)(nil),               // 33: luxor.ThemeGroup
+	(*ThemeClearRequest)(nil),        // 34: luxor.ThemeClearRequest
+	(*ThemeClearResponse)(nil),       // 35: luxor.ThemeClearResponse
+	(*ThemeGetRequest)(nil),          // 36: luxor.ThemeGetRequest
+	(*ThemeGetResponse)(nil),         // 37: luxor.ThemeGetResponse
+	(*ThemeListAddRequest)(nil),      // 38: luxor.ThemeListAddRequest
+	(*ThemeListAddResponse)(nil),     // 39: luxor.ThemeListAddResponse
+	(*ThemeListClearRequest)(nil),    // 40: luxor.ThemeListClearRequest
+	(*ThemeListClearResponse)(nil),   // 41: luxor.ThemeListClearResponse
+	(*ThemeListDeleteRequest)(nil),   // 42: luxor.ThemeListDeleteRequest
+	(*ThemeListDeleteResponse)(nil),  // 43: luxor.ThemeListDeleteResponse
+	(*ThemeListGetRequest)(nil),      // 44: luxor.ThemeListGetRequest
+	(*ThemeListGetResponse)(nil),     // 45: luxor.ThemeListGetResponse
+	(*ThemeListRenameRequest)(nil),   // 46: luxor.ThemeListRenameRequest
+	(*ThemeListRenameResponse)(nil),  // 47: luxor.ThemeListRenameResponse
+	(*ThemeListReorderRequest)(nil),  // 48: luxor.ThemeListReorderRequest
+	(*ThemeListReorderResponse)(nil), // 49: luxor.ThemeListReorderResponse
+	(*ThemeSetRequest)(nil),          // 50: luxor.ThemeSetRequest
+	(*ThemeSetResponse)(nil),         // 51: luxor.ThemeSetResponse
+}
+var file_luxor_proto_depIdxs = []int32{
+	2,  // 0: luxor.ColorListGetResponse.color_list:type_name -> luxor.Color
+	13, // 1: luxor.GroupListClearResponse.group_list:type_name -> luxor.Group
+	13, // 2: luxor.GroupListGetResponse.group_list:type_name -> luxor.Group
+	33, // 3: luxor.ThemeGetResponse.groups:type_name -> luxor.ThemeGroup
+	32, // 4: luxor.ThemeListGetResponse.theme_list:type_name -> luxor.Theme
+	33, // 5: luxor.ThemeSetRequest.groups:type_name -> luxor.ThemeGroup
+	0,  // 6: luxor.Controller.AssignLight:input_type -> luxor.AssignLightRequest
+	3,  // 7: luxor.Controller.ColorListGet:input_type -> luxor.ColorListGetRequest
+	5,  // 8: luxor.Controller.ColorListSet:input_type -> luxor.ColorListSetRequest
+	7,  // 9: luxor.Controller.ControllerName:input_type -> luxor.ControllerNameRequest
+	9,  // 10: luxor.Controller.ExtinguishAll:input_type -> luxor.ExtinguishAllRequest
+	11, // 11: luxor.Controller.FlashLights:input_type -> luxor.FlashLightsRequest
+	14, // 12: luxor.Controller.GroupListAdd:input_type -> luxor.GroupListAddRequest
+	16, // 13: luxor.Controller.GroupListClear:input_type -> luxor.GroupListClearRequest
+	18, // 14: luxor.Controller.GroupListDelete:input_type -> luxor.GroupListDeleteRequest
+	20, // 15: luxor.Controller.GroupListGet:input_type -> luxor.GroupListGetRequest
+	22, // 16: luxor.Controller.GroupListRename:input_type -> luxor.GroupListRenameRequest
+	24, // 17: luxor.Controller.GroupListReorder:input_type -> luxor.GroupListReorderRequest
+	26, // 18: luxor.Controller.IlluminateAll:input_type -> luxor.IlluminateAllRequest
+	28, // 19: luxor.Controller.IlluminateGroup:input_type -> luxor.IlluminateGroupRequest
+	30, // 20: luxor.Controller.IlluminateTheme:input_type -> luxor.IlluminateThemeRequest
+	34, // 21: luxor.Controller.ThemeClear:input_type -> luxor.ThemeClearRequest
+	36, // 22: luxor.Controller.ThemeGet:input_type -> luxor.ThemeGetRequest
+	38, // 23: luxor.Controller.ThemeListAdd:input_type -> luxor.ThemeListAddRequest
+	40, // 24: luxor.Controller.ThemeListClear:input_type -> luxor.ThemeListClearRequest
+	42, // 25: luxor.Controller.ThemeListDelete:input_type -> luxor.ThemeListDeleteRequest
+	44, // 26: luxor.Controller.ThemeListGet:input_type -> luxor.ThemeListGetRequest
+	46, // 27: luxor.Controller.ThemeListRename:input_type -> luxor.ThemeListRenameRequest
+	48, // 28: luxor.Controller.ThemeListReorder:input_type -> luxor.ThemeListReorderRequest
+	50, // 29: luxor.Controller.ThemeSet:input_type -> luxor.ThemeSetRequest
+	1,  // 30: luxor.Controller.AssignLight:output_type -> luxor.AssignLightResponse
+	4,  // 31: luxor.Controller.ColorListGet:output_type -> luxor.ColorListGetResponse
+	6,  // 32: luxor.Controller.ColorListSet:output_type -> luxor.ColorListSetResponse
+	8,  // 33: luxor.Controller.ControllerName:output_type -> luxor.ControllerNameResponse
+	10, // 34: luxor.Controller.ExtinguishAll:output_type -> luxor.ExtinguishAllResponse
+	12, // 35: luxor.Controller.FlashLights:output_type -> luxor.FlashLightsResponse
+	15, // 36: luxor.Controller.GroupListAdd:output_type -> luxor.GroupListAddResponse
+	17, // 37: luxor.Controller.GroupListClear:output_type -> luxor.GroupListClearResponse
+	19, // 38: luxor.Controller.GroupListDelete:output_type -> luxor.GroupListDeleteResponse
+	21, // 39: luxor.Controller.GroupListGet:output_type -> luxor.GroupListGetResponse
+	23, // 40: luxor.Controller.GroupListRename:output_type -> luxor.GroupListRenameResponse
+	25, // 41: luxor.Controller.GroupListReorder:output_type -> luxor.GroupListReorderResponse
+	27, // 42: luxor.Controller.IlluminateAll:output_type -> luxor.IlluminateAllResponse
+	29, // 43: luxor.Controller.IlluminateGroup:output_type -> luxor.IlluminateGroupResponse
+	31, // 44: luxor.Controller.IlluminateTheme:output_type -> luxor.IlluminateThemeResponse
+	35, // 45: luxor.Controller.ThemeClear:output_type -> luxor.ThemeClearResponse
+	37, // 46: luxor.Controller.ThemeGet:output_type -> luxor.ThemeGetResponse
+	39, // 47: luxor.Controller.ThemeListAdd:output_type -> luxor.ThemeListAddResponse
+	41, // 48: luxor.Controller.ThemeListClear:output_type -> luxor.ThemeListClearResponse
+	43, // 49: luxor.Controller.ThemeListDelete:output_type -> luxor.ThemeListDeleteResponse
+	45, // 50: luxor.Controller.ThemeListGet:output_type -> luxor.ThemeListGetResponse
+	47, // 51: luxor.Controller.ThemeListRename:output_type -> luxor.ThemeListRenameResponse
+	49, // 52: luxor.Controller.ThemeListReorder:output_type -> luxor.ThemeListReorderResponse
+	51, // 53: luxor.Controller.ThemeSet:output_type -> luxor.ThemeSetResponse
+	30, // [30:54] is the sub-list for method output_type
+	6,  // [6:30] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_luxor_proto_init() }
+func file_luxor_proto_init() {
+	if File_luxor_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_luxor_proto_rawDesc), len(file_luxor_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   52,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_luxor_proto_goTypes,
+		DependencyIndexes: file_luxor_proto_depIdxs,
+		MessageInfos:      file_luxor_proto_msgTypes,
+	}.Build()
+	File_luxor_proto = out.File
+	file_luxor_proto_goTypes = nil
+	file_luxor_proto_depIdxs = nil
+}
@@ -0,0 +1,1002 @@
+// gRPC mirror of the protocol.ColorController interface. Messages correspond
+// field-for-field to the protocol package's request and response types;
+// uint8 fields are widened to uint32. Each response's status is the
+// controller's application-level status, exactly as in the JSON protocol.
+//
+// Regenerate with: go generate ./luxorpb
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: luxor.proto
+
+package luxorpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Controller_AssignLight_FullMethodName      = "/luxor.Controller/AssignLight"
+	Controller_ColorListGet_FullMethodName     = "/luxor.Controller/ColorListGet"
+	Controller_ColorListSet_FullMethodName     = "/luxor.Controller/ColorListSet"
+	Controller_ControllerName_FullMethodName   = "/luxor.Controller/ControllerName"
+	Controller_ExtinguishAll_FullMethodName    = "/luxor.Controller/ExtinguishAll"
+	Controller_FlashLights_FullMethodName      = "/luxor.Controller/FlashLights"
+	Controller_GroupListAdd_FullMethodName     = "/luxor.Controller/GroupListAdd"
+	Controller_GroupListClear_FullMethodName   = "/luxor.Controller/GroupListClear"
+	Controller_GroupListDelete_FullMethodName  = "/luxor.Controller/GroupListDelete"
+	Controller_GroupListGet_FullMethodName     = "/luxor.Controller/GroupListGet"
+	Controller_GroupListRename_FullMethodName  = "/luxor.Controller/GroupListRename"
+	Controller_GroupListReorder_FullMethodName = "/luxor.Controller/GroupListReorder"
+	Controller_IlluminateAll_FullMethodName    = "/luxor.Controller/IlluminateAll"
+	Controller_IlluminateGroup_FullMethodName  = "/luxor.Controller/IlluminateGroup"
+	Controller_IlluminateTheme_FullMethodName  = "/luxor.Controller/IlluminateTheme"
+	Controller_ThemeClear_FullMethodName       = "/luxor.Controller/ThemeClear"
+	Controller_ThemeGet_FullMethodName         = "/luxor.Controller/ThemeGet"
+	Controller_ThemeListAdd_FullMethodName     = "/luxor.Controller/ThemeListAdd"
+	Controller_ThemeListClear_FullMethodName   = "/luxor.Controller/ThemeListClear"
+	Controller_ThemeListDelete_FullMethodName  = "/luxor.Controller/ThemeListDelete"
+	Controller_ThemeListGet_FullMethodName     = "/luxor.Controller/ThemeListGet"
+	Controller_ThemeListRename_FullMethodName  = "/luxor.Controller/ThemeListRename"
+	Controller_ThemeListReorder_FullMethodName = "/luxor.Controller/ThemeListReorder"
+	Controller_ThemeSet_FullMethodName         = "/luxor.Controller/ThemeSet"
+)
+
+// ControllerClient is the client API for Controller service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ControllerClient interface {
+	AssignLight(ctx context.Context, in *AssignLightRequest, opts ...grpc.CallOption) (*AssignLightResponse, error)
+	ColorListGet(ctx context.Context, in *ColorListGetRequest, opts ...grpc.CallOption) (*ColorListGetResponse, error)
+	ColorListSet(ctx context.Context, in *ColorListSetRequest, opts ...grpc.CallOption) (*ColorListSetResponse, error)
+	ControllerName(ctx context.Context, in *ControllerNameRequest, opts ...grpc.CallOption) (*ControllerNameResponse, error)
+	ExtinguishAll(ctx context.Context, in *ExtinguishAllRequest, opts ...grpc.CallOption) (*ExtinguishAllResponse, error)
+	FlashLights(ctx context.Context, in *FlashLightsRequest, opts ...grpc.CallOption) (*FlashLightsResponse, error)
+	GroupListAdd(ctx context.Context, in *GroupListAddRequest, opts ...grpc.CallOption) (*GroupListAddResponse, error)
+	GroupListClear(ctx context.Context, in *GroupListClearRequest, opts ...grpc.CallOption) (*GroupListClearResponse, error)
+	GroupListDelete(ctx context.Context, in *GroupListDeleteRequest, opts ...grpc.CallOption) (*GroupListDeleteResponse, error)
+	GroupListGet(ctx context.Context, in *GroupListGetRequest, opts ...grpc.CallOption) (*GroupListGetResponse, error)
+	GroupListRename(ctx context.Context, in *GroupListRenameRequest, opts ...grpc.CallOption) (*GroupListRenameResponse, error)
+	GroupListReorder(ctx context.Context, in *GroupListReorderRequest, opts ...grpc.CallOption) (*GroupListReorderResponse, error)
+	IlluminateAll(ctx context.Context, in *IlluminateAllRequest, opts ...grpc.CallOption) (*IlluminateAllResponse, error)
+	IlluminateGroup(ctx context.Context, in *IlluminateGroupRequest, opts ...grpc.CallOption) (*IlluminateGroupResponse, error)
+	IlluminateTheme(ctx context.Context, in *IlluminateThemeRequest, opts ...grpc.CallOption) (*IlluminateThemeResponse, error)
+	ThemeClear(ctx context.Context, in *ThemeClearRequest, opts ...grpc.CallOption) (*ThemeClearResponse, error)
+	ThemeGet(ctx context.Context, in *ThemeGetRequest, opts ...grpc.CallOption) (*ThemeGetResponse, error)
+	ThemeListAdd(ctx context.Context, in *ThemeListAddRequest, opts ...grpc.CallOption) (*ThemeListAddResponse, error)
+	ThemeListClear(ctx context.Context, in *ThemeListClearRequest, opts ...grpc.CallOption) (*ThemeListClearResponse, error)
+	ThemeListDelete(ctx context.Context, in *ThemeListDeleteRequest, opts ...grpc.CallOption) (*ThemeListDeleteResponse, error)
+	ThemeListGet(ctx context.Context, in *ThemeListGetRequest, opts ...grpc.CallOption) (*ThemeListGetResponse, error)
+	ThemeListRename(ctx context.Context, in *ThemeListRenameRequest, opts ...grpc.CallOption) (*ThemeListRenameResponse, error)
+	ThemeListReorder(ctx context.Context, in *ThemeListReorderRequest, opts ...grpc.CallOption) (*ThemeListReorderResponse, error)
+	ThemeSet(ctx context.Context, in *ThemeSetRequest, opts ...grpc.CallOption) (*ThemeSetResponse, error)
+}
+
+type controllerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewControllerClient(cc grpc.ClientConnInterface) ControllerClient {
+	return &controllerClient{cc}
+}
+
+func (c *controllerClient) AssignLight(ctx context.Context, in *AssignLightRequest, opts ...grpc.CallOption) (*AssignLightResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AssignLightResponse)
+	err := c.cc.Invoke(ctx, Controller_AssignLight_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) ColorListGet(ctx context.Context, in *ColorListGetRequest, opts ...grpc.CallOption) (*ColorListGetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ColorListGetResponse)
+	err := c.cc.Invoke(ctx, Controller_ColorListGet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) ColorListSet(ctx context.Context, in *ColorListSetRequest, opts ...grpc.CallOption) (*ColorListSetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ColorListSetResponse)
+	err := c.cc.Invoke(ctx, Controller_ColorListSet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) ControllerName(ctx context.Context, in *ControllerNameRequest, opts ...grpc.CallOption) (*ControllerNameResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ControllerNameResponse)
+	err := c.cc.Invoke(ctx, Controller_ControllerName_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) ExtinguishAll(ctx context.Context, in *ExtinguishAllRequest, opts ...grpc.CallOption) (*ExtinguishAllResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExtinguishAllResponse)
+	err := c.cc.Invoke(ctx, Controller_ExtinguishAll_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) FlashLights(ctx context.Context, in *FlashLightsRequest, opts ...grpc.CallOption) (*FlashLightsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FlashLightsResponse)
+	err := c.cc.Invoke(ctx, Controller_FlashLights_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) GroupListAdd(ctx context.Context, in *GroupListAddRequest, opts ...grpc.CallOption) (*GroupListAddResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GroupListAddResponse)
+	err := c.cc.Invoke(ctx, Controller_GroupListAdd_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) GroupListClear(ctx context.Context, in *GroupListClearRequest, opts ...grpc.CallOption) (*GroupListClearResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GroupListClearResponse)
+	err := c.cc.Invoke(ctx, Controller_GroupListClear_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) GroupListDelete(ctx context.Context, in *GroupListDeleteRequest, opts ...grpc.CallOption) (*GroupListDeleteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GroupListDeleteResponse)
+	err := c.cc.Invoke(ctx, Controller_GroupListDelete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) GroupListGet(ctx context.Context, in *GroupListGetRequest, opts ...grpc.CallOption) (*GroupListGetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GroupListGetResponse)
+	err := c.cc.Invoke(ctx, Controller_GroupListGet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) GroupListRename(ctx context.Context, in *GroupListRenameRequest, opts ...grpc.CallOption) (*GroupListRenameResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GroupListRenameResponse)
+	err := c.cc.Invoke(ctx, Controller_GroupListRename_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) GroupListReorder(ctx context.Context, in *GroupListReorderRequest, opts ...grpc.CallOption) (*GroupListReorderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GroupListReorderResponse)
+	err := c.cc.Invoke(ctx, Controller_GroupListReorder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) IlluminateAll(ctx context.Context, in *IlluminateAllRequest, opts ...grpc.CallOption) (*IlluminateAllResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IlluminateAllResponse)
+	err := c.cc.Invoke(ctx, Controller_IlluminateAll_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) IlluminateGroup(ctx context.Context, in *IlluminateGroupRequest, opts ...grpc.CallOption) (*IlluminateGroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IlluminateGroupResponse)
+	err := c.cc.Invoke(ctx, Controller_IlluminateGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) IlluminateTheme(ctx context.Context, in *IlluminateThemeRequest, opts ...grpc.CallOption) (*IlluminateThemeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IlluminateThemeResponse)
+	err := c.cc.Invoke(ctx, Controller_IlluminateTheme_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) ThemeClear(ctx context.Context, in *ThemeClearRequest, opts ...grpc.CallOption) (*ThemeClearResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ThemeClearResponse)
+	err := c.cc.Invoke(ctx, Controller_ThemeClear_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) ThemeGet(ctx context.Context, in *ThemeGetRequest, opts ...grpc.CallOption) (*ThemeGetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ThemeGetResponse)
+	err := c.cc.Invoke(ctx, Controller_ThemeGet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) ThemeListAdd(ctx context.Context, in *ThemeListAddRequest, opts ...grpc.CallOption) (*ThemeListAddResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ThemeListAddResponse)
+	err := c.cc.Invoke(ctx, Controller_ThemeListAdd_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) ThemeListClear(ctx context.Context, in *ThemeListClearRequest, opts ...grpc.CallOption) (*ThemeListClearResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ThemeListClearResponse)
+	err := c.cc.Invoke(ctx, Controller_ThemeListClear_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) ThemeListDelete(ctx context.Context, in *ThemeListDeleteRequest, opts ...grpc.CallOption) (*ThemeListDeleteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ThemeListDeleteResponse)
+	err := c.cc.Invoke(ctx, Controller_ThemeListDelete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) ThemeListGet(ctx context.Context, in *ThemeListGetRequest, opts ...grpc.CallOption) (*ThemeListGetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ThemeListGetResponse)
+	err := c.cc.Invoke(ctx, Controller_ThemeListGet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) ThemeListRename(ctx context.Context, in *ThemeListRenameRequest, opts ...grpc.CallOption) (*ThemeListRenameResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ThemeListRenameResponse)
+	err := c.cc.Invoke(ctx, Controller_ThemeListRename_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) ThemeListReorder(ctx context.Context, in *ThemeListReorderRequest, opts ...grpc.CallOption) (*ThemeListReorderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ThemeListReorderResponse)
+	err := c.cc.Invoke(ctx, Controller_ThemeListReorder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controllerClient) ThemeSet(ctx context.Context, in *ThemeSetRequest, opts ...grpc.CallOption) (*ThemeSetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ThemeSetResponse)
+	err := c.cc.Invoke(ctx, Controller_ThemeSet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ControllerServer is the server API for Controller service.
+// All implementations must embed UnimplementedControllerServer
+// for forward compatibility.
+type ControllerServer interface {
+	AssignLight(context.Context, *AssignLightRequest) (*AssignLightResponse, error)
+	ColorListGet(context.Context, *ColorListGetRequest) (*ColorListGetResponse, error)
+	ColorListSet(context.Context, *ColorListSetRequest) (*ColorListSetResponse, error)
+	ControllerName(context.Context, *ControllerNameRequest) (*ControllerNameResponse, error)
+	ExtinguishAll(context.Context, *ExtinguishAllRequest) (*ExtinguishAllResponse, error)
+	FlashLights(context.Context, *FlashLightsRequest) (*FlashLightsResponse, error)
+	GroupListAdd(context.Context, *GroupListAddRequest) (*GroupListAddResponse, error)
+	GroupListClear(context.Context, *GroupListClearRequest) (*GroupListClearResponse, error)
+	GroupListDelete(context.Context, *GroupListDeleteRequest) (*GroupListDeleteResponse, error)
+	GroupListGet(context.Context, *GroupListGetRequest) (*GroupListGetResponse, error)
+	GroupListRename(context.Context, *GroupListRenameRequest) (*GroupListRenameResponse, error)
+	GroupListReorder(context.Context, *GroupListReorderRequest) (*GroupListReorderResponse, error)
+	IlluminateAll(context.Context, *IlluminateAllRequest) (*IlluminateAllResponse, error)
+	IlluminateGroup(context.Context, *IlluminateGroupRequest) (*IlluminateGroupResponse, error)
+	IlluminateTheme(context.Context, *IlluminateThemeRequest) (*IlluminateThemeResponse, error)
+	ThemeClear(context.Context, *ThemeClearRequest) (*ThemeClearResponse, error)
+	ThemeGet(context.Context, *ThemeGetRequest) (*ThemeGetResponse, error)
+	ThemeListAdd(context.Context, *ThemeListAddRequest) (*ThemeListAddResponse, error)
+	ThemeListClear(context.Context, *ThemeListClearRequest) (*ThemeListClearResponse, error)
+	ThemeListDelete(context.Context, *ThemeListDeleteRequest) (*ThemeListDeleteResponse, error)
+	ThemeListGet(context.Context, *ThemeListGetRequest) (*ThemeListGetResponse, error)
+	ThemeListRename(context.Context, *ThemeListRenameRequest) (*ThemeListRenameResponse, error)
+	ThemeListReorder(context.Context, *ThemeListReorderRequest) (*ThemeListReorderResponse, error)
+	ThemeSet(context.Context, *ThemeSetRequest) (*ThemeSetResponse, error)
+	mustEmbedUnimplementedControllerServer()
+}
+
+// UnimplementedControllerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedControllerServer struct{}
+
+func (UnimplementedControllerServer) AssignLight(context.Context, *AssignLightRequest) (*AssignLightResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AssignLight not implemented")
+}
+func (UnimplementedControllerServer) ColorListGet(context.Context, *ColorListGetRequest) (*ColorListGetResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ColorListGet not implemented")
+}
+func (UnimplementedControllerServer) ColorListSet(context.Context, *ColorListSetRequest) (*ColorListSetResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ColorListSet not implemented")
+}
+func (UnimplementedControllerServer) ControllerName(context.Context, *ControllerNameRequest) (*ControllerNameResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ControllerName not implemented")
+}
+func (UnimplementedControllerServer) ExtinguishAll(context.Context, *ExtinguishAllRequest) (*ExtinguishAllResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ExtinguishAll not implemented")
+}
+func (UnimplementedControllerServer) FlashLights(context.Context, *FlashLightsRequest) (*FlashLightsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method FlashLights not implemented")
+}
+func (UnimplementedControllerServer) GroupListAdd(context.Context, *GroupListAddRequest) (*GroupListAddResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GroupListAdd not implemented")
+}
+func (UnimplementedControllerServer) GroupListClear(context.Context, *GroupListClearRequest) (*GroupListClearResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GroupListClear not implemented")
+}
+func (UnimplementedControllerServer) GroupListDelete(context.Context, *GroupListDeleteRequest) (*GroupListDeleteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GroupListDelete not implemented")
+}
+func (UnimplementedControllerServer) GroupListGet(context.Context, *GroupListGetRequest) (*GroupListGetResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GroupListGet not implemented")
+}
+func (UnimplementedControllerServer) GroupListRename(context.Context, *GroupListRenameRequest) (*GroupListRenameResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GroupListRename not implemented")
+}
+func (UnimplementedControllerServer) GroupListReorder(context.Context, *GroupListReorderRequest) (*GroupListReorderResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GroupListReorder not implemented")
+}
+func (UnimplementedControllerServer) IlluminateAll(context.Context, *IlluminateAllRequest) (*IlluminateAllResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method IlluminateAll not implemented")
+}
+func (UnimplementedControllerServer) IlluminateGroup(context.Context, *IlluminateGroupRequest) (*IlluminateGroupResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method IlluminateGroup not implemented")
+}
+func (UnimplementedControllerServer) IlluminateTheme(context.Context, *IlluminateThemeRequest) (*IlluminateThemeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method IlluminateTheme not implemented")
+}
+func (UnimplementedControllerServer) ThemeClear(context.Context, *ThemeClearRequest) (*ThemeClearResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ThemeClear not implemented")
+}
+func (UnimplementedControllerServer) ThemeGet(context.Context, *ThemeGetRequest) (*ThemeGetResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ThemeGet not implemented")
+}
+func (UnimplementedControllerServer) ThemeListAdd(context.Context, *ThemeListAddRequest) (*ThemeListAddResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ThemeListAdd not implemented")
+}
+func (UnimplementedControllerServer) ThemeListClear(context.Context, *ThemeListClearRequest) (*ThemeListClearResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ThemeListClear not implemented")
+}
+func (UnimplementedControllerServer) ThemeListDelete(context.Context, *ThemeListDeleteRequest) (*ThemeListDeleteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ThemeListDelete not implemented")
+}
+func (UnimplementedControllerServer) ThemeListGet(context.Context, *ThemeListGetRequest) (*ThemeListGetResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ThemeListGet not implemented")
+}
+func (UnimplementedControllerServer) ThemeListRename(context.Context, *ThemeListRenameRequest) (*ThemeListRenameResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ThemeListRename not implemented")
+}
+func (UnimplementedControllerServer) ThemeListReorder(context.Context, *ThemeListReorderRequest) (*ThemeListReorderResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ThemeListReorder not implemented")
+}
+func (UnimplementedControllerServer) ThemeSet(context.Context, *ThemeSetRequest) (*ThemeSetResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ThemeSet not implemented")
+}
+func (UnimplementedControllerServer) mustEmbedUnimplementedControllerServer() {}
+func (UnimplementedControllerServer) testEmbeddedByValue()                    {}
+
+// UnsafeControllerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ControllerServer will
+// result in compilation errors.
+type UnsafeControllerServer interface {
+	mustEmbedUnimplementedControllerServer()
+}
+
+func RegisterControllerServer(s grpc.ServiceRegistrar, srv ControllerServer) {
+	// If the following call panics, it indicates UnimplementedControllerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Controller_ServiceDesc, srv)
+}
+
+func _Controller_AssignLight_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignLightRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).AssignLight(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Controller_AssignLight_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).AssignLight(ctx, req.(*AssignLightRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_ColorListGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ColorListGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).ColorListGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Controller_ColorListGet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).ColorListGet(ctx, req.(*ColorListGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_ColorListSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ColorListSetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).ColorListSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Controller_ColorListSet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).ColorListSet(ctx, req.(*ColorListSetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_ControllerName_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ControllerNameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).ControllerName(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Controller_ControllerName_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).ControllerName(ctx, req.(*ControllerNameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_ExtinguishAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExtinguishAllRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).ExtinguishAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Controller_ExtinguishAll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).ExtinguishAll(ctx, req.(*ExtinguishAllRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_FlashLights_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FlashLightsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).FlashLights(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Controller_FlashLights_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).FlashLights(ctx, req.(*FlashLightsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_GroupListAdd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupListAddRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).GroupListAdd(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Controller_GroupListAdd_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).GroupListAdd(ctx, req.(*GroupListAddRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_GroupListClear_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupListClearRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).GroupListClear(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Controller_GroupListClear_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).GroupListClear(ctx, req.(*GroupListClearRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_GroupListDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupListDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).GroupListDelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Controller_GroupListDelete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).GroupListDelete(ctx, req.(*GroupListDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_GroupListGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupListGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).GroupListGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Controller_GroupListGet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).GroupListGet(ctx, req.(*GroupListGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_GroupListRename_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupListRenameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).GroupListRename(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Controller_GroupListRename_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).GroupListRename(ctx, req.(*GroupListRenameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_GroupListReorder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupListReorderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).GroupListReorder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Controller_GroupListReorder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).GroupListReorder(ctx, req.(*GroupListReorderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_IlluminateAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IlluminateAllRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).IlluminateAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Controller_IlluminateAll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).IlluminateAll(ctx, req.(*IlluminateAllRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_IlluminateGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IlluminateGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).IlluminateGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Controller_IlluminateGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).IlluminateGroup(ctx, req.(*IlluminateGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_IlluminateTheme_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IlluminateThemeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).IlluminateTheme(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Controller_IlluminateTheme_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).IlluminateTheme(ctx, req.(*IlluminateThemeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_ThemeClear_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ThemeClearRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).ThemeClear(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Controller_ThemeClear_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).ThemeClear(ctx, req.(*ThemeClearRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_ThemeGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ThemeGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).ThemeGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Controller_ThemeGet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).ThemeGet(ctx, req.(*ThemeGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_ThemeListAdd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ThemeListAddRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).ThemeListAdd(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Controller_ThemeListAdd_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).ThemeListAdd(ctx, req.(*ThemeListAddRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_ThemeListClear_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ThemeListClearRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).ThemeListClear(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Controller_ThemeListClear_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).ThemeListClear(ctx, req.(*ThemeListClearRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_ThemeListDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ThemeListDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).ThemeListDelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Controller_ThemeListDelete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).ThemeListDelete(ctx, req.(*ThemeListDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_ThemeListGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ThemeListGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).ThemeListGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Controller_ThemeListGet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).ThemeListGet(ctx, req.(*ThemeListGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_ThemeListRename_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ThemeListRenameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).ThemeListRename(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Controller_ThemeListRename_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).ThemeListRename(ctx, req.(*ThemeListRenameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_ThemeListReorder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ThemeListReorderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).ThemeListReorder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Controller_ThemeListReorder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).ThemeListReorder(ctx, req.(*ThemeListReorderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Controller_ThemeSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ThemeSetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControllerServer).ThemeSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Controller_ThemeSet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControllerServer).ThemeSet(ctx, req.(*ThemeSetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Controller_ServiceDesc is the grpc.ServiceDesc for Controller service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Controller_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "luxor.Controller",
+	HandlerType: (*ControllerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AssignLight",
+			Handler:    _Controller_AssignLight_Handler,
+		},
+		{
+			MethodName: "ColorListGet",
+			Handler:    _Controller_ColorListGet_Handler,
+		},
+		{
+			MethodName: "ColorListSet",
+			Handler:    _Controller_ColorListSet_Handler,
+		},
+		{
+			MethodName: "ControllerName",
+			Handler:    _Controller_ControllerName_Handler,
+		},
+		{
+			MethodName: "ExtinguishAll",
+			Handler:    _Controller_ExtinguishAll_Handler,
+		},
+		{
+			MethodName: "FlashLights",
+			Handler:    _Controller_FlashLights_Handler,
+		},
+		{
+			MethodName: "GroupListAdd",
+			Handler:    _Controller_GroupListAdd_Handler,
+		},
+		{
+			MethodName: "GroupListClear",
+			Handler:    _Controller_GroupListClear_Handler,
+		},
+		{
+			MethodName: "GroupListDelete",
+			Handler:    _Controller_GroupListDelete_Handler,
+		},
+		{
+			MethodName: "GroupListGet",
+			Handler:    _Controller_GroupListGet_Handler,
+		},
+		{
+			MethodName: "GroupListRename",
+			Handler:    _Controller_GroupListRename_Handler,
+		},
+		{
+			MethodName: "GroupListReorder",
+			Handler:    _Controller_GroupListReorder_Handler,
+		},
+		{
+			MethodName: "IlluminateAll",
+			Handler:    _Controller_IlluminateAll_Handler,
+		},
+		{
+			MethodName: "IlluminateGroup",
+			Handler:    _Controller_IlluminateGroup_Handler,
+		},
+		{
+			MethodName: "IlluminateTheme",
+			Handler:    _Controller_IlluminateTheme_Handler,
+		},
+		{
+			MethodName: "ThemeClear",
+			Handler:    _Controller_ThemeClear_Handler,
+		},
+		{
+			MethodName: "ThemeGet",
+			Handler:    _Controller_ThemeGet_Handler,
+		},
+		{
+			MethodName: "ThemeListAdd",
+			Handler:    _Controller_ThemeListAdd_Handler,
+		},
+		{
+			MethodName: "ThemeListClear",
+			Handler:    _Controller_ThemeListClear_Handler,
+		},
+		{
+			MethodName: "ThemeListDelete",
+			Handler:    _Controller_ThemeListDelete_Handler,
+		},
+		{
+			MethodName: "ThemeListGet",
+			Handler:    _Controller_ThemeListGet_Handler,
+		},
+		{
+			MethodName: "ThemeListRename",
+			Handler:    _Controller_ThemeListRename_Handler,
+		},
+		{
+			MethodName: "ThemeListReorder",
+			Handler:    _Controller_ThemeListReorder_Handler,
+		},
+		{
+			MethodName: "ThemeSet",
+			Handler:    _Controller_ThemeSet_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "luxor.proto",
+}
@@ -0,0 +1,510 @@
+// Package mqttbridge exposes a Luxor controller over MQTT using Home
+// Assistant's discovery conventions.
+//
+// Each group from GroupListGet becomes a dimmable light (JSON schema), each
+// theme from ThemeListGet becomes a switch, and "all off" becomes a button.
+// Discovery configs and states are retained; availability is tracked through
+// a last-will message. With the default prefixes, the topics for a controller
+// whose node ID is "luxor" are:
+//
+//	homeassistant/light/luxor/group_<number>/config
+//	homeassistant/switch/luxor/theme_<index>/config
+//	homeassistant/button/luxor/all_off/config
+//	luxor/luxor/availability                  "online" or "offline"
+//	luxor/luxor/group/<number>/state          {"state":"ON","brightness":60}
+//	luxor/luxor/group/<number>/set            same, to IlluminateGroup
+//	luxor/luxor/theme/<index>/state           "ON" or "OFF"
+//	luxor/luxor/theme/<index>/set             same, to IlluminateTheme
+//	luxor/luxor/all/off/set                   "PRESS", to ExtinguishAll
+//
+// Entities are keyed by group number and theme index, so renaming a group or
+// theme updates the existing entity's discovery config in place. The bridge
+// subscribes to its own retained discovery configs, so entities deleted while
+// it wasn't running are removed too.
+package mqttbridge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/scottlamb/luxor/protocol"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultTopicPrefix     = "luxor"
+	DefaultDiscoveryPrefix = "homeassistant"
+	DefaultPollInterval    = 10 * time.Second
+
+	payloadOnline  = "online"
+	payloadOffline = "offline"
+	payloadOn      = "ON"
+	payloadOff     = "OFF"
+	payloadPress   = "PRESS"
+
+	publishTimeout = 10 * time.Second
+)
+
+// Bridge connects one controller to an MQTT broker.
+type Bridge struct {
+	Controller protocol.Controller
+
+	// Broker is the broker's URL, such as "tcp://localhost:1883".
+	Broker   string
+	ClientID string
+	Username string
+	Password string
+
+	// NodeID distinguishes this controller's topics and entities from
+	// others on the same broker. If empty, it is derived from the
+	// controller's name.
+	NodeID string
+
+	// TopicPrefix and DiscoveryPrefix default to DefaultTopicPrefix and
+	// DefaultDiscoveryPrefix.
+	TopicPrefix     string
+	DiscoveryPrefix string
+
+	// PollInterval is how often to poll the controller for changes made
+	// elsewhere. Defaults to DefaultPollInterval.
+	PollInterval time.Duration
+
+	client   mqtt.Client
+	base     string
+	commands chan func(context.Context) error
+
+	mu         sync.Mutex
+	controller string            // controller's name, for the device block.
+	published  map[string]string // retained topic -> payload last published.
+	retained   map[string]bool   // discovery config topics retained on the broker.
+	lastOn     map[uint8]uint8   // group number -> last non-zero intensity.
+}
+
+type device struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name"`
+	Manufacturer string   `json:"manufacturer"`
+	Model        string   `json:"model"`
+}
+
+type discoveryConfig struct {
+	Name                string   `json:"name"`
+	UniqueID            string   `json:"unique_id"`
+	Schema              string   `json:"schema,omitempty"`
+	CommandTopic        string   `json:"command_topic"`
+	StateTopic          string   `json:"state_topic,omitempty"`
+	AvailabilityTopic   string   `json:"availability_topic"`
+	PayloadOn           string   `json:"payload_on,omitempty"`
+	PayloadOff          string   `json:"payload_off,omitempty"`
+	PayloadPress        string   `json:"payload_press,omitempty"`
+	Brightness          bool     `json:"brightness,omitempty"`
+	BrightnessScale     int      `json:"brightness_scale,omitempty"`
+	SupportedColorModes []string `json:"supported_color_modes,omitempty"`
+	Device              device   `json:"device"`
+}
+
+// lightState is both the state and command payload of a group's light.
+type lightState struct {
+	State      string `json:"state"`
+	Brightness *int   `json:"brightness,omitempty"`
+}
+
+// SanitizeNodeID converts a controller name into a node ID usable in MQTT
+// topics and Home Assistant unique IDs.
+func SanitizeNodeID(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	if b.Len() == 0 {
+		return "luxor"
+	}
+	return b.String()
+}
+
+func (b *Bridge) discoveryPrefix() string {
+	if b.DiscoveryPrefix != "" {
+		return b.DiscoveryPrefix
+	}
+	return DefaultDiscoveryPrefix
+}
+
+func (b *Bridge) availabilityTopic() string { return b.base + "/availability" }
+
+func (b *Bridge) groupTopic(n uint8) string {
+	return b.base + "/group/" + strconv.Itoa(int(n))
+}
+
+func (b *Bridge) themeTopic(index uint8) string {
+	return b.base + "/theme/" + strconv.Itoa(int(index))
+}
+
+func (b *Bridge) configTopic(component, object string) string {
+	return b.discoveryPrefix() + "/" + component + "/" + b.NodeID + "/" + object + "/config"
+}
+
+// Run connects to the broker and bridges until ctx is done. On return, the
+// bridge marks itself offline and disconnects.
+func (b *Bridge) Run(ctx context.Context) error {
+	if b.NodeID == "" || b.controller == "" {
+		resp, err := b.Controller.ControllerName(ctx, &protocol.ControllerNameRequest{})
+		if err != nil {
+			return err
+		}
+		b.controller = resp.Controller
+		if b.NodeID == "" {
+			b.NodeID = SanitizeNodeID(resp.Controller)
+		}
+	}
+	prefix := b.TopicPrefix
+	if prefix == "" {
+		prefix = DefaultTopicPrefix
+	}
+	b.base = prefix + "/" + b.NodeID
+	b.published = make(map[string]string)
+	b.retained = make(map[string]bool)
+	b.lastOn = make(map[uint8]uint8)
+	b.commands = make(chan func(context.Context) error, 16)
+	clientID := b.ClientID
+	if clientID == "" {
+		clientID = "luxor-" + b.NodeID
+	}
+	opts := mqtt.NewClientOptions().
+		AddBroker(b.Broker).
+		SetClientID(clientID).
+		SetUsername(b.Username).
+		SetPassword(b.Password).
+		SetWill(b.availabilityTopic(), payloadOffline, 1, true).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetOnConnectHandler(b.onConnect)
+	b.client = mqtt.NewClient(opts)
+	if err := wait(ctx, b.client.Connect()); err != nil {
+		return err
+	}
+	defer func() {
+		wait(context.Background(), b.client.Publish(b.availabilityTopic(), 1, true, payloadOffline))
+		b.client.Disconnect(250)
+	}()
+
+	interval := b.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := b.poll(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("mqttbridge: poll failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case cmd := <-b.commands:
+			if err := cmd(ctx); err != nil {
+				log.Printf("mqttbridge: command failed: %v", err)
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+// onConnect (re)subscribes to command topics and the bridge's discovery
+// configs, and forgets what was published so that the next poll republishes
+// everything.
+func (b *Bridge) onConnect(c mqtt.Client) {
+	b.mu.Lock()
+	b.published = make(map[string]string)
+	b.retained = make(map[string]bool)
+	b.mu.Unlock()
+	subs := map[string]mqtt.MessageHandler{
+		b.base + "/group/+/set": b.onGroupCommand,
+		b.base + "/theme/+/set": b.onThemeCommand,
+		b.base + "/all/off/set": b.onAllOffCommand,
+		b.configTopic("+", "+"): b.onConfig,
+	}
+	for topic, handler := range subs {
+		if t := c.Subscribe(topic, 1, handler); t.WaitTimeout(publishTimeout) && t.Error() != nil {
+			log.Printf("mqttbridge: subscribe to %s failed: %v", topic, t.Error())
+		}
+	}
+	b.enqueue(func(context.Context) error { return nil }) // trigger a poll.
+}
+
+func (b *Bridge) enqueue(cmd func(context.Context) error) {
+	select {
+	case b.commands <- cmd:
+	default:
+		log.Printf("mqttbridge: command queue full; dropping command")
+	}
+}
+
+// topicNumber extracts the uint8 preceding the final "/set" in topic.
+func topicNumber(topic string) (uint8, error) {
+	parts := strings.Split(topic, "/")
+	if len(parts) < 2 {
+		return 0, fmt.Errorf("bad topic %q", topic)
+	}
+	n, err := strconv.ParseUint(parts[len(parts)-2], 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("bad topic %q: %v", topic, err)
+	}
+	return uint8(n), nil
+}
+
+func (b *Bridge) onGroupCommand(_ mqtt.Client, msg mqtt.Message) {
+	n, err := topicNumber(msg.Topic())
+	if err != nil {
+		log.Printf("mqttbridge: %v", err)
+		return
+	}
+	var cmd lightState
+	if err := json.Unmarshal(msg.Payload(), &cmd); err != nil {
+		log.Printf("mqttbridge: bad command %q on %s: %v", msg.Payload(), msg.Topic(), err)
+		return
+	}
+	b.enqueue(func(ctx context.Context) error {
+		var intensity uint8
+		switch {
+		case cmd.State == payloadOff:
+			intensity = 0
+		case cmd.Brightness != nil:
+			if *cmd.Brightness < 0 || *cmd.Brightness > protocol.MaxIntensity {
+				return fmt.Errorf("group %d: brightness %d out of range", n, *cmd.Brightness)
+			}
+			intensity = uint8(*cmd.Brightness)
+		default:
+			b.mu.Lock()
+			intensity = b.lastOn[n]
+			b.mu.Unlock()
+			if intensity == 0 {
+				intensity = protocol.MaxIntensity
+			}
+		}
+		_, err := b.Controller.IlluminateGroup(ctx, &protocol.IlluminateGroupRequest{GroupNumber: n, Intensity: intensity})
+		return err
+	})
+}
+
+func (b *Bridge) onThemeCommand(_ mqtt.Client, msg mqtt.Message) {
+	index, err := topicNumber(msg.Topic())
+	if err != nil {
+		log.Printf("mqttbridge: %v", err)
+		return
+	}
+	var onOff uint8
+	switch string(msg.Payload()) {
+	case payloadOn:
+		onOff = 1
+	case payloadOff:
+	default:
+		log.Printf("mqttbridge: bad command %q on %s", msg.Payload(), msg.Topic())
+		return
+	}
+	b.enqueue(func(ctx context.Context) error {
+		_, err := b.Controller.IlluminateTheme(ctx, &protocol.IlluminateThemeRequest{ThemeIndex: index, OnOff: onOff})
+		return err
+	})
+}
+
+// onConfig tracks which of the bridge's discovery configs the broker retains,
+// including those from earlier runs, so publishAll can remove stale ones.
+func (b *Bridge) onConfig(_ mqtt.Client, msg mqtt.Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(msg.Payload()) == 0 {
+		delete(b.retained, msg.Topic())
+	} else {
+		b.retained[msg.Topic()] = true
+	}
+}
+
+func (b *Bridge) onAllOffCommand(_ mqtt.Client, msg mqtt.Message) {
+	if string(msg.Payload()) != payloadPress {
+		log.Printf("mqttbridge: bad command %q on %s", msg.Payload(), msg.Topic())
+		return
+	}
+	b.enqueue(func(ctx context.Context) error {
+		_, err := b.Controller.ExtinguishAll(ctx, &protocol.ExtinguishAllRequest{})
+		return err
+	})
+}
+
+func wait(ctx context.Context, t mqtt.Token) error {
+	select {
+	case <-t.Done():
+		return t.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(publishTimeout):
+		return errors.New("timed out waiting for broker")
+	}
+}
+
+// publish publishes a retained payload if it differs from the last one
+// published to the topic. An empty payload removes the retained message.
+func (b *Bridge) publish(ctx context.Context, topic, payload string) error {
+	b.mu.Lock()
+	last, ok := b.published[topic]
+	b.mu.Unlock()
+	if ok && last == payload {
+		return nil
+	}
+	if err := wait(ctx, b.client.Publish(topic, 1, true, payload)); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	if payload == "" {
+		delete(b.published, topic)
+	} else {
+		b.published[topic] = payload
+	}
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *Bridge) publishJSON(ctx context.Context, topic string, v interface{}) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return b.publish(ctx, topic, string(payload))
+}
+
+func (b *Bridge) device() device {
+	return device{
+		Identifiers:  []string{"luxor_" + b.NodeID},
+		Name:         b.controller,
+		Manufacturer: "FX Luminaire",
+		Model:        "Luxor ZD",
+	}
+}
+
+// poll reads the groups and themes and publishes any changed configs and
+// states. Entities which have disappeared have their configs removed.
+func (b *Bridge) poll(ctx context.Context) error {
+	groups, err := b.Controller.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	if err == nil {
+		var themes *protocol.ThemeListGetResponse
+		themes, err = b.Controller.ThemeListGet(ctx, &protocol.ThemeListGetRequest{})
+		if err == nil {
+			err = b.publishAll(ctx, groups.GroupList, themes.ThemeList)
+		}
+	}
+	availability := payloadOnline
+	if err != nil {
+		availability = payloadOffline
+	}
+	if pubErr := b.publish(ctx, b.availabilityTopic(), availability); err == nil {
+		err = pubErr
+	}
+	return err
+}
+
+func (b *Bridge) publishAll(ctx context.Context, groups []protocol.Group, themes []protocol.Theme) error {
+	wanted := make(map[string]bool)
+	dev := b.device()
+	for _, g := range groups {
+		topic := b.groupTopic(g.GroupNumber)
+		config := b.configTopic("light", fmt.Sprintf("group_%d", g.GroupNumber))
+		wanted[config] = true
+		if err := b.publishJSON(ctx, config, &discoveryConfig{
+			Name:                g.Name,
+			UniqueID:            fmt.Sprintf("luxor_%s_group_%d", b.NodeID, g.GroupNumber),
+			Schema:              "json",
+			CommandTopic:        topic + "/set",
+			StateTopic:          topic + "/state",
+			AvailabilityTopic:   b.availabilityTopic(),
+			Brightness:          true,
+			BrightnessScale:     protocol.MaxIntensity,
+			SupportedColorModes: []string{"brightness"},
+			Device:              dev,
+		}); err != nil {
+			return err
+		}
+		state := lightState{State: payloadOff}
+		if g.Intensity > 0 {
+			brightness := int(g.Intensity)
+			if brightness > protocol.MaxIntensity {
+				brightness = protocol.MaxIntensity
+			}
+			state = lightState{State: payloadOn, Brightness: &brightness}
+			b.mu.Lock()
+			b.lastOn[g.GroupNumber] = uint8(brightness)
+			b.mu.Unlock()
+		}
+		if err := b.publishJSON(ctx, topic+"/state", &state); err != nil {
+			return err
+		}
+	}
+	for _, t := range themes {
+		topic := b.themeTopic(t.ThemeIndex)
+		config := b.configTopic("switch", fmt.Sprintf("theme_%d", t.ThemeIndex))
+		wanted[config] = true
+		if err := b.publishJSON(ctx, config, &discoveryConfig{
+			Name:              t.Name,
+			UniqueID:          fmt.Sprintf("luxor_%s_theme_%d", b.NodeID, t.ThemeIndex),
+			CommandTopic:      topic + "/set",
+			StateTopic:        topic + "/state",
+			AvailabilityTopic: b.availabilityTopic(),
+			PayloadOn:         payloadOn,
+			PayloadOff:        payloadOff,
+			Device:            dev,
+		}); err != nil {
+			return err
+		}
+		state := payloadOff
+		if t.OnOff != 0 {
+			state = payloadOn
+		}
+		if err := b.publish(ctx, topic+"/state", state); err != nil {
+			return err
+		}
+	}
+	allOff := b.configTopic("button", "all_off")
+	wanted[allOff] = true
+	if err := b.publishJSON(ctx, allOff, &discoveryConfig{
+		Name:              "All off",
+		UniqueID:          fmt.Sprintf("luxor_%s_all_off", b.NodeID),
+		CommandTopic:      b.base + "/all/off/set",
+		AvailabilityTopic: b.availabilityTopic(),
+		PayloadPress:      payloadPress,
+		Device:            dev,
+	}); err != nil {
+		return err
+	}
+
+	// Remove entities which no longer exist, including ones retained from
+	// earlier runs.
+	prefix := b.discoveryPrefix() + "/"
+	var stale []string
+	b.mu.Lock()
+	for topic := range b.published {
+		if strings.HasPrefix(topic, prefix) && !wanted[topic] {
+			stale = append(stale, topic)
+		}
+	}
+	for topic := range b.retained {
+		if _, ok := b.published[topic]; !ok && !wanted[topic] {
+			stale = append(stale, topic)
+		}
+	}
+	b.mu.Unlock()
+	for _, topic := range stale {
+		if err := b.publish(ctx, topic, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
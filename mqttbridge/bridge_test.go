@@ -0,0 +1,206 @@
+package mqttbridge_test
+
+import (
+	"context"
+	"encoding/json"
+	mochi "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/mochi-mqtt/server/v2/packets"
+	"github.com/scottlamb/luxor/emulator"
+	"github.com/scottlamb/luxor/mqttbridge"
+	"github.com/scottlamb/luxor/protocol"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// broker is an embedded broker which records the latest payload per topic.
+type broker struct {
+	*mochi.Server
+	url string
+
+	mu     sync.Mutex
+	topics map[string]string
+}
+
+func newBroker(t *testing.T) *broker {
+	s := mochi.New(&mochi.Options{
+		InlineClient: true,
+		Logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	if err := s.AddHook(new(auth.AllowHook), nil); err != nil {
+		t.Fatal(err)
+	}
+	tcp := listeners.NewTCP(listeners.Config{ID: "tcp", Address: "127.0.0.1:0"})
+	if err := s.AddListener(tcp); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Serve(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	b := &broker{Server: s, url: "tcp://" + tcp.Address(), topics: make(map[string]string)}
+	err := s.Subscribe("#", 1, func(cl *mochi.Client, sub packets.Subscription, pk packets.Packet) {
+		b.mu.Lock()
+		b.topics[pk.TopicName] = string(pk.Payload)
+		b.mu.Unlock()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func (b *broker) get(topic string) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	v, ok := b.topics[topic]
+	return v, ok
+}
+
+// waitFor waits until topic has a payload satisfying f, and returns it.
+func (b *broker) waitFor(t *testing.T, topic string, f func(payload string) bool) string {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		v, ok := b.get(topic)
+		if ok && f(v) {
+			return v
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting on %s; last payload %q", topic, v)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func equals(want string) func(string) bool {
+	return func(got string) bool { return got == want }
+}
+
+func newController(t *testing.T) *emulator.Controller {
+	ctx := context.Background()
+	c := emulator.New("Back Yard")
+	if _, err := c.GroupListAdd(ctx, &protocol.GroupListAddRequest{GroupNumber: 3, Name: "Patio"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.IlluminateGroup(ctx, &protocol.IlluminateGroupRequest{GroupNumber: 3, Intensity: 40}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ThemeListAdd(ctx, &protocol.ThemeListAddRequest{ThemeIndex: 2, Name: "Party"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ThemeSet(ctx, &protocol.ThemeSetRequest{ThemeIndex: 2, Groups: []protocol.ThemeGroup{{GroupNumber: 3, Intensity: 90}}}); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func intensity(t *testing.T, c protocol.Controller) uint8 {
+	resp, err := c.GroupListGet(context.Background(), &protocol.GroupListGetRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.GroupList[0].Intensity
+}
+
+func TestBridge(t *testing.T) {
+	b := newBroker(t)
+	c := newController(t)
+	bridge := &mqttbridge.Bridge{Controller: c, Broker: b.url, PollInterval: 20 * time.Millisecond}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- bridge.Run(ctx) }()
+
+	b.waitFor(t, "luxor/back_yard/availability", equals("online"))
+	config := b.waitFor(t, "homeassistant/light/back_yard/group_3/config", func(string) bool { return true })
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(config), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["name"] != "Patio" || decoded["unique_id"] != "luxor_back_yard_group_3" ||
+		decoded["command_topic"] != "luxor/back_yard/group/3/set" || decoded["brightness_scale"] != 100.0 {
+		t.Errorf("unexpected light config %s", config)
+	}
+	b.waitFor(t, "homeassistant/switch/back_yard/theme_2/config", func(string) bool { return true })
+	b.waitFor(t, "homeassistant/button/back_yard/all_off/config", func(string) bool { return true })
+	b.waitFor(t, "luxor/back_yard/group/3/state", equals(`{"state":"ON","brightness":40}`))
+	b.waitFor(t, "luxor/back_yard/theme/2/state", equals("OFF"))
+
+	// Commands.
+	if err := b.Publish("luxor/back_yard/group/3/set", []byte(`{"state":"ON","brightness":70}`), false, 1); err != nil {
+		t.Fatal(err)
+	}
+	b.waitFor(t, "luxor/back_yard/group/3/state", equals(`{"state":"ON","brightness":70}`))
+	if err := b.Publish("luxor/back_yard/group/3/set", []byte(`{"state":"OFF"}`), false, 1); err != nil {
+		t.Fatal(err)
+	}
+	b.waitFor(t, "luxor/back_yard/group/3/state", equals(`{"state":"OFF"}`))
+	if err := b.Publish("luxor/back_yard/group/3/set", []byte(`{"state":"ON"}`), false, 1); err != nil {
+		t.Fatal(err)
+	}
+	b.waitFor(t, "luxor/back_yard/group/3/state", equals(`{"state":"ON","brightness":70}`))
+	if err := b.Publish("luxor/back_yard/theme/2/set", []byte("ON"), false, 1); err != nil {
+		t.Fatal(err)
+	}
+	b.waitFor(t, "luxor/back_yard/theme/2/state", equals("ON"))
+	if got := intensity(t, c); got != 90 {
+		t.Errorf("expected theme intensity 90; got %d", got)
+	}
+	if err := b.Publish("luxor/back_yard/all/off/set", []byte("PRESS"), false, 1); err != nil {
+		t.Fatal(err)
+	}
+	b.waitFor(t, "luxor/back_yard/theme/2/state", equals("OFF"))
+	if got := intensity(t, c); got != 0 {
+		t.Errorf("expected intensity 0 after all off; got %d", got)
+	}
+
+	// Renames update the discovery config in place; deletions remove it.
+	if _, err := c.GroupListRename(ctx, &protocol.GroupListRenameRequest{OldName: "Patio", NewName: "Deck"}); err != nil {
+		t.Fatal(err)
+	}
+	b.waitFor(t, "homeassistant/light/back_yard/group_3/config", func(payload string) bool {
+		return json.Unmarshal([]byte(payload), &decoded) == nil && decoded["name"] == "Deck"
+	})
+	if _, err := c.ThemeListDelete(ctx, &protocol.ThemeListDeleteRequest{Name: "Party"}); err != nil {
+		t.Fatal(err)
+	}
+	b.waitFor(t, "homeassistant/switch/back_yard/theme_2/config", equals(""))
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("expected canceled; got %v", err)
+	}
+	b.waitFor(t, "luxor/back_yard/availability", equals("offline"))
+}
+
+// TestStaleRetained checks that discovery configs retained from an earlier
+// run, for entities deleted since, are removed.
+func TestStaleRetained(t *testing.T) {
+	b := newBroker(t)
+	const stale = "homeassistant/light/back_yard/group_9/config"
+	if err := b.Publish(stale, []byte(`{"name":"Shed"}`), true, 1); err != nil {
+		t.Fatal(err)
+	}
+	const other = "homeassistant/light/front_yard/group_9/config"
+	if err := b.Publish(other, []byte(`{"name":"Shed"}`), true, 1); err != nil {
+		t.Fatal(err)
+	}
+	bridge := &mqttbridge.Bridge{Controller: newController(t), Broker: b.url, PollInterval: 20 * time.Millisecond}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- bridge.Run(ctx) }()
+
+	b.waitFor(t, stale, equals(""))
+	b.waitFor(t, "homeassistant/light/back_yard/group_3/config", func(payload string) bool { return payload != "" })
+	cancel()
+	<-done
+	if payload, _ := b.get(other); payload == "" {
+		t.Error("removed another node's config")
+	}
+}
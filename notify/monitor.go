@@ -0,0 +1,265 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"github.com/scottlamb/luxor/protocol"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultPollInterval     = time.Minute
+	DefaultUnreachableAfter = 5 * time.Minute
+	DefaultFlashLeftOnAfter = 30 * time.Minute
+)
+
+// Monitor is a protocol.Controller which raises events about the controller
+// it wraps. Calls made through it are watched for theme failures and
+// FlashLights; Run polls GroupListGet for reachability and renames.
+type Monitor struct {
+	protocol.Controller
+	Notifier *Notifier
+
+	// Rules lists the rules to evaluate. If nil, all are.
+	Rules []string
+
+	// PollInterval defaults to DefaultPollInterval.
+	PollInterval time.Duration
+
+	// UnreachableAfter is how long the controller must fail before
+	// RuleUnreachable fires. Defaults to DefaultUnreachableAfter.
+	UnreachableAfter time.Duration
+
+	// FlashLeftOnAfter is how long FlashLights may stay on before
+	// RuleFlashLeftOn fires. Defaults to DefaultFlashLeftOnAfter.
+	FlashLeftOnAfter time.Duration
+
+	mu              sync.Mutex
+	name            string           // controller's name, once known.
+	failingSince    time.Time        // zero if the last call succeeded.
+	unreachableSent bool             // RuleUnreachable fired for this outage.
+	flashSince      time.Time        // zero if FlashLights is off.
+	flashSent       bool             // RuleFlashLeftOn fired for this flash.
+	names           map[uint8]string // group number -> name at last poll.
+}
+
+func (m *Monitor) enabled(rule string) bool {
+	if m.Rules == nil {
+		return true
+	}
+	for _, r := range m.Rules {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// notify sends e with the controller's name filled in. Suppression isn't
+// worth logging.
+func (m *Monitor) notify(ctx context.Context, e *Event) {
+	if !m.enabled(e.Rule) {
+		return
+	}
+	m.mu.Lock()
+	e.Controller = m.name
+	m.mu.Unlock()
+	if e.Controller == "" {
+		e.Controller = "controller"
+	}
+	e.Subject = e.Controller + ": " + e.Subject
+	if err := m.Notifier.Notify(ctx, e); err != nil && err != ErrSuppressed {
+		log.Printf("notify: %v", err)
+	}
+}
+
+// observe records the outcome of a call for the reachability rules. Only
+// transport failures count; a status error means the controller answered.
+func (m *Monitor) observe(ctx context.Context, err error) {
+	now := time.Now()
+	after := m.UnreachableAfter
+	if after <= 0 {
+		after = DefaultUnreachableAfter
+	}
+	m.mu.Lock()
+	var e *Event
+	if err == nil || protocol.StatusOf(err) > 0 {
+		if m.unreachableSent {
+			e = &Event{
+				Rule:    RuleRecovered,
+				Key:     RuleRecovered + "/" + strconv.FormatInt(m.failingSince.Unix(), 10),
+				Subject: "reachable again",
+				Message: fmt.Sprintf("The controller is responding again after failing since %s.", m.failingSince.Format(time.RFC1123)),
+			}
+		}
+		m.failingSince = time.Time{}
+		m.unreachableSent = false
+	} else {
+		if m.failingSince.IsZero() {
+			m.failingSince = now
+		}
+		if !m.unreachableSent && now.Sub(m.failingSince) >= after {
+			m.unreachableSent = true
+			e = &Event{
+				Rule:    RuleUnreachable,
+				Key:     RuleUnreachable + "/" + strconv.FormatInt(m.failingSince.Unix(), 10),
+				Subject: "unreachable",
+				Message: fmt.Sprintf("The controller has not responded since %s: %v", m.failingSince.Format(time.RFC1123), err),
+				Fields:  map[string]string{"error": err.Error()},
+			}
+		}
+	}
+	m.mu.Unlock()
+	if e != nil {
+		m.notify(ctx, e)
+	}
+}
+
+// Poll evaluates the polled rules once.
+func (m *Monitor) Poll(ctx context.Context) error {
+	m.mu.Lock()
+	needName := m.name == ""
+	m.mu.Unlock()
+	if needName {
+		if resp, err := m.Controller.ControllerName(ctx, &protocol.ControllerNameRequest{}); err == nil {
+			m.mu.Lock()
+			m.name = resp.Controller
+			m.mu.Unlock()
+		}
+	}
+	groups, err := m.Controller.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	m.observe(ctx, err)
+	if err == nil {
+		m.checkNames(ctx, groups.GroupList)
+	}
+	m.checkFlash(ctx)
+	return err
+}
+
+func (m *Monitor) checkNames(ctx context.Context, groups []protocol.Group) {
+	names := make(map[uint8]string, len(groups))
+	for _, g := range groups {
+		names[g.GroupNumber] = g.Name
+	}
+	m.mu.Lock()
+	old := m.names
+	m.names = names
+	m.mu.Unlock()
+	if old == nil {
+		return // first poll.
+	}
+	for _, g := range groups {
+		was, ok := old[g.GroupNumber]
+		if !ok || was == g.Name {
+			continue
+		}
+		m.notify(ctx, &Event{
+			Rule:    RuleGroupRenamed,
+			Key:     fmt.Sprintf("%s/%d/%s", RuleGroupRenamed, g.GroupNumber, g.Name),
+			Subject: fmt.Sprintf("group %d renamed to %q", g.GroupNumber, g.Name),
+			Message: fmt.Sprintf("Group %d was renamed from %q to %q.", g.GroupNumber, was, g.Name),
+			Fields: map[string]string{
+				"group_number": strconv.Itoa(int(g.GroupNumber)),
+				"old_name":     was,
+				"new_name":     g.Name,
+			},
+		})
+	}
+}
+
+func (m *Monitor) checkFlash(ctx context.Context) {
+	after := m.FlashLeftOnAfter
+	if after <= 0 {
+		after = DefaultFlashLeftOnAfter
+	}
+	m.mu.Lock()
+	since := m.flashSince
+	fire := !since.IsZero() && !m.flashSent && time.Since(since) >= after
+	if fire {
+		m.flashSent = true
+	}
+	m.mu.Unlock()
+	if fire {
+		m.notify(ctx, &Event{
+			Rule:    RuleFlashLeftOn,
+			Key:     RuleFlashLeftOn + "/" + strconv.FormatInt(since.Unix(), 10),
+			Subject: "FlashLights left on",
+			Message: fmt.Sprintf("FlashLights mode has been on since %s.", since.Format(time.RFC1123)),
+		})
+	}
+}
+
+// Run polls until ctx is done.
+func (m *Monitor) Run(ctx context.Context) error {
+	interval := m.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		m.Poll(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Monitor) IlluminateTheme(ctx context.Context, req *protocol.IlluminateThemeRequest) (*protocol.IlluminateThemeResponse, error) {
+	resp, err := m.Controller.IlluminateTheme(ctx, req)
+	m.observe(ctx, err)
+	if err != nil {
+		status := protocol.StatusOf(err)
+		letter := protocol.ThemeLetter(req.ThemeIndex)
+		m.notify(ctx, &Event{
+			Rule:    RuleThemeFailed,
+			Key:     fmt.Sprintf("%s/%d/%d/%d", RuleThemeFailed, req.ThemeIndex, req.OnOff, status),
+			Subject: fmt.Sprintf("theme %s failed", letter),
+			Message: fmt.Sprintf("IlluminateTheme %s (OnOff %d) failed: %v", letter, req.OnOff, err),
+			Fields: map[string]string{
+				"theme_index": strconv.Itoa(int(req.ThemeIndex)),
+				"on_off":      strconv.Itoa(int(req.OnOff)),
+				"status":      strconv.Itoa(status),
+				"error":       err.Error(),
+			},
+		})
+	}
+	return resp, err
+}
+
+func (m *Monitor) FlashLights(ctx context.Context, req *protocol.FlashLightsRequest) (*protocol.FlashLightsResponse, error) {
+	resp, err := m.Controller.FlashLights(ctx, req)
+	m.observe(ctx, err)
+	if err == nil {
+		m.mu.Lock()
+		if req.OnOff == 0 {
+			m.flashSince = time.Time{}
+			m.flashSent = false
+		} else if m.flashSince.IsZero() {
+			m.flashSince = time.Now()
+		}
+		m.mu.Unlock()
+	}
+	return resp, err
+}
+
+func (m *Monitor) ExtinguishAll(ctx context.Context, req *protocol.ExtinguishAllRequest) (*protocol.ExtinguishAllResponse, error) {
+	resp, err := m.Controller.ExtinguishAll(ctx, req)
+	m.observe(ctx, err)
+	if err == nil {
+		// ExtinguishAll also ends flash mode.
+		m.mu.Lock()
+		m.flashSince = time.Time{}
+		m.flashSent = false
+		m.mu.Unlock()
+	}
+	return resp, err
+}
+
+// Ensure *Monitor implements protocol.Controller.
+var _ protocol.Controller = (*Monitor)(nil)
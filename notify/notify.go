@@ -0,0 +1,152 @@
+// Package notify sends notifications about controller failures and notable
+// lighting events to pluggable sinks: HTTP webhooks, email, and commands.
+//
+// A Monitor wraps a protocol.Controller, watching both the calls made
+// through it and periodic polls, and raises Events when its rules match. A
+// Notifier delivers Events to its Sinks, suppressing duplicates and limiting
+// the overall rate so that one outage doesn't send hundreds of messages.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Rules, as in Event.Rule and Monitor.Rules.
+const (
+	RuleUnreachable  = "unreachable"   // the controller has failed for Monitor.UnreachableAfter.
+	RuleRecovered    = "recovered"     // the controller is reachable again after RuleUnreachable.
+	RuleThemeFailed  = "theme_failed"  // IlluminateTheme returned an error.
+	RuleFlashLeftOn  = "flash_left_on" // FlashLights has been on for Monitor.FlashLeftOnAfter.
+	RuleGroupRenamed = "group_renamed" // a group's name changed.
+)
+
+// PolledRules are the rules which Monitor.Run can evaluate on its own. The
+// others need calls made through the Monitor, as by a scheduler wrapping its
+// client in one.
+var PolledRules = []string{RuleUnreachable, RuleRecovered, RuleGroupRenamed}
+
+const (
+	DefaultDedupeWindow = time.Hour
+	DefaultRateLimit    = 10
+	DefaultRatePeriod   = time.Hour
+)
+
+// Event is a single notification.
+type Event struct {
+	Rule string
+
+	// Key identifies the event for deduplication; events with the same key
+	// within the Notifier's DedupeWindow are sent once.
+	Key string
+
+	Time       time.Time
+	Controller string
+	Subject    string
+	Message    string
+
+	// Fields holds rule-specific details, such as "status" for
+	// RuleThemeFailed.
+	Fields map[string]string `json:",omitempty"`
+
+	// Suppressed is the number of events dropped by rate limiting since the
+	// last one sent.
+	Suppressed int `json:",omitempty"`
+}
+
+// Sink delivers events somewhere.
+type Sink interface {
+	Send(ctx context.Context, e *Event) error
+}
+
+// Notifier delivers events to sinks.
+type Notifier struct {
+	Sinks []Sink
+
+	// DedupeWindow defaults to DefaultDedupeWindow.
+	DedupeWindow time.Duration
+
+	// At most RateLimit events are sent per RatePeriod; these default to
+	// DefaultRateLimit and DefaultRatePeriod.
+	RateLimit  int
+	RatePeriod time.Duration
+
+	mu         sync.Mutex
+	lastSent   map[string]time.Time // key -> time last sent.
+	recent     []time.Time          // send times within the rate period.
+	suppressed int
+}
+
+// ErrSuppressed is returned by Notify for events dropped as duplicates or by
+// rate limiting.
+var ErrSuppressed = errors.New("notification suppressed")
+
+// admit decides whether to send e, updating dedupe and rate-limit state.
+func (n *Notifier) admit(e *Event) bool {
+	window := n.DedupeWindow
+	if window <= 0 {
+		window = DefaultDedupeWindow
+	}
+	limit := n.RateLimit
+	if limit <= 0 {
+		limit = DefaultRateLimit
+	}
+	period := n.RatePeriod
+	if period <= 0 {
+		period = DefaultRatePeriod
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.lastSent == nil {
+		n.lastSent = make(map[string]time.Time)
+	}
+	for k, t := range n.lastSent {
+		if e.Time.Sub(t) >= window {
+			delete(n.lastSent, k)
+		}
+	}
+	if _, dup := n.lastSent[e.Key]; dup {
+		return false
+	}
+	i := 0
+	for i < len(n.recent) && e.Time.Sub(n.recent[i]) >= period {
+		i++
+	}
+	n.recent = n.recent[i:]
+	if len(n.recent) >= limit {
+		n.suppressed++
+		return false
+	}
+	n.recent = append(n.recent, e.Time)
+	n.lastSent[e.Key] = e.Time
+	e.Suppressed = n.suppressed
+	n.suppressed = 0
+	return true
+}
+
+// Notify sends e to every sink, unless it's a duplicate or over the rate
+// limit. Key defaults to Rule and Time to now. Every sink is tried; the
+// returned error joins their failures.
+func (n *Notifier) Notify(ctx context.Context, e *Event) error {
+	if e.Key == "" {
+		e.Key = e.Rule
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if !n.admit(e) {
+		return ErrSuppressed
+	}
+	var errs []error
+	for _, s := range n.Sinks {
+		if err := s.Send(ctx, e); err != nil {
+			log.Printf("notify: %T failed to send %s: %v", s, e.Key, err)
+			errs = append(errs, fmt.Errorf("%T: %w", s, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,273 @@
+package notify_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/scottlamb/luxor/emulator"
+	"github.com/scottlamb/luxor/notify"
+	"github.com/scottlamb/luxor/protocol"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingSink struct {
+	mu     sync.Mutex
+	events []notify.Event
+}
+
+func (s *recordingSink) Send(ctx context.Context, e *notify.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, *e)
+	return nil
+}
+
+func (s *recordingSink) rules() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var rules []string
+	for _, e := range s.events {
+		rules = append(rules, e.Rule)
+	}
+	return rules
+}
+
+func TestNotifier(t *testing.T) {
+	ctx := context.Background()
+	sink := &recordingSink{}
+	n := &notify.Notifier{Sinks: []notify.Sink{sink}, RateLimit: 2, RatePeriod: 50 * time.Millisecond}
+	if err := n.Notify(ctx, &notify.Event{Rule: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Notify(ctx, &notify.Event{Rule: "a"}); err != notify.ErrSuppressed {
+		t.Errorf("duplicate: got %v", err)
+	}
+	if err := n.Notify(ctx, &notify.Event{Rule: "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Notify(ctx, &notify.Event{Rule: "c"}); err != notify.ErrSuppressed {
+		t.Errorf("over rate limit: got %v", err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if err := n.Notify(ctx, &notify.Event{Rule: "d"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(sink.events) != 3 || sink.events[2].Rule != "d" || sink.events[2].Suppressed != 1 {
+		t.Errorf("unexpected events %+v", sink.events)
+	}
+
+	failing := &notify.WebhookSink{URL: "http://127.0.0.1:1/"}
+	n = &notify.Notifier{Sinks: []notify.Sink{failing, sink}}
+	if err := n.Notify(ctx, &notify.Event{Rule: "e"}); err == nil {
+		t.Error("expected failing sink's error")
+	}
+	if last := sink.events[len(sink.events)-1]; last.Rule != "e" {
+		t.Error("other sinks should still be tried")
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	bodies := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies <- string(b)
+	}))
+	defer server.Close()
+	tmpl, err := notify.ParseWebhookTemplate(`{"text": {{json .Subject}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &notify.WebhookSink{URL: server.URL, Template: tmpl}
+	if err := s.Send(context.Background(), &notify.Event{Subject: `group "Path" renamed`}); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-bodies; got != `{"text": "group \"Path\" renamed"}` {
+		t.Errorf("unexpected body %s", got)
+	}
+
+	bad, err := notify.ParseWebhookTemplate(`{"text": {{.Subject}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Template = bad
+	if err := s.Send(context.Background(), &notify.Event{Subject: "x"}); err == nil || !strings.Contains(err.Error(), "invalid JSON") {
+		t.Errorf("expected invalid JSON error; got %v", err)
+	}
+}
+
+func TestExecSink(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out")
+	s := &notify.ExecSink{Command: []string{"sh", "-c", `cat > "$1"; echo >> "$1"; echo "$LUXOR_RULE" >> "$1"`, "sh", out}}
+	if err := s.Send(context.Background(), &notify.Event{Rule: notify.RuleFlashLeftOn, Subject: "s"}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	var e notify.Event
+	if len(lines) != 2 || json.Unmarshal([]byte(lines[0]), &e) != nil || e.Subject != "s" || lines[1] != notify.RuleFlashLeftOn {
+		t.Errorf("unexpected output %q", b)
+	}
+	s.Command = []string{"false"}
+	if err := s.Send(context.Background(), &notify.Event{}); err == nil {
+		t.Error("expected failure from false")
+	}
+}
+
+// fakeSMTP accepts one message and returns its DATA.
+func fakeSMTP(t *testing.T) (string, <-chan string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	data := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		io.WriteString(conn, "220 fake\r\n")
+		var msg strings.Builder
+		inData := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					data <- msg.String()
+					io.WriteString(conn, "250 ok\r\n")
+				} else {
+					msg.WriteString(line)
+				}
+				continue
+			}
+			switch cmd := strings.ToUpper(strings.Fields(line)[0]); cmd {
+			case "DATA":
+				inData = true
+				io.WriteString(conn, "354 go ahead\r\n")
+			case "QUIT":
+				io.WriteString(conn, "221 bye\r\n")
+				return
+			default:
+				io.WriteString(conn, "250 ok\r\n")
+			}
+		}
+	}()
+	return ln.Addr().String(), data
+}
+
+func TestSMTPSink(t *testing.T) {
+	addr, data := fakeSMTP(t)
+	s := &notify.SMTPSink{Addr: addr, From: "luxor@example.com", To: []string{"me@example.com"}}
+	e := &notify.Event{
+		Subject: "yard: unreachable",
+		Message: "The controller has not responded.",
+		Time:    time.Now(),
+		Fields:  map[string]string{"error": "connection refused"},
+	}
+	if err := s.Send(context.Background(), e); err != nil {
+		t.Fatal(err)
+	}
+	msg := <-data
+	for _, want := range []string{"Subject: yard: unreachable\r\n", "To: me@example.com\r\n", "The controller has not responded.", "error: connection refused"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message lacks %q:\n%s", want, msg)
+		}
+	}
+}
+
+// flaky fails GroupListGet with a transport-level error while down is set.
+type flaky struct {
+	protocol.Controller
+	mu   sync.Mutex
+	down bool
+}
+
+func (f *flaky) setDown(down bool) {
+	f.mu.Lock()
+	f.down = down
+	f.mu.Unlock()
+}
+
+func (f *flaky) GroupListGet(ctx context.Context, req *protocol.GroupListGetRequest) (*protocol.GroupListGetResponse, error) {
+	f.mu.Lock()
+	down := f.down
+	f.mu.Unlock()
+	if down {
+		return nil, errors.New("connection refused")
+	}
+	return f.Controller.GroupListGet(ctx, req)
+}
+
+func TestMonitor(t *testing.T) {
+	ctx := context.Background()
+	e := emulator.New("yard")
+	if _, err := e.GroupListAdd(ctx, &protocol.GroupListAddRequest{GroupNumber: 1, Name: "Path"}); err != nil {
+		t.Fatal(err)
+	}
+	f := &flaky{Controller: e}
+	sink := &recordingSink{}
+	m := &notify.Monitor{
+		Controller:       f,
+		Notifier:         &notify.Notifier{Sinks: []notify.Sink{sink}},
+		UnreachableAfter: 10 * time.Millisecond,
+		FlashLeftOnAfter: 10 * time.Millisecond,
+	}
+	if err := m.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	f.setDown(true)
+	m.Poll(ctx)
+	time.Sleep(20 * time.Millisecond)
+	m.Poll(ctx)
+	m.Poll(ctx) // only one notification per outage.
+	f.setDown(false)
+	m.Poll(ctx)
+
+	if _, err := e.GroupListRename(ctx, &protocol.GroupListRenameRequest{OldName: "Path", NewName: "Walk"}); err != nil {
+		t.Fatal(err)
+	}
+	m.Poll(ctx)
+
+	if _, err := m.IlluminateTheme(ctx, &protocol.IlluminateThemeRequest{ThemeIndex: 4, OnOff: 1}); err == nil {
+		t.Error("expected failure illuminating missing theme")
+	}
+	m.IlluminateTheme(ctx, &protocol.IlluminateThemeRequest{ThemeIndex: 4, OnOff: 1}) // deduplicated.
+
+	if _, err := m.FlashLights(ctx, &protocol.FlashLightsRequest{OnOff: 1}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	m.Poll(ctx)
+
+	want := []string{notify.RuleUnreachable, notify.RuleRecovered, notify.RuleGroupRenamed, notify.RuleThemeFailed, notify.RuleFlashLeftOn}
+	if got := sink.rules(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("got rules %v; want %v", got, want)
+	}
+	themeFailed := sink.events[3]
+	if themeFailed.Controller != "yard" || themeFailed.Fields["status"] == "" || !strings.HasPrefix(themeFailed.Subject, "yard: theme E") {
+		t.Errorf("unexpected theme failure event %+v", themeFailed)
+	}
+	if renamed := sink.events[2]; renamed.Fields["old_name"] != "Path" || renamed.Fields["new_name"] != "Walk" {
+		t.Errorf("unexpected rename event %+v", renamed)
+	}
+}
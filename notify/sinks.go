@@ -0,0 +1,212 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultWebhookTemplate posts the Event itself as JSON.
+const DefaultWebhookTemplate = `{{json .}}`
+
+// TemplateFuncs are available to webhook templates. "json" encodes any value
+// as JSON, so strings are quoted and escaped properly:
+//
+//	{"text": {{json .Subject}}, "priority": {{if eq .Rule "unreachable"}}2{{else}}0{{end}}}
+var TemplateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// ParseWebhookTemplate parses a template for a WebhookSink.
+func ParseWebhookTemplate(text string) (*template.Template, error) {
+	return template.New("webhook").Funcs(TemplateFuncs).Parse(text)
+}
+
+// WebhookSink POSTs a JSON body, rendered from a template, to a URL.
+type WebhookSink struct {
+	URL string
+
+	// Template renders the body from the *Event. It must produce valid
+	// JSON. Defaults to DefaultWebhookTemplate.
+	Template *template.Template
+
+	// Client defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+var defaultWebhookTemplate = template.Must(ParseWebhookTemplate(DefaultWebhookTemplate))
+
+func (s *WebhookSink) Send(ctx context.Context, e *Event) error {
+	tmpl := s.Template
+	if tmpl == nil {
+		tmpl = defaultWebhookTemplate
+	}
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, e); err != nil {
+		return err
+	}
+	if !json.Valid(body.Bytes()) {
+		return fmt.Errorf("template produced invalid JSON: %q", body.Bytes())
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", s.URL, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %s: %q", resp.Status, msg)
+	}
+	return nil
+}
+
+// SMTPSink sends plain-text email.
+type SMTPSink struct {
+	// Addr is the server's "host:port".
+	Addr string
+
+	// Auth may be nil for servers which don't require authentication.
+	Auth smtp.Auth
+
+	From string
+	To   []string
+}
+
+// formatEmail returns the message for e, with headers.
+func (s *SMTPSink) formatEmail(e *Event) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", s.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(s.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", strings.NewReplacer("\r", " ", "\n", " ").Replace(e.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", e.Time.Format(time.RFC1123Z))
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(e.Message)
+	b.WriteString("\r\n")
+	if len(e.Fields) > 0 {
+		b.WriteString("\r\n")
+		for _, k := range sortedKeys(e.Fields) {
+			fmt.Fprintf(&b, "%s: %s\r\n", k, e.Fields[k])
+		}
+	}
+	if e.Suppressed > 0 {
+		fmt.Fprintf(&b, "\r\n(%d earlier notifications were suppressed by rate limiting.)\r\n", e.Suppressed)
+	}
+	return b.Bytes()
+}
+
+// Send delivers the message as smtp.SendMail does, but honoring ctx.
+func (s *SMTPSink) Send(ctx context.Context, e *Event) error {
+	host, _, err := net.SplitHostPort(s.Addr)
+	if err != nil {
+		return err
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if s.Auth != nil {
+		if err := c.Auth(s.Auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(s.From); err != nil {
+		return err
+	}
+	for _, to := range s.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(s.formatEmail(e)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// ExecSink runs a command for each event. The event is written to its
+// standard input as JSON, and its main fields are also in the environment
+// as LUXOR_RULE, LUXOR_KEY, LUXOR_CONTROLLER, LUXOR_SUBJECT, and
+// LUXOR_MESSAGE.
+type ExecSink struct {
+	// Command is the program and its arguments.
+	Command []string
+}
+
+func (s *ExecSink) Send(ctx context.Context, e *Event) error {
+	if len(s.Command) == 0 {
+		return fmt.Errorf("no command")
+	}
+	input, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(ctx, s.Command[0], s.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Env = append(os.Environ(),
+		"LUXOR_RULE="+e.Rule,
+		"LUXOR_KEY="+e.Key,
+		"LUXOR_CONTROLLER="+e.Controller,
+		"LUXOR_SUBJECT="+e.Subject,
+		"LUXOR_MESSAGE="+e.Message,
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %v; output: %q", s.Command, err, out)
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
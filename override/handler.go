@@ -0,0 +1,108 @@
+package override
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// The handler speaks the same JSON-over-HTTP style as the controller itself:
+// each method is a POST to /Method.json.
+
+type OverrideSetRequest struct {
+	GroupNumber uint8
+	Intensity   uint8
+	Seconds     int
+	Restore     Restore
+}
+
+type OverrideSetResponse struct {
+	Override Override
+}
+
+type OverrideListRequest struct {
+}
+
+type OverrideListResponse struct {
+	Overrides []Override
+}
+
+type OverrideCancelRequest struct {
+	GroupNumber uint8
+
+	// Restore, if true, restores the group as if the override had expired.
+	Restore bool
+}
+
+type OverrideCancelResponse struct {
+}
+
+// Handler returns an http.Handler which serves OverrideSet, OverrideList and
+// OverrideCancel.
+func (m *Manager) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/OverrideSet.json", func(w http.ResponseWriter, r *http.Request) {
+		var req OverrideSetRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		if req.Seconds <= 0 {
+			http.Error(w, "Seconds must be positive", http.StatusBadRequest)
+			return
+		}
+		o, err := m.Set(r.Context(), req.GroupNumber, req.Intensity, time.Duration(req.Seconds)*time.Second, req.Restore)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		encode(w, &OverrideSetResponse{Override: *o})
+	})
+	mux.HandleFunc("/OverrideList.json", func(w http.ResponseWriter, r *http.Request) {
+		var req OverrideListRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		encode(w, &OverrideListResponse{Overrides: m.List()})
+	})
+	mux.HandleFunc("/OverrideCancel.json", func(w http.ResponseWriter, r *http.Request) {
+		var req OverrideCancelRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		if err := m.Cancel(r.Context(), req.GroupNumber, req.Restore); err != nil {
+			writeError(w, err)
+			return
+		}
+		encode(w, &OverrideCancelResponse{})
+	})
+	return mux
+}
+
+func decode(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	if r.Method != "POST" {
+		http.Error(w, "POST required", http.StatusMethodNotAllowed)
+		return false
+	}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		http.Error(w, "bad request: "+err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func encode(w http.ResponseWriter, resp interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrBadIntensity), errors.Is(err, ErrBadRestore), errors.Is(err, ErrNoSchedule):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrNoSuchGroup), errors.Is(err, ErrNoOverride):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusBadGateway)
+	}
+}
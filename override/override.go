@@ -0,0 +1,355 @@
+// Package override implements time-limited group overrides: "set group X to
+// N% for 30 minutes, then put it back".
+//
+// A Manager sets the override through IlluminateGroup and remembers the
+// group's previous intensity. When the override expires, the Manager restores
+// either that previous intensity or the intensity a schedule dictates. If
+// anyone else changes the group's intensity in the meantime (as seen through
+// periodic GroupListGet polls), the override is abandoned rather than
+// clobbering their change. Overrides are optionally persisted to a file so
+// they survive restarts.
+package override
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/scottlamb/luxor/protocol"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultPollInterval is the PollInterval used when none is specified.
+const DefaultPollInterval = 30 * time.Second
+
+// Restore selects the intensity a group returns to when its override expires.
+type Restore int
+
+const (
+	// RestorePrevious returns the group to its intensity before the override.
+	RestorePrevious Restore = iota
+
+	// RestoreSchedule returns the group to the intensity given by the
+	// Manager's Schedule, or the previous intensity if the schedule has no
+	// opinion. It's only accepted by a Manager with a Schedule.
+	RestoreSchedule
+)
+
+func (r Restore) String() string {
+	switch r {
+	case RestorePrevious:
+		return "previous"
+	case RestoreSchedule:
+		return "schedule"
+	}
+	return fmt.Sprintf("Restore(%d)", int(r))
+}
+
+// Override is a single group's time-limited intensity.
+type Override struct {
+	GroupNumber uint8
+	Intensity   uint8
+
+	// Previous is the group's intensity before the override was set. When
+	// an existing override is replaced, the original value is kept.
+	Previous uint8
+
+	Restore Restore
+	Expires time.Time
+}
+
+// ScheduleFunc returns the intensity a schedule dictates for the given group
+// at the given time. ok is false if the schedule does not cover the group.
+type ScheduleFunc func(groupNumber uint8, t time.Time) (intensity uint8, ok bool)
+
+var (
+	ErrNoSuchGroup  = errors.New("no such group")
+	ErrNoOverride   = errors.New("no override for group")
+	ErrBadIntensity = fmt.Errorf("intensity must be in [0, %d]", protocol.MaxIntensity)
+	ErrBadRestore   = errors.New("unknown restore mode")
+	ErrNoSchedule   = errors.New("no schedule to restore to")
+)
+
+// Manager tracks overrides on a single controller. Run must be called for
+// overrides to expire.
+type Manager struct {
+	// Schedule is consulted for overrides with RestoreSchedule. May be nil,
+	// in which case Set rejects RestoreSchedule.
+	Schedule ScheduleFunc
+
+	// PollInterval is how often to check for external changes to
+	// overridden groups. If zero, DefaultPollInterval is used.
+	PollInterval time.Duration
+
+	controller protocol.Controller
+	path       string
+	wake       chan struct{}
+
+	// mu is held for the duration of each operation, including its RPCs,
+	// so that a poll never races with a Set or Cancel of the same group.
+	mu        sync.Mutex
+	overrides map[uint8]Override
+}
+
+type state struct {
+	Overrides []Override
+}
+
+// NewManager returns a manager for the given controller. If path is
+// non-empty, overrides are persisted there and any previously persisted
+// overrides are loaded; ones which expired while the process was down are
+// restored on Run's first pass.
+func NewManager(controller protocol.Controller, path string) (*Manager, error) {
+	m := &Manager{
+		controller: controller,
+		path:       path,
+		wake:       make(chan struct{}, 1),
+		overrides:  make(map[uint8]Override),
+	}
+	if path == "" {
+		return m, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var s state
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("JSON error: %v while parsing %q", err, path)
+	}
+	for _, o := range s.Overrides {
+		m.overrides[o.GroupNumber] = o
+	}
+	return m, nil
+}
+
+// save persists the current overrides. Caller must hold m.mu.
+func (m *Manager) save() error {
+	if m.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(state{Overrides: m.list()}, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(m.path), filepath.Base(m.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), m.path)
+}
+
+// list returns the overrides ordered by expiration. Caller must hold m.mu.
+func (m *Manager) list() []Override {
+	l := make([]Override, 0, len(m.overrides))
+	for _, o := range m.overrides {
+		l = append(l, o)
+	}
+	sort.Slice(l, func(i, j int) bool {
+		if !l[i].Expires.Equal(l[j].Expires) {
+			return l[i].Expires.Before(l[j].Expires)
+		}
+		return l[i].GroupNumber < l[j].GroupNumber
+	})
+	return l
+}
+
+func (m *Manager) poke() {
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
+// List returns all active overrides, soonest-expiring first.
+func (m *Manager) List() []Override {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.list()
+}
+
+// Set illuminates the given group at intensity for duration d, after which
+// it will be restored as specified. It replaces any existing override for
+// the group. RestoreSchedule fails with ErrNoSchedule if m has no Schedule.
+func (m *Manager) Set(ctx context.Context, groupNumber, intensity uint8, d time.Duration, restore Restore) (*Override, error) {
+	if intensity > protocol.MaxIntensity {
+		return nil, ErrBadIntensity
+	}
+	switch {
+	case restore != RestorePrevious && restore != RestoreSchedule:
+		return nil, ErrBadRestore
+	case restore == RestoreSchedule && m.Schedule == nil:
+		return nil, ErrNoSchedule
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	o, ok := m.overrides[groupNumber]
+	if !ok {
+		groups, err := m.controller.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for _, g := range groups.GroupList {
+			if g.GroupNumber == groupNumber {
+				o.Previous = g.Intensity
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, ErrNoSuchGroup
+		}
+	}
+	o.GroupNumber = groupNumber
+	o.Intensity = intensity
+	o.Restore = restore
+	o.Expires = time.Now().Add(d)
+	if _, err := m.controller.IlluminateGroup(ctx, &protocol.IlluminateGroupRequest{GroupNumber: groupNumber, Intensity: intensity}); err != nil {
+		return nil, err
+	}
+	m.overrides[groupNumber] = o
+	if err := m.save(); err != nil {
+		return nil, err
+	}
+	m.poke()
+	return &o, nil
+}
+
+// Cancel removes the override for the given group. If restore is true, the
+// group is restored as if the override had expired; otherwise it is left at
+// its current intensity.
+func (m *Manager) Cancel(ctx context.Context, groupNumber uint8, restore bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	o, ok := m.overrides[groupNumber]
+	if !ok {
+		return ErrNoOverride
+	}
+	if restore {
+		if err := m.restore(ctx, o, time.Now()); err != nil {
+			return err
+		}
+	}
+	delete(m.overrides, groupNumber)
+	if err := m.save(); err != nil {
+		return err
+	}
+	m.poke()
+	return nil
+}
+
+// restore sets o's group back to its post-override intensity.
+func (m *Manager) restore(ctx context.Context, o Override, now time.Time) error {
+	intensity := o.Previous
+	if o.Restore == RestoreSchedule && m.Schedule != nil {
+		if scheduled, ok := m.Schedule(o.GroupNumber, now); ok {
+			intensity = scheduled
+		}
+	}
+	_, err := m.controller.IlluminateGroup(ctx, &protocol.IlluminateGroupRequest{GroupNumber: o.GroupNumber, Intensity: intensity})
+	return err
+}
+
+// check drops overrides whose groups were changed externally and restores
+// expired ones. It returns the time of the next expiration, or the zero time
+// if there are no overrides left.
+func (m *Manager) check(ctx context.Context) (time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.overrides) == 0 {
+		return time.Time{}, nil
+	}
+	groups, err := m.controller.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	if err != nil {
+		return m.next(), err
+	}
+	current := make(map[uint8]uint8, len(groups.GroupList))
+	for _, g := range groups.GroupList {
+		current[g.GroupNumber] = g.Intensity
+	}
+	now := time.Now()
+	changed := false
+	var restoreErr error
+	for n, o := range m.overrides {
+		if intensity, ok := current[n]; !ok {
+			log.Printf("override: group %d was deleted; dropping its override", n)
+		} else if intensity != o.Intensity {
+			log.Printf("override: group %d changed externally from %d to %d; dropping its override",
+				n, o.Intensity, intensity)
+		} else if !now.Before(o.Expires) {
+			if err := m.restore(ctx, o, now); err != nil {
+				// Leave it in place to retry on the next pass.
+				restoreErr = err
+				continue
+			}
+		} else {
+			continue
+		}
+		delete(m.overrides, n)
+		changed = true
+	}
+	if changed {
+		if err := m.save(); err != nil {
+			return m.next(), err
+		}
+	}
+	return m.next(), restoreErr
+}
+
+// next returns the earliest expiration. Caller must hold m.mu.
+func (m *Manager) next() time.Time {
+	var next time.Time
+	for _, o := range m.overrides {
+		if next.IsZero() || o.Expires.Before(next) {
+			next = o.Expires
+		}
+	}
+	return next
+}
+
+// Run expires overrides and watches for external changes until ctx is done.
+// Errors talking to the controller are logged and retried.
+func (m *Manager) Run(ctx context.Context) error {
+	interval := m.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	for {
+		next, err := m.check(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("override: %v", err)
+		}
+		wait := interval
+		if !next.IsZero() {
+			if untilNext := time.Until(next); untilNext < wait {
+				wait = untilNext
+			}
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-m.wake:
+			t.Stop()
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package override_test
+
+import (
+	"context"
+	"github.com/scottlamb/luxor/emulator"
+	"github.com/scottlamb/luxor/override"
+	"github.com/scottlamb/luxor/protocol"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newController(t *testing.T) *emulator.Controller {
+	ctx := context.Background()
+	c := emulator.New("test")
+	for _, g := range []protocol.GroupListAddRequest{{GroupNumber: 1, Name: "Patio"}, {GroupNumber: 2, Name: "Path"}} {
+		if _, err := c.GroupListAdd(ctx, &g); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := c.IlluminateGroup(ctx, &protocol.IlluminateGroupRequest{GroupNumber: 1, Intensity: 20}); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func intensity(t *testing.T, c protocol.Controller, groupNumber uint8) uint8 {
+	resp, err := c.GroupListGet(context.Background(), &protocol.GroupListGetRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, g := range resp.GroupList {
+		if g.GroupNumber == groupNumber {
+			return g.Intensity
+		}
+	}
+	t.Fatalf("no group %d", groupNumber)
+	return 0
+}
+
+// waitFor polls until f returns true or a generous deadline passes.
+func waitFor(t *testing.T, what string, f func() bool) {
+	deadline := time.Now().Add(10 * time.Second)
+	for !f() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestExpireRestoresPrevious(t *testing.T) {
+	c := newController(t)
+	m, err := override.NewManager(c, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.Run(ctx)
+	if _, err := m.Set(ctx, 1, 100, 50*time.Millisecond, override.RestorePrevious); err != nil {
+		t.Fatal(err)
+	}
+	if got := intensity(t, c, 1); got != 100 {
+		t.Errorf("expected override intensity 100; got %d", got)
+	}
+	waitFor(t, "expiration", func() bool { return len(m.List()) == 0 })
+	if got := intensity(t, c, 1); got != 20 {
+		t.Errorf("expected restored intensity 20; got %d", got)
+	}
+}
+
+func TestExpireRestoresSchedule(t *testing.T) {
+	c := newController(t)
+	m, err := override.NewManager(c, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Schedule = func(groupNumber uint8, t time.Time) (uint8, bool) { return 60, groupNumber == 1 }
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.Run(ctx)
+	if _, err := m.Set(ctx, 1, 100, 10*time.Millisecond, override.RestoreSchedule); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "expiration", func() bool { return len(m.List()) == 0 })
+	if got := intensity(t, c, 1); got != 60 {
+		t.Errorf("expected scheduled intensity 60; got %d", got)
+	}
+}
+
+func TestReplaceKeepsPrevious(t *testing.T) {
+	c := newController(t)
+	m, err := override.NewManager(c, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err := m.Set(ctx, 1, 100, time.Hour, override.RestorePrevious); err != nil {
+		t.Fatal(err)
+	}
+	o, err := m.Set(ctx, 1, 50, time.Hour, override.RestorePrevious)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Previous != 20 || o.Intensity != 50 {
+		t.Errorf("unexpected override %+v", o)
+	}
+	if err := m.Cancel(ctx, 1, true); err != nil {
+		t.Fatal(err)
+	}
+	if got := intensity(t, c, 1); got != 20 {
+		t.Errorf("expected restored intensity 20; got %d", got)
+	}
+	if err := m.Cancel(ctx, 1, true); err != override.ErrNoOverride {
+		t.Errorf("expected ErrNoOverride; got %v", err)
+	}
+}
+
+func TestExternalChangeCancels(t *testing.T) {
+	c := newController(t)
+	m, err := override.NewManager(c, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.PollInterval = 10 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err := m.Set(ctx, 1, 100, time.Hour, override.RestorePrevious); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.IlluminateGroup(ctx, &protocol.IlluminateGroupRequest{GroupNumber: 1, Intensity: 5}); err != nil {
+		t.Fatal(err)
+	}
+	go m.Run(ctx)
+	waitFor(t, "cancellation", func() bool { return len(m.List()) == 0 })
+	if got := intensity(t, c, 1); got != 5 {
+		t.Errorf("external change should be left alone; got %d", got)
+	}
+}
+
+func TestNoSuchGroup(t *testing.T) {
+	m, err := override.NewManager(newController(t), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Set(context.Background(), 9, 100, time.Hour, override.RestorePrevious); err != override.ErrNoSuchGroup {
+		t.Errorf("expected ErrNoSuchGroup; got %v", err)
+	}
+}
+
+func TestPersistence(t *testing.T) {
+	c := newController(t)
+	path := filepath.Join(t.TempDir(), "overrides.json")
+	m, err := override.NewManager(c, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err := m.Set(ctx, 1, 100, 10*time.Millisecond, override.RestorePrevious); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new manager picks up the override and restores it once expired.
+	m2, err := override.NewManager(c, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := m2.List()
+	if len(l) != 1 || l[0].GroupNumber != 1 || l[0].Previous != 20 {
+		t.Fatalf("unexpected loaded overrides %+v", l)
+	}
+	time.Sleep(10 * time.Millisecond)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go m2.Run(ctx)
+	waitFor(t, "expiration", func() bool { return len(m2.List()) == 0 })
+	if got := intensity(t, c, 1); got != 20 {
+		t.Errorf("expected restored intensity 20; got %d", got)
+	}
+	m3, err := override.NewManager(c, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l := m3.List(); len(l) != 0 {
+		t.Errorf("expected no persisted overrides; got %+v", l)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	c := newController(t)
+	m, err := override.NewManager(c, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(m.Handler())
+	defer server.Close()
+	post := func(method, body string) *http.Response {
+		resp, err := http.Post(server.URL+"/"+method+".json", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+	if resp := post("OverrideSet", `{"GroupNumber":2,"Intensity":70,"Seconds":60}`); resp.StatusCode != http.StatusOK {
+		t.Errorf("OverrideSet: %v", resp.Status)
+	}
+	if got := intensity(t, c, 2); got != 70 {
+		t.Errorf("expected intensity 70; got %d", got)
+	}
+	if resp := post("OverrideSet", `{"GroupNumber":2,"Intensity":170,"Seconds":60}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("OverrideSet with bad intensity: %v", resp.Status)
+	}
+	for _, restore := range []string{"1", "2", "-1"} {
+		// 1 is RestoreSchedule, which needs a Schedule; the others are unknown.
+		if resp := post("OverrideSet", `{"GroupNumber":2,"Intensity":40,"Seconds":60,"Restore":`+restore+`}`); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("OverrideSet with Restore %s: %v", restore, resp.Status)
+		}
+	}
+	if got := m.List(); len(got) != 1 || got[0].Intensity != 70 || got[0].Restore != override.RestorePrevious {
+		t.Errorf("overrides after rejected sets: %+v", got)
+	}
+	if resp := post("OverrideCancel", `{"GroupNumber":2,"Restore":true}`); resp.StatusCode != http.StatusOK {
+		t.Errorf("OverrideCancel: %v", resp.Status)
+	}
+	if got := intensity(t, c, 2); got != 0 {
+		t.Errorf("expected intensity 0; got %d", got)
+	}
+	if resp := post("OverrideCancel", `{"GroupNumber":2}`); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("second OverrideCancel: %v", resp.Status)
+	}
+}
@@ -0,0 +1,58 @@
+package protocol
+
+import "context"
+
+// ColorController is a Controller which may support the ZDC's color methods.
+// Implementations talking to a ZD controller return errors with
+// StatusUnknownMethod from them.
+type ColorController interface {
+	Controller
+
+	// ColorListGet retrieves the color palette.
+	ColorListGet(ctx context.Context, request *ColorListGetRequest) (response *ColorListGetResponse, err error)
+
+	// ColorListSet redefines one palette color.
+	ColorListSet(ctx context.Context, request *ColorListSetRequest) (response *ColorListSetResponse, err error)
+}
+
+// Group types, as in Group.Type.
+const (
+	GroupTypeMonochrome = 0
+	GroupTypeColor      = 1
+)
+
+const (
+	// MaxHue is the largest hue, in degrees.
+	MaxHue = 359
+
+	// MaxSaturation is the largest saturation, in percent.
+	MaxSaturation = 100
+)
+
+// Color is a palette entry.
+type Color struct {
+	ColorNumber uint8
+	Hue         uint16
+	Saturation  uint8
+}
+
+type ColorListGetRequest struct {
+}
+
+type ColorListGetResponse struct {
+	Status    int
+	ColorList []Color
+}
+
+type ColorListSetRequest struct {
+	// ColorNumber should be non-zero; color 0 can't be redefined.
+	ColorNumber uint8
+	Hue         uint16
+	Saturation  uint8
+}
+
+type ColorListSetResponse struct {
+	// Status will be StatusInvalidRequest if the color number is 0 or
+	// the hue or saturation is out of range.
+	Status int
+}
@@ -43,6 +43,20 @@
 // ExtinguishAll) request. Because it is possible to change the theme's
 // group's intensities in other ways, the lights do not always match the
 // theme's state.
+//
+// Colors: the Luxor ZDC controller also drives RGBW fixtures. It keeps a
+// palette of colors, each a (hue, saturation) pair identified by a uint8
+// color number from 1 up; 0 means no color.
+//
+// Each group is monochrome or color (GroupTypeMonochrome or GroupTypeColor),
+// chosen when the group is added. Color groups have a current color number
+// alongside their intensity, set through IlluminateGroupRequest.Color, and
+// theme entries may carry a color number to apply with their intensity.
+// Colors are ignored for monochrome groups.
+//
+// The ZD controller has no palette and answers ColorListGet and ColorListSet
+// with StatusUnknownMethod and reports every group as monochrome. See
+// ColorController.
 package protocol
 
 import (
@@ -157,16 +171,39 @@ var statusName = map[int]string{
 	StatusThemeIndexOutOfRange: "theme index out of range",
 }
 
+// StatusError is the error returned for a response with a non-zero Status.
+type StatusError struct {
+	Status int
+}
+
+func (e *StatusError) Error() string {
+	if statusStr, ok := statusName[e.Status]; ok {
+		return statusStr
+	}
+	return fmt.Sprintf("unknown status %d", e.Status)
+}
+
 // ErrorForStatus returns an error for the given status.
-// If status == 0, the error will be nil.
+// If status == 0, the error will be nil; otherwise it will be a *StatusError.
 func ErrorForStatus(status int) error {
 	if status == 0 {
 		return nil
 	}
-	if statusStr, ok := statusName[status]; ok {
-		return errors.New(statusStr)
+	return &StatusError{Status: status}
+}
+
+// StatusOf returns the status carried by err: StatusOk if err is nil, the
+// status of a wrapped *StatusError, or -1 if err is some other error, such as
+// a transport failure.
+func StatusOf(err error) int {
+	if err == nil {
+		return StatusOk
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Status
 	}
-	return fmt.Errorf("unknown status %d", status)
+	return -1
 }
 
 type AssignLightRequest struct {
@@ -205,6 +242,12 @@ type Group struct {
 	GroupNumber uint8
 	Intensity   uint8
 	Name        string
+
+	// Type is GroupTypeColor for a ZDC color group.
+	Type uint8 `json:",omitempty"`
+
+	// Color is a color group's current color number.
+	Color uint8 `json:",omitempty"`
 }
 
 type GroupListAddRequest struct {
@@ -212,6 +255,9 @@ type GroupListAddRequest struct {
 
 	// Name will be truncated to MaxNameLength.
 	Name string
+
+	// Type may be GroupTypeColor on a ZDC controller.
+	Type uint8 `json:",omitempty"`
 }
 
 type GroupListAddResponse struct {
@@ -280,6 +326,10 @@ type IlluminateAllResponse struct {
 type IlluminateGroupRequest struct {
 	GroupNumber uint8
 	Intensity   uint8
+
+	// Color, if non-zero, sets a color group's color number. Zero leaves
+	// it unchanged.
+	Color uint8 `json:",omitempty"`
 }
 
 type IlluminateGroupResponse struct {
@@ -304,9 +354,31 @@ type Theme struct {
 	OnOff      uint8
 }
 
+// ThemeLetter returns the letter for a theme index, as shown in the
+// controller and app UI.
+func ThemeLetter(index uint8) string {
+	return string(rune('A' + int(index)))
+}
+
+// ParseThemeLetter parses a theme letter ("A" through "Z", case-insensitive)
+// into a theme index.
+func ParseThemeLetter(letter string) (uint8, error) {
+	if len(letter) == 1 {
+		c := letter[0] &^ ('a' - 'A') // upper-case.
+		if c >= 'A' && c <= 'A'+MaxThemeNumber {
+			return c - 'A', nil
+		}
+	}
+	return 0, fmt.Errorf("bad theme letter %q", letter)
+}
+
 type ThemeGroup struct {
 	GroupNumber uint8
 	Intensity   uint8
+
+	// Color, if non-zero, is the color number to set a color group to
+	// along with its intensity.
+	Color uint8 `json:",omitempty"`
 }
 
 type ThemeClearRequest struct {
@@ -0,0 +1,69 @@
+package protocol
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Kinds of ThemeIssue.
+const (
+	IssueDuplicateGroup = "duplicate_group" // listed again later; the last entry wins.
+	IssueIntensity      = "intensity"       // above MaxIntensity.
+	IssueUnknownGroup   = "unknown_group"   // not in the group list.
+)
+
+// ThemeIssue is a problem with one entry of a theme definition.
+type ThemeIssue struct {
+	Kind string
+
+	// Entry is the entry's position in the definition.
+	Entry int
+
+	GroupNumber uint8
+	Intensity   uint8
+	Message     string
+}
+
+// LintTheme checks a theme definition against the group list and returns
+// its canonical form along with any issues found. The canonical form has one
+// entry per group (the last, as the controller resolves duplicates), with
+// intensities clamped to MaxIntensity, without unknown groups, and in the
+// group list's (UI) order. Writing the canonical form through ThemeSet has
+// the same effect as writing the original, except that entries for unknown
+// groups no longer apply should such groups be added later.
+func LintTheme(entries []ThemeGroup, groups []Group) (canonical []ThemeGroup, issues []ThemeIssue) {
+	order := make(map[uint8]int, len(groups))
+	for i, g := range groups {
+		order[g.GroupNumber] = i
+	}
+	last := make(map[uint8]int, len(entries))
+	for i, e := range entries {
+		last[e.GroupNumber] = i
+	}
+	for i, e := range entries {
+		issue := ThemeIssue{Entry: i, GroupNumber: e.GroupNumber, Intensity: e.Intensity}
+		if _, ok := order[e.GroupNumber]; !ok {
+			issue.Kind = IssueUnknownGroup
+			issue.Message = fmt.Sprintf("entry %d refers to group %d, which doesn't exist", i, e.GroupNumber)
+			issues = append(issues, issue)
+			continue
+		}
+		if last[e.GroupNumber] != i {
+			issue.Kind = IssueDuplicateGroup
+			issue.Message = fmt.Sprintf("entry %d lists group %d, which entry %d overrides", i, e.GroupNumber, last[e.GroupNumber])
+			issues = append(issues, issue)
+			continue
+		}
+		if e.Intensity > MaxIntensity {
+			issue.Kind = IssueIntensity
+			issue.Message = fmt.Sprintf("entry %d sets group %d to %d%%, above %d%%", i, e.GroupNumber, e.Intensity, MaxIntensity)
+			issues = append(issues, issue)
+			e.Intensity = MaxIntensity
+		}
+		canonical = append(canonical, e)
+	}
+	sort.SliceStable(canonical, func(i, j int) bool {
+		return order[canonical[i].GroupNumber] < order[canonical[j].GroupNumber]
+	})
+	return canonical, issues
+}
@@ -0,0 +1,45 @@
+package protocol_test
+
+import (
+	"github.com/scottlamb/luxor/protocol"
+	"reflect"
+	"testing"
+)
+
+func TestLintTheme(t *testing.T) {
+	groups := []protocol.Group{{GroupNumber: 3}, {GroupNumber: 1}, {GroupNumber: 2}}
+	entries := []protocol.ThemeGroup{
+		{GroupNumber: 1, Intensity: 10},
+		{GroupNumber: 2, Intensity: 250},
+		{GroupNumber: 9, Intensity: 50},
+		{GroupNumber: 1, Intensity: 20},
+		{GroupNumber: 3, Intensity: 100},
+	}
+	canonical, issues := protocol.LintTheme(entries, groups)
+	wantCanonical := []protocol.ThemeGroup{
+		{GroupNumber: 3, Intensity: 100},
+		{GroupNumber: 1, Intensity: 20},
+		{GroupNumber: 2, Intensity: 100},
+	}
+	if !reflect.DeepEqual(canonical, wantCanonical) {
+		t.Errorf("canonical = %+v; want %+v", canonical, wantCanonical)
+	}
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.Kind+": "+issue.Message)
+	}
+	want := []string{
+		"duplicate_group: entry 0 lists group 1, which entry 3 overrides",
+		"intensity: entry 1 sets group 2 to 250%, above 100%",
+		"unknown_group: entry 2 refers to group 9, which doesn't exist",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("issues = %q; want %q", got, want)
+	}
+
+	// A canonical definition is clean and unchanged.
+	again, issues := protocol.LintTheme(canonical, groups)
+	if len(issues) != 0 || !reflect.DeepEqual(again, canonical) {
+		t.Errorf("second pass: %+v, %+v", again, issues)
+	}
+}
@@ -0,0 +1,542 @@
+// Package replicate copies groups and themes from a source controller to
+// replicas, as for identical rental units whose themes are edited on one
+// controller and pushed to the others.
+//
+// Plan compares the source's State with a target's and returns the Changes
+// which make the target match: group names and order, and theme names,
+// definitions, and order. Lights and intensities aren't replicated, only
+// configuration. Where a replica's wiring differs, a Target's GroupMap
+// translates source group numbers to the replica's. A Syncer plans and
+// applies changes for several targets, once or continuously.
+package replicate
+
+import (
+	"context"
+	"fmt"
+	"github.com/scottlamb/luxor/protocol"
+	"log"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Theme is a theme with its definition.
+type Theme struct {
+	protocol.Theme
+	Groups []protocol.ThemeGroup
+}
+
+// State is a controller's replicated configuration.
+type State struct {
+	// Groups are in the controller's (UI) order.
+	Groups []protocol.Group
+
+	// Themes are in the controller's order. Should several share an
+	// index, only the first is kept, as the others can't be used.
+	Themes []Theme
+}
+
+// Read reads a controller's State.
+func Read(ctx context.Context, c protocol.Controller) (*State, error) {
+	groups, err := c.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	if err != nil {
+		return nil, err
+	}
+	themes, err := c.ThemeListGet(ctx, &protocol.ThemeListGetRequest{})
+	if err != nil {
+		return nil, err
+	}
+	s := &State{Groups: groups.GroupList}
+	seen := make(map[uint8]bool)
+	for _, t := range themes.ThemeList {
+		if seen[t.ThemeIndex] {
+			continue
+		}
+		seen[t.ThemeIndex] = true
+		resp, err := c.ThemeGet(ctx, &protocol.ThemeGetRequest{ThemeIndex: t.ThemeIndex})
+		if err != nil {
+			return nil, fmt.Errorf("theme %s: %w", protocol.ThemeLetter(t.ThemeIndex), err)
+		}
+		s.Themes = append(s.Themes, Theme{Theme: t, Groups: resp.Groups})
+	}
+	return s, nil
+}
+
+// Kinds of Change.
+const (
+	KindDeleteGroup   = "delete_group"
+	KindRenameGroup   = "rename_group"
+	KindAddGroup      = "add_group"
+	KindReorderGroups = "reorder_groups"
+	KindDeleteTheme   = "delete_theme"
+	KindRenameTheme   = "rename_theme"
+	KindAddTheme      = "add_theme"
+	KindSetTheme      = "set_theme"
+	KindReorderThemes = "reorder_themes"
+)
+
+// Change is one step toward making a target match the source.
+type Change struct {
+	Kind        string
+	Description string
+
+	// Method and Request are the call which makes the change.
+	Method  string
+	Request interface{}
+}
+
+func (c *Change) String() string { return c.Description }
+
+// Apply makes the change on controller.
+func (c *Change) Apply(ctx context.Context, controller protocol.Controller) error {
+	var err error
+	switch req := c.Request.(type) {
+	case *protocol.GroupListDeleteRequest:
+		_, err = controller.GroupListDelete(ctx, req)
+	case *protocol.GroupListRenameRequest:
+		_, err = controller.GroupListRename(ctx, req)
+	case *protocol.GroupListAddRequest:
+		_, err = controller.GroupListAdd(ctx, req)
+	case *protocol.GroupListReorderRequest:
+		_, err = controller.GroupListReorder(ctx, req)
+	case *protocol.ThemeListDeleteRequest:
+		_, err = controller.ThemeListDelete(ctx, req)
+	case *protocol.ThemeListRenameRequest:
+		_, err = controller.ThemeListRename(ctx, req)
+	case *protocol.ThemeListAddRequest:
+		_, err = controller.ThemeListAdd(ctx, req)
+	case *protocol.ThemeSetRequest:
+		_, err = controller.ThemeSet(ctx, req)
+	case *protocol.ThemeListReorderRequest:
+		_, err = controller.ThemeListReorder(ctx, req)
+	default:
+		return fmt.Errorf("%s: unsupported request %T", c.Description, c.Request)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", c.Description, err)
+	}
+	return nil
+}
+
+// Apply makes changes on controller in order, stopping at the first failure.
+func Apply(ctx context.Context, controller protocol.Controller, changes []Change) error {
+	for i := range changes {
+		if err := changes[i].Apply(ctx, controller); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Options control Plan.
+type Options struct {
+	// GroupMap maps source group numbers to the target's. Groups not
+	// listed keep their numbers.
+	GroupMap map[uint8]uint8
+
+	// Prune deletes the target's groups and themes which have no
+	// counterpart in the source. Otherwise they're left alone, after the
+	// replicated ones, and renamed only if their names are needed.
+	Prune bool
+}
+
+// mapGroup maps a source group number to the target's.
+func (o *Options) mapGroup(n uint8) uint8 {
+	if m, ok := o.GroupMap[n]; ok {
+		return m
+	}
+	return n
+}
+
+// Plan returns the changes which make target match source.
+func Plan(source, target *State, opts Options) ([]Change, error) {
+	images := make(map[uint8]uint8, len(source.Groups))
+	for _, g := range source.Groups {
+		m := opts.mapGroup(g.GroupNumber)
+		for s, other := range images {
+			if other == m {
+				return nil, fmt.Errorf("groups %d and %d both map to target group %d", s, g.GroupNumber, m)
+			}
+		}
+		images[g.GroupNumber] = m
+	}
+	p := &planner{}
+	p.groups(source, target, opts, images)
+	p.themes(source, target, opts, images)
+	return p.changes, nil
+}
+
+type planner struct {
+	changes []Change
+}
+
+func (p *planner) add(kind, method string, req interface{}, format string, args ...interface{}) {
+	p.changes = append(p.changes, Change{Kind: kind, Description: fmt.Sprintf(format, args...), Method: method, Request: req})
+}
+
+// item is a group or theme, for renaming and reordering.
+type item struct {
+	id      uint8 // group number or theme index.
+	current string
+	final   string
+	exists  bool
+	extra   bool // no counterpart in the source.
+}
+
+// finalNames assigns names to extra items which collide with replicated
+// ones, so that every final name is unique.
+func finalNames(items []*item, label func(uint8) string) {
+	taken := make(map[string]bool)
+	for _, it := range items {
+		if !it.extra {
+			taken[it.final] = true
+		}
+	}
+	for _, it := range items {
+		if !it.extra {
+			continue
+		}
+		it.final = it.current
+		if taken[it.final] {
+			it.final = truncate(it.current + " " + label(it.id))
+			for n := 2; taken[it.final]; n++ {
+				it.final = truncate(it.current + " " + label(it.id) + "." + strconv.Itoa(n))
+			}
+		}
+		taken[it.final] = true
+	}
+}
+
+func truncate(name string) string {
+	if len(name) > protocol.MaxNameLength {
+		return name[len(name)-protocol.MaxNameLength:]
+	}
+	return name
+}
+
+// renames plans the renames which take existing items from their current
+// names to their final ones. Should a final name be held by another item, as
+// when two names are swapped, renaming items pass through temporary names.
+func renames(items []*item) (first, second []*item, temp map[*item]string) {
+	held := make(map[string]*item)
+	for _, it := range items {
+		if it.exists {
+			held[it.current] = it
+		}
+	}
+	var renaming []*item
+	blocked := false
+	for _, it := range items {
+		if it.exists && it.current != it.final {
+			renaming = append(renaming, it)
+			if h := held[it.final]; h != nil && h != it {
+				blocked = true
+			}
+		}
+	}
+	if !blocked {
+		return renaming, nil, nil
+	}
+	temp = make(map[*item]string)
+	for _, it := range renaming {
+		name := "~" + strconv.Itoa(int(it.id))
+		for held[name] != nil {
+			name += "~"
+		}
+		temp[it] = name
+	}
+	return renaming, renaming, temp
+}
+
+func (p *planner) groups(source, target *State, opts Options, images map[uint8]uint8) {
+	byNumber := make(map[uint8]protocol.Group, len(target.Groups))
+	for _, g := range target.Groups {
+		byNumber[g.GroupNumber] = g
+	}
+	replicated := make(map[uint8]bool, len(images))
+	for _, m := range images {
+		replicated[m] = true
+	}
+
+	var items []*item
+	types := make(map[uint8]uint8, len(source.Groups)) // by target number.
+	for _, g := range source.Groups {
+		m := images[g.GroupNumber]
+		types[m] = g.Type
+		tg, exists := byNumber[m]
+		items = append(items, &item{id: m, current: tg.Name, final: g.Name, exists: exists})
+	}
+	var extras []*item
+	for _, g := range target.Groups {
+		if replicated[g.GroupNumber] {
+			continue
+		}
+		if opts.Prune {
+			p.add(KindDeleteGroup, "GroupListDelete", &protocol.GroupListDeleteRequest{Name: g.Name},
+				"delete group %d %q", g.GroupNumber, g.Name)
+			continue
+		}
+		extras = append(extras, &item{id: g.GroupNumber, current: g.Name, exists: true, extra: true})
+	}
+	items = append(items, extras...)
+	finalNames(items, func(n uint8) string { return "#" + strconv.Itoa(int(n)) })
+
+	first, second, temp := renames(items)
+	for _, it := range first {
+		to := it.final
+		if temp != nil {
+			to = temp[it]
+		}
+		p.add(KindRenameGroup, "GroupListRename", &protocol.GroupListRenameRequest{OldName: it.current, NewName: to},
+			"rename group %d from %q to %q", it.id, it.current, to)
+	}
+	for _, it := range second {
+		p.add(KindRenameGroup, "GroupListRename", &protocol.GroupListRenameRequest{OldName: temp[it], NewName: it.final},
+			"rename group %d from %q to %q", it.id, temp[it], it.final)
+	}
+
+	// After adds, the target's order is its current one (less deletions)
+	// with the added groups appended.
+	var order []uint8
+	for _, g := range target.Groups {
+		if replicated[g.GroupNumber] || !opts.Prune {
+			order = append(order, g.GroupNumber)
+		}
+	}
+	for _, it := range items {
+		if !it.exists {
+			p.add(KindAddGroup, "GroupListAdd", &protocol.GroupListAddRequest{GroupNumber: it.id, Name: it.final, Type: types[it.id]},
+				"add group %d %q", it.id, it.final)
+			order = append(order, it.id)
+		}
+	}
+	want := make([]uint8, len(items))
+	for i, it := range items {
+		want[i] = it.id
+	}
+	if !reflect.DeepEqual(order, want) {
+		p.add(KindReorderGroups, "GroupListReorder", &protocol.GroupListReorderRequest{GroupNumbers: want},
+			"reorder groups to %v", want)
+	}
+}
+
+func (p *planner) themes(source, target *State, opts Options, images map[uint8]uint8) {
+	byIndex := make(map[uint8]*Theme, len(target.Themes))
+	for i := range target.Themes {
+		byIndex[target.Themes[i].ThemeIndex] = &target.Themes[i]
+	}
+	inSource := make(map[uint8]bool, len(source.Themes))
+	for _, t := range source.Themes {
+		inSource[t.ThemeIndex] = true
+	}
+
+	var items []*item
+	for _, t := range source.Themes {
+		it := &item{id: t.ThemeIndex, final: t.Name}
+		if tt := byIndex[t.ThemeIndex]; tt != nil {
+			it.current, it.exists = tt.Name, true
+		}
+		items = append(items, it)
+	}
+	var extras []*item
+	for _, t := range target.Themes {
+		if inSource[t.ThemeIndex] {
+			continue
+		}
+		if opts.Prune {
+			p.add(KindDeleteTheme, "ThemeListDelete", &protocol.ThemeListDeleteRequest{Name: t.Name},
+				"delete theme %s %q", protocol.ThemeLetter(t.ThemeIndex), t.Name)
+			continue
+		}
+		extras = append(extras, &item{id: t.ThemeIndex, current: t.Name, exists: true, extra: true})
+	}
+	items = append(items, extras...)
+	finalNames(items, protocol.ThemeLetter)
+
+	first, second, temp := renames(items)
+	for _, it := range first {
+		to := it.final
+		if temp != nil {
+			to = temp[it]
+		}
+		p.add(KindRenameTheme, "ThemeListRename", &protocol.ThemeListRenameRequest{OldName: it.current, NewName: to},
+			"rename theme %s from %q to %q", protocol.ThemeLetter(it.id), it.current, to)
+	}
+	for _, it := range second {
+		p.add(KindRenameTheme, "ThemeListRename", &protocol.ThemeListRenameRequest{OldName: temp[it], NewName: it.final},
+			"rename theme %s from %q to %q", protocol.ThemeLetter(it.id), temp[it], it.final)
+	}
+
+	// Theme definitions refer to groups by target number, in the
+	// target's group order.
+	var targetGroups []protocol.Group
+	for _, g := range source.Groups {
+		targetGroups = append(targetGroups, protocol.Group{GroupNumber: images[g.GroupNumber]})
+	}
+	var order []uint8
+	for _, t := range target.Themes {
+		if inSource[t.ThemeIndex] || !opts.Prune {
+			order = append(order, t.ThemeIndex)
+		}
+	}
+	for _, t := range source.Themes {
+		canonical, _ := protocol.LintTheme(t.Groups, source.Groups)
+		mapped := make([]protocol.ThemeGroup, len(canonical))
+		for i, g := range canonical {
+			mapped[i] = g
+			mapped[i].GroupNumber = images[g.GroupNumber]
+		}
+		letter := protocol.ThemeLetter(t.ThemeIndex)
+		tt := byIndex[t.ThemeIndex]
+		if tt == nil {
+			p.add(KindAddTheme, "ThemeListAdd", &protocol.ThemeListAddRequest{ThemeIndex: t.ThemeIndex, Name: t.Name},
+				"add theme %s %q", letter, t.Name)
+			order = append(order, t.ThemeIndex)
+		}
+		if tt == nil || !sameDefinition(mapped, tt.Groups) {
+			p.add(KindSetTheme, "ThemeSet", &protocol.ThemeSetRequest{ThemeIndex: t.ThemeIndex, Groups: mapped},
+				"set theme %s %q to %s", letter, t.Name, describe(mapped))
+		}
+	}
+	want := make([]uint8, len(items))
+	for i, it := range items {
+		want[i] = it.id
+	}
+	if !reflect.DeepEqual(order, want) {
+		letters := make([]string, len(want))
+		for i, index := range want {
+			letters[i] = protocol.ThemeLetter(index)
+		}
+		p.add(KindReorderThemes, "ThemeListReorder", &protocol.ThemeListReorderRequest{ThemeIndexes: want},
+			"reorder themes to %v", letters)
+	}
+}
+
+// sameDefinition reports whether two theme definitions have the same effect,
+// regardless of the order of their entries.
+func sameDefinition(a, b []protocol.ThemeGroup) bool {
+	effect := func(entries []protocol.ThemeGroup) map[uint8]protocol.ThemeGroup {
+		m := make(map[uint8]protocol.ThemeGroup, len(entries))
+		for _, e := range entries {
+			m[e.GroupNumber] = e // the last entry wins.
+		}
+		return m
+	}
+	return reflect.DeepEqual(effect(a), effect(b))
+}
+
+func describe(entries []protocol.ThemeGroup) string {
+	if len(entries) == 0 {
+		return "no groups"
+	}
+	s := ""
+	for i, e := range entries {
+		if i > 0 {
+			s += ", "
+		}
+		s += fmt.Sprintf("%d=%d%%", e.GroupNumber, e.Intensity)
+		if e.Color != 0 {
+			s += fmt.Sprintf(" color %d", e.Color)
+		}
+	}
+	return s
+}
+
+// Target is a controller to keep in step with the source.
+type Target struct {
+	Name       string
+	Controller protocol.Controller
+	Options    Options
+}
+
+// Result is the outcome of syncing one target.
+type Result struct {
+	Target  string
+	Changes []Change
+
+	// Err is why the target couldn't be read or changed. Changes before
+	// the failing one were applied.
+	Err error
+}
+
+// DefaultInterval is Syncer.Interval's default.
+const DefaultInterval = time.Minute
+
+// Syncer keeps targets in step with a source.
+type Syncer struct {
+	Source  protocol.Controller
+	Targets []Target
+
+	// DryRun plans changes without applying them.
+	DryRun bool
+
+	// Interval is how often Run checks the source; it defaults to
+	// DefaultInterval.
+	Interval time.Duration
+
+	// Report, if non-nil, is called with each target's result from Run.
+	// Otherwise, changes and failures are logged.
+	Report func(Result)
+}
+
+// Sync makes each target match the source once. It returns an error only if
+// the source can't be read; each target's outcome is in its Result.
+func (s *Syncer) Sync(ctx context.Context) ([]Result, error) {
+	source, err := Read(ctx, s.Source)
+	if err != nil {
+		return nil, fmt.Errorf("source: %w", err)
+	}
+	results := make([]Result, len(s.Targets))
+	for i, t := range s.Targets {
+		results[i] = s.syncTarget(ctx, source, t)
+	}
+	return results, nil
+}
+
+func (s *Syncer) syncTarget(ctx context.Context, source *State, t Target) Result {
+	r := Result{Target: t.Name}
+	target, err := Read(ctx, t.Controller)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	if r.Changes, r.Err = Plan(source, target, t.Options); r.Err != nil || s.DryRun {
+		return r
+	}
+	r.Err = Apply(ctx, t.Controller, r.Changes)
+	return r
+}
+
+// Run syncs every Interval until ctx is done, propagating changes to the
+// source (and undoing changes made directly to targets).
+func (s *Syncer) Run(ctx context.Context) error {
+	interval := s.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		results, err := s.Sync(ctx)
+		if err != nil {
+			log.Printf("replicate: %v", err)
+		}
+		for _, r := range results {
+			if s.Report != nil {
+				s.Report(r)
+				continue
+			}
+			for _, c := range r.Changes {
+				log.Printf("replicate: %s: %s", r.Target, c.Description)
+			}
+			if r.Err != nil {
+				log.Printf("replicate: %s: %v", r.Target, r.Err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package replicate_test
+
+import (
+	"context"
+	"github.com/scottlamb/luxor/emulator"
+	"github.com/scottlamb/luxor/protocol"
+	"github.com/scottlamb/luxor/replicate"
+	"reflect"
+	"testing"
+)
+
+type group struct {
+	number uint8
+	name   string
+}
+
+type theme struct {
+	index  uint8
+	name   string
+	groups []protocol.ThemeGroup
+}
+
+func newEmulator(t *testing.T, groups []group, themes []theme) *emulator.Controller {
+	t.Helper()
+	ctx := context.Background()
+	e := emulator.New("unit")
+	for _, g := range groups {
+		if _, err := e.GroupListAdd(ctx, &protocol.GroupListAddRequest{GroupNumber: g.number, Name: g.name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, th := range themes {
+		if _, err := e.ThemeListAdd(ctx, &protocol.ThemeListAddRequest{ThemeIndex: th.index, Name: th.name}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := e.ThemeSet(ctx, &protocol.ThemeSetRequest{ThemeIndex: th.index, Groups: th.groups}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return e
+}
+
+func read(t *testing.T, c protocol.Controller) *replicate.State {
+	t.Helper()
+	s, err := replicate.Read(context.Background(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func kinds(changes []replicate.Change) []string {
+	var out []string
+	for _, c := range changes {
+		out = append(out, c.Kind)
+	}
+	return out
+}
+
+func TestSync(t *testing.T) {
+	ctx := context.Background()
+	source := newEmulator(t,
+		[]group{{1, "Path"}, {2, "Patio"}, {3, "Trees"}},
+		[]theme{
+			{2, "Evening", []protocol.ThemeGroup{{GroupNumber: 1, Intensity: 50}, {GroupNumber: 2, Intensity: 80}}},
+			{0, "Party", []protocol.ThemeGroup{{GroupNumber: 3, Intensity: 100}}},
+		})
+
+	// The replica's groups 1 and 2 are swapped by name, its path is wired
+	// as group 4, and it has an extra group and theme of its own.
+	replica := newEmulator(t,
+		[]group{{2, "Path"}, {3, "Patio"}, {4, "Trees"}, {9, "Shed"}},
+		[]theme{
+			{2, "Evening", []protocol.ThemeGroup{{GroupNumber: 4, Intensity: 50}}},
+			{5, "Party", nil},
+		})
+	opts := replicate.Options{GroupMap: map[uint8]uint8{1: 4}}
+	s := &replicate.Syncer{Source: source, Targets: []replicate.Target{{Name: "replica", Controller: replica, Options: opts}}}
+
+	s.DryRun = true
+	results, err := s.Sync(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Err != nil || len(results[0].Changes) == 0 {
+		t.Fatalf("dry run results = %+v", results)
+	}
+	if after := read(t, replica); after.Groups[0].Name != "Path" {
+		t.Errorf("dry run changed the replica: %+v", after)
+	}
+
+	s.DryRun = false
+	if results, err = s.Sync(ctx); err != nil || results[0].Err != nil {
+		t.Fatalf("Sync = %+v, %v", results, err)
+	}
+	got := read(t, replica)
+	var groups []group
+	for _, g := range got.Groups {
+		groups = append(groups, group{g.GroupNumber, g.Name})
+	}
+	if want := []group{{4, "Path"}, {2, "Patio"}, {3, "Trees"}, {9, "Shed"}}; !reflect.DeepEqual(groups, want) {
+		t.Errorf("replica groups = %+v; want %+v", groups, want)
+	}
+	var themes []string
+	for _, th := range got.Themes {
+		themes = append(themes, th.Name)
+	}
+	if want := []string{"Evening", "Party", "Party F"}; !reflect.DeepEqual(themes, want) {
+		t.Errorf("replica themes = %q; want %q", themes, want)
+	}
+	if want := []protocol.ThemeGroup{{GroupNumber: 4, Intensity: 50}, {GroupNumber: 2, Intensity: 80}}; !reflect.DeepEqual(got.Themes[0].Groups, want) {
+		t.Errorf("replica Evening = %+v; want %+v", got.Themes[0].Groups, want)
+	}
+
+	// A second pass has nothing to do.
+	changes, err := replicate.Plan(read(t, source), got, opts)
+	if err != nil || len(changes) != 0 {
+		t.Errorf("second Plan = %v, %v", changes, err)
+	}
+
+	// Pruning removes the extras.
+	opts.Prune = true
+	changes, err = replicate.Plan(read(t, source), got, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{replicate.KindDeleteGroup, replicate.KindDeleteTheme}; !reflect.DeepEqual(kinds(changes), want) {
+		t.Errorf("prune kinds = %q; want %q", kinds(changes), want)
+	}
+	if err := replicate.Apply(ctx, replica, changes); err != nil {
+		t.Fatal(err)
+	}
+	if got := read(t, replica); len(got.Groups) != 3 || len(got.Themes) != 2 {
+		t.Errorf("after prune: %+v", got)
+	}
+}
+
+func TestPlanBadMap(t *testing.T) {
+	source := &replicate.State{Groups: []protocol.Group{{GroupNumber: 1, Name: "Path"}, {GroupNumber: 2, Name: "Patio"}}}
+	if _, err := replicate.Plan(source, &replicate.State{}, replicate.Options{GroupMap: map[uint8]uint8{1: 2}}); err == nil {
+		t.Error("Plan with two groups mapped to one: no error")
+	}
+}
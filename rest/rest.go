@@ -0,0 +1,530 @@
+// Package rest is a resource-oriented HTTP gateway in front of any
+// protocol.Controller:
+//
+//	GET  /groups                  list groups in UI order
+//	GET  /groups/{group}          one group, by number or name
+//	PUT  /groups/{group}          set its intensity and/or name
+//	GET  /themes                  list themes
+//	GET  /themes/{letter}         one theme, including its definition
+//	PUT  /themes/{letter}         set its name and/or definition
+//	POST /themes/{letter}/on      illuminate it
+//	POST /themes/{letter}/off     extinguish it
+//	POST /all/off                 extinguish everything
+//	GET  /openapi.json            OpenAPI 3 description of the above
+//
+// Request and response bodies are JSON with the same field naming as the
+// protocol package. Application-level statuses are translated to HTTP
+// statuses by HTTPStatus.
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/scottlamb/luxor/client"
+	"github.com/scottlamb/luxor/protocol"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type Group struct {
+	GroupNumber uint8
+	Name        string
+	Intensity   uint8
+
+	// Order is the group's zero-based position in the app's UI.
+	Order int
+}
+
+type GroupUpdate struct {
+	// Intensity, if present, is applied through IlluminateGroup.
+	Intensity *uint8
+
+	// Name, if present, is applied through GroupListRename.
+	Name *string
+}
+
+type Theme struct {
+	ThemeIndex uint8
+	Letter     string
+	Name       string
+	On         bool
+}
+
+type ThemeDetail struct {
+	Theme
+	Groups []protocol.ThemeGroup
+}
+
+type ThemeUpdate struct {
+	// Name, if present, is applied through ThemeListRename.
+	Name *string
+
+	// Groups, if present, is applied through ThemeSet in the canonical form
+	// of protocol.LintTheme, as luxorctl's "theme edit" does: duplicates
+	// resolved, intensities clamped, and unknown groups dropped.
+	Groups *[]protocol.ThemeGroup
+}
+
+type Error struct {
+	Error string
+
+	// Status is the controller's application-level status, if any.
+	Status int `json:",omitempty"`
+}
+
+// errNotFound is returned for groups and themes absent from the lists.
+var errNotFound = errors.New("not found")
+
+// badRequest is an error in the client's request, detected before
+// contacting the controller.
+type badRequest struct {
+	msg string
+}
+
+func (e *badRequest) Error() string { return e.msg }
+
+// HTTPStatus maps an error from a protocol.Controller method to the HTTP
+// status the gateway reports.
+func HTTPStatus(err error) int {
+	var bad *badRequest
+	switch {
+	case err == nil:
+		return http.StatusOK
+	case errors.As(err, &bad):
+		return http.StatusBadRequest
+	case errors.Is(err, errNotFound):
+		return http.StatusNotFound
+	}
+	switch protocol.StatusOf(err) {
+	case protocol.StatusGroupNameInUse, protocol.StatusGroupNumberInUse:
+		return http.StatusConflict
+	case protocol.StatusPreconditionFailed, protocol.StatusThemeIndexOutOfRange:
+		return http.StatusNotFound
+	case protocol.StatusInvalidRequest:
+		// Most commonly, themes are restricted in the setup menu.
+		return http.StatusForbidden
+	case protocol.StatusUnparseableRequest:
+		return http.StatusBadRequest
+	case protocol.StatusUnknownMethod:
+		return http.StatusNotImplemented
+	case -1:
+		if client.ErrorClass(err) == client.ClassTimeout {
+			return http.StatusGatewayTimeout
+		}
+	}
+	return http.StatusBadGateway
+}
+
+// Gateway is an http.Handler serving the REST API.
+type Gateway struct {
+	controller protocol.Controller
+	mux        *http.ServeMux
+}
+
+// New returns a gateway for the given controller.
+func New(controller protocol.Controller) *Gateway {
+	g := &Gateway{controller: controller, mux: http.NewServeMux()}
+	for _, r := range routes {
+		r := r
+		g.mux.HandleFunc(r.method+" "+r.path, func(w http.ResponseWriter, req *http.Request) {
+			var body interface{}
+			if r.request != nil {
+				body = reflect.New(r.request).Interface()
+				dec := json.NewDecoder(req.Body)
+				dec.DisallowUnknownFields()
+				if err := dec.Decode(body); err != nil {
+					writeError(w, &badRequest{"bad request body: " + err.Error()})
+					return
+				}
+			}
+			resp, err := r.handle(g, req.Context(), req, body)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			if resp == nil {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			writeJSON(w, http.StatusOK, resp)
+		})
+	}
+	g.mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, req *http.Request) {
+		writeJSON(w, http.StatusOK, OpenAPI())
+	})
+	return g
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(w, r)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	e := &Error{Error: err.Error()}
+	if status := protocol.StatusOf(err); status > 0 {
+		e.Status = status
+	}
+	writeJSON(w, HTTPStatus(err), e)
+}
+
+func toTheme(t protocol.Theme) Theme {
+	return Theme{ThemeIndex: t.ThemeIndex, Letter: protocol.ThemeLetter(t.ThemeIndex), Name: t.Name, On: t.OnOff != 0}
+}
+
+// findGroup finds a group by number or, failing that, by name.
+func (g *Gateway) findGroup(ctx context.Context, id string) (*Group, error) {
+	resp, err := g.controller.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	if err != nil {
+		return nil, err
+	}
+	if n, err := strconv.ParseUint(id, 10, 8); err == nil {
+		for i, grp := range resp.GroupList {
+			if grp.GroupNumber == uint8(n) {
+				return &Group{GroupNumber: grp.GroupNumber, Name: grp.Name, Intensity: grp.Intensity, Order: i}, nil
+			}
+		}
+	}
+	for i, grp := range resp.GroupList {
+		if grp.Name == id {
+			return &Group{GroupNumber: grp.GroupNumber, Name: grp.Name, Intensity: grp.Intensity, Order: i}, nil
+		}
+	}
+	return nil, fmt.Errorf("group %q: %w", id, errNotFound)
+}
+
+// findTheme finds the (first) theme with the given letter.
+func (g *Gateway) findTheme(ctx context.Context, letter string) (*Theme, error) {
+	index, err := protocol.ParseThemeLetter(letter)
+	if err != nil {
+		return nil, &badRequest{err.Error()}
+	}
+	resp, err := g.controller.ThemeListGet(ctx, &protocol.ThemeListGetRequest{})
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range resp.ThemeList {
+		if t.ThemeIndex == index {
+			theme := toTheme(t)
+			return &theme, nil
+		}
+	}
+	return nil, fmt.Errorf("theme %s: %w", protocol.ThemeLetter(index), errNotFound)
+}
+
+func (g *Gateway) listGroups(ctx context.Context, r *http.Request, _ interface{}) (interface{}, error) {
+	resp, err := g.controller.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]Group, len(resp.GroupList))
+	for i, grp := range resp.GroupList {
+		groups[i] = Group{GroupNumber: grp.GroupNumber, Name: grp.Name, Intensity: grp.Intensity, Order: i}
+	}
+	return groups, nil
+}
+
+func (g *Gateway) getGroup(ctx context.Context, r *http.Request, _ interface{}) (interface{}, error) {
+	return g.findGroup(ctx, r.PathValue("group"))
+}
+
+func (g *Gateway) putGroup(ctx context.Context, r *http.Request, body interface{}) (interface{}, error) {
+	update := body.(*GroupUpdate)
+	if update.Intensity != nil && *update.Intensity > protocol.MaxIntensity {
+		return nil, &badRequest{fmt.Sprintf("Intensity must be in [0, %d]", protocol.MaxIntensity)}
+	}
+	if update.Name != nil && (*update.Name == "" || len(*update.Name) > protocol.MaxNameLength) {
+		return nil, &badRequest{fmt.Sprintf("Name must be 1 to %d bytes", protocol.MaxNameLength)}
+	}
+	grp, err := g.findGroup(ctx, r.PathValue("group"))
+	if err != nil {
+		return nil, err
+	}
+	if update.Name != nil && *update.Name != grp.Name {
+		if _, err := g.controller.GroupListRename(ctx, &protocol.GroupListRenameRequest{OldName: grp.Name, NewName: *update.Name}); err != nil {
+			return nil, err
+		}
+		grp.Name = *update.Name
+	}
+	if update.Intensity != nil {
+		if _, err := g.controller.IlluminateGroup(ctx, &protocol.IlluminateGroupRequest{GroupNumber: grp.GroupNumber, Intensity: *update.Intensity}); err != nil {
+			return nil, err
+		}
+		grp.Intensity = *update.Intensity
+	}
+	return grp, nil
+}
+
+func (g *Gateway) listThemes(ctx context.Context, r *http.Request, _ interface{}) (interface{}, error) {
+	resp, err := g.controller.ThemeListGet(ctx, &protocol.ThemeListGetRequest{})
+	if err != nil {
+		return nil, err
+	}
+	themes := make([]Theme, len(resp.ThemeList))
+	for i, t := range resp.ThemeList {
+		themes[i] = toTheme(t)
+	}
+	return themes, nil
+}
+
+func (g *Gateway) getTheme(ctx context.Context, r *http.Request, _ interface{}) (interface{}, error) {
+	t, err := g.findTheme(ctx, r.PathValue("letter"))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := g.controller.ThemeGet(ctx, &protocol.ThemeGetRequest{ThemeIndex: t.ThemeIndex})
+	if err != nil {
+		return nil, err
+	}
+	groups := resp.Groups
+	if groups == nil {
+		groups = []protocol.ThemeGroup{}
+	}
+	return &ThemeDetail{Theme: *t, Groups: groups}, nil
+}
+
+func (g *Gateway) putTheme(ctx context.Context, r *http.Request, body interface{}) (interface{}, error) {
+	update := body.(*ThemeUpdate)
+	if update.Name != nil && (*update.Name == "" || len(*update.Name) > protocol.MaxNameLength) {
+		return nil, &badRequest{fmt.Sprintf("Name must be 1 to %d bytes", protocol.MaxNameLength)}
+	}
+	t, err := g.findTheme(ctx, r.PathValue("letter"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Everything which can be checked is checked before the first write.
+	var canonical []protocol.ThemeGroup
+	if update.Groups != nil {
+		groups, err := g.controller.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+		if err != nil {
+			return nil, err
+		}
+		canonical, _ = protocol.LintTheme(*update.Groups, groups.GroupList)
+		if canonical == nil {
+			canonical = []protocol.ThemeGroup{}
+		}
+	}
+	if update.Name != nil && *update.Name != t.Name {
+		if _, err := g.controller.ThemeListRename(ctx, &protocol.ThemeListRenameRequest{OldName: t.Name, NewName: *update.Name}); err != nil {
+			return nil, err
+		}
+	}
+	if canonical != nil {
+		if _, err := g.controller.ThemeSet(ctx, &protocol.ThemeSetRequest{ThemeIndex: t.ThemeIndex, Groups: canonical}); err != nil {
+			return nil, err
+		}
+	}
+	return g.getTheme(ctx, r, nil)
+}
+
+func illuminateTheme(onOff uint8) func(*Gateway, context.Context, *http.Request, interface{}) (interface{}, error) {
+	return func(g *Gateway, ctx context.Context, r *http.Request, _ interface{}) (interface{}, error) {
+		t, err := g.findTheme(ctx, r.PathValue("letter"))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := g.controller.IlluminateTheme(ctx, &protocol.IlluminateThemeRequest{ThemeIndex: t.ThemeIndex, OnOff: onOff}); err != nil {
+			return nil, err
+		}
+		t.On = onOff != 0
+		return t, nil
+	}
+}
+
+func (g *Gateway) allOff(ctx context.Context, r *http.Request, _ interface{}) (interface{}, error) {
+	_, err := g.controller.ExtinguishAll(ctx, &protocol.ExtinguishAllRequest{})
+	return nil, err
+}
+
+// route describes one endpoint, for both dispatch and the OpenAPI document.
+type route struct {
+	method, path string
+	summary      string
+	request      reflect.Type // nil if there's no request body.
+	response     reflect.Type // nil for 204 No Content.
+	errors       []int        // HTTP statuses other than 400 and 502 the route may return.
+	handle       func(g *Gateway, ctx context.Context, r *http.Request, body interface{}) (interface{}, error)
+}
+
+var routes = []route{
+	{
+		method: "GET", path: "/groups", summary: "List groups in UI order",
+		response: reflect.TypeOf([]Group{}), handle: (*Gateway).listGroups,
+	},
+	{
+		method: "GET", path: "/groups/{group}", summary: "Get a group by number or name",
+		response: reflect.TypeOf(Group{}), errors: []int{http.StatusNotFound}, handle: (*Gateway).getGroup,
+	},
+	{
+		method: "PUT", path: "/groups/{group}", summary: "Set a group's intensity and/or name",
+		request: reflect.TypeOf(GroupUpdate{}), response: reflect.TypeOf(Group{}),
+		errors: []int{http.StatusNotFound, http.StatusConflict}, handle: (*Gateway).putGroup,
+	},
+	{
+		method: "GET", path: "/themes", summary: "List themes",
+		response: reflect.TypeOf([]Theme{}), handle: (*Gateway).listThemes,
+	},
+	{
+		method: "GET", path: "/themes/{letter}", summary: "Get a theme, including its definition",
+		response: reflect.TypeOf(ThemeDetail{}), errors: []int{http.StatusNotFound}, handle: (*Gateway).getTheme,
+	},
+	{
+		method: "PUT", path: "/themes/{letter}", summary: "Set a theme's name and/or definition",
+		request: reflect.TypeOf(ThemeUpdate{}), response: reflect.TypeOf(ThemeDetail{}),
+		errors: []int{http.StatusNotFound, http.StatusForbidden}, handle: (*Gateway).putTheme,
+	},
+	{
+		method: "POST", path: "/themes/{letter}/on", summary: "Illuminate a theme",
+		response: reflect.TypeOf(Theme{}), errors: []int{http.StatusNotFound}, handle: illuminateTheme(1),
+	},
+	{
+		method: "POST", path: "/themes/{letter}/off", summary: "Extinguish a theme",
+		response: reflect.TypeOf(Theme{}), errors: []int{http.StatusNotFound}, handle: illuminateTheme(0),
+	},
+	{
+		method: "POST", path: "/all/off", summary: "Extinguish all groups and themes",
+		handle: (*Gateway).allOff,
+	},
+}
+
+var pathParams = map[string]string{
+	"group":  "Group number or, if no group has that number, group name",
+	"letter": "Theme letter, A through Z",
+}
+
+// OpenAPI returns an OpenAPI 3 document describing the gateway, generated
+// from its routes and Go types.
+func OpenAPI() map[string]interface{} {
+	paths := map[string]interface{}{}
+	schemas := map[string]interface{}{}
+	for _, r := range routes {
+		op := map[string]interface{}{
+			"summary":     r.summary,
+			"operationId": operationID(r),
+		}
+		var params []interface{}
+		for _, seg := range strings.Split(r.path, "/") {
+			if strings.HasPrefix(seg, "{") {
+				name := strings.Trim(seg, "{}")
+				params = append(params, map[string]interface{}{
+					"name": name, "in": "path", "required": true,
+					"description": pathParams[name], "schema": map[string]interface{}{"type": "string"},
+				})
+			}
+		}
+		if params != nil {
+			op["parameters"] = params
+		}
+		if r.request != nil {
+			op["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content":  jsonContent(schemaFor(r.request, schemas)),
+			}
+		}
+		responses := map[string]interface{}{}
+		if r.response != nil {
+			responses["200"] = map[string]interface{}{
+				"description": "OK",
+				"content":     jsonContent(schemaFor(r.response, schemas)),
+			}
+		} else {
+			responses["204"] = map[string]interface{}{"description": "No Content"}
+		}
+		errorSchema := schemaFor(reflect.TypeOf(Error{}), schemas)
+		for _, status := range append([]int{http.StatusBadRequest, http.StatusBadGateway}, r.errors...) {
+			responses[strconv.Itoa(status)] = map[string]interface{}{
+				"description": http.StatusText(status),
+				"content":     jsonContent(errorSchema),
+			}
+		}
+		op["responses"] = responses
+		item, ok := paths[r.path].(map[string]interface{})
+		if !ok {
+			item = map[string]interface{}{}
+			paths[r.path] = item
+		}
+		item[strings.ToLower(r.method)] = op
+	}
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "Luxor REST gateway",
+			"version": "1",
+		},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": schemas},
+	}
+}
+
+func operationID(r route) string {
+	id := strings.ToLower(r.method)
+	for _, seg := range strings.Split(r.path, "/") {
+		seg = strings.Trim(seg, "{}")
+		if seg != "" {
+			id += strings.ToUpper(seg[:1]) + seg[1:]
+		}
+	}
+	return id
+}
+
+func jsonContent(schema interface{}) map[string]interface{} {
+	return map[string]interface{}{"application/json": map[string]interface{}{"schema": schema}}
+}
+
+// schemaFor returns a JSON schema for t, adding named struct types to
+// schemas and referring to them by $ref.
+func schemaFor(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaFor(t.Elem(), schemas)
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": schemaFor(t.Elem(), schemas)}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Uint8:
+		return map[string]interface{}{"type": "integer", "minimum": 0, "maximum": 255}
+	case reflect.Int:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Struct:
+		name := t.Name()
+		if _, ok := schemas[name]; !ok {
+			schemas[name] = nil // placeholder, in case of recursion.
+			props := map[string]interface{}{}
+			var required []string
+			addFields(t, props, &required, schemas)
+			s := map[string]interface{}{"type": "object", "properties": props}
+			if required != nil {
+				s["required"] = required
+			}
+			schemas[name] = s
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	}
+	panic("unsupported type " + t.String())
+}
+
+func addFields(t reflect.Type, props map[string]interface{}, required *[]string, schemas map[string]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			addFields(f.Type, props, required, schemas)
+			continue
+		}
+		props[f.Name] = schemaFor(f.Type, schemas)
+		if f.Type.Kind() != reflect.Ptr && !strings.Contains(f.Tag.Get("json"), "omitempty") {
+			*required = append(*required, f.Name)
+		}
+	}
+}
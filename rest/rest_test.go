@@ -0,0 +1,170 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/scottlamb/luxor/emulator"
+	"github.com/scottlamb/luxor/protocol"
+	"github.com/scottlamb/luxor/rest"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newServer(t *testing.T) (*emulator.Controller, *httptest.Server) {
+	ctx := context.Background()
+	c := emulator.New("luxor")
+	for _, g := range []protocol.GroupListAddRequest{{GroupNumber: 3, Name: "Front Path"}, {GroupNumber: 7, Name: "Patio"}} {
+		if _, err := c.GroupListAdd(ctx, &g); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := c.ThemeListAdd(ctx, &protocol.ThemeListAddRequest{ThemeIndex: 2, Name: "Party"}); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(rest.New(c))
+	t.Cleanup(server.Close)
+	return c, server
+}
+
+// do issues a request and returns the status and body.
+func do(t *testing.T, method, url, body string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, strings.TrimSpace(string(b))
+}
+
+func TestGroups(t *testing.T) {
+	_, server := newServer(t)
+	tests := []struct {
+		method, path, body string
+		wantStatus         int
+		wantBody           string
+	}{
+		{"GET", "/groups", "", 200,
+			`[{"GroupNumber":3,"Name":"Front Path","Intensity":0,"Order":0},{"GroupNumber":7,"Name":"Patio","Intensity":0,"Order":1}]`},
+		{"GET", "/groups/7", "", 200, `{"GroupNumber":7,"Name":"Patio","Intensity":0,"Order":1}`},
+		{"GET", "/groups/Front%20Path", "", 200, `{"GroupNumber":3,"Name":"Front Path","Intensity":0,"Order":0}`},
+		{"GET", "/groups/4", "", 404, `{"Error":"group \"4\": not found"}`},
+		{"PUT", "/groups/Patio", `{"Intensity":60}`, 200, `{"GroupNumber":7,"Name":"Patio","Intensity":60,"Order":1}`},
+		{"PUT", "/groups/7", `{"Intensity":101}`, 400, `{"Error":"Intensity must be in [0, 100]"}`},
+		{"PUT", "/groups/7", `{"Bogus":1}`, 400, `{"Error":"bad request body: json: unknown field \"Bogus\""}`},
+		{"PUT", "/groups/7", `{"Name":"Front Path"}`, 409, `{"Error":"group name in use","Status":202}`},
+		{"PUT", "/groups/7", `{"Name":"Deck"}`, 200, `{"GroupNumber":7,"Name":"Deck","Intensity":60,"Order":1}`},
+		{"DELETE", "/groups/7", "", 405, "Method Not Allowed"},
+	}
+	for _, test := range tests {
+		status, body := do(t, test.method, server.URL+test.path, test.body)
+		if status != test.wantStatus || body != test.wantBody {
+			t.Errorf("%s %s %s: got %d %s; want %d %s", test.method, test.path, test.body,
+				status, body, test.wantStatus, test.wantBody)
+		}
+	}
+}
+
+func TestThemes(t *testing.T) {
+	c, server := newServer(t)
+	tests := []struct {
+		method, path, body string
+		wantStatus         int
+		wantBody           string
+	}{
+		{"GET", "/themes", "", 200, `[{"ThemeIndex":2,"Letter":"C","Name":"Party","On":false}]`},
+		{"GET", "/themes/c", "", 200, `{"ThemeIndex":2,"Letter":"C","Name":"Party","On":false,"Groups":[]}`},
+		{"GET", "/themes/D", "", 404, `{"Error":"theme D: not found"}`},
+		{"GET", "/themes/AA", "", 400, `{"Error":"bad theme letter \"AA\""}`},
+		{"PUT", "/themes/C", `{"Name":"Dinner","Groups":[{"GroupNumber":3,"Intensity":80}]}`, 200,
+			`{"ThemeIndex":2,"Letter":"C","Name":"Dinner","On":false,"Groups":[{"GroupNumber":3,"Intensity":80}]}`},
+		{"PUT", "/themes/C", `{"Name":"","Groups":[]}`, 400, `{"Error":"Name must be 1 to 19 bytes"}`},
+		{"PUT", "/themes/C", `{"Groups":[{"GroupNumber":3,"Intensity":50},{"GroupNumber":9,"Intensity":80},{"GroupNumber":3,"Intensity":80}]}`, 200,
+			`{"ThemeIndex":2,"Letter":"C","Name":"Dinner","On":false,"Groups":[{"GroupNumber":3,"Intensity":80}]}`},
+		{"POST", "/themes/C/on", "", 200, `{"ThemeIndex":2,"Letter":"C","Name":"Dinner","On":true}`},
+		{"GET", "/groups/3", "", 200, `{"GroupNumber":3,"Name":"Front Path","Intensity":80,"Order":0}`},
+		{"POST", "/themes/C/off", "", 200, `{"ThemeIndex":2,"Letter":"C","Name":"Dinner","On":false}`},
+		{"PUT", "/groups/3", `{"Intensity":40}`, 200, `{"GroupNumber":3,"Name":"Front Path","Intensity":40,"Order":0}`},
+		{"POST", "/all/off", "", 204, ""},
+		{"GET", "/groups/3", "", 200, `{"GroupNumber":3,"Name":"Front Path","Intensity":0,"Order":0}`},
+	}
+	for _, test := range tests {
+		status, body := do(t, test.method, server.URL+test.path, test.body)
+		if status != test.wantStatus || body != test.wantBody {
+			t.Errorf("%s %s %s: got %d %s; want %d %s", test.method, test.path, test.body,
+				status, body, test.wantStatus, test.wantBody)
+		}
+	}
+
+	c.SetRestricted(true)
+	status, body := do(t, "PUT", server.URL+"/themes/C", `{"Name":"Other"}`)
+	if status != http.StatusOK {
+		// Renames are allowed even while restricted.
+		t.Errorf("rename while restricted: got %d %s", status, body)
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	_, server := newServer(t)
+	status, body := do(t, "GET", server.URL+"/openapi.json", "")
+	if status != http.StatusOK {
+		t.Fatalf("got status %d", status)
+	}
+	var doc struct {
+		OpenAPI    string
+		Paths      map[string]map[string]json.RawMessage
+		Components struct {
+			Schemas map[string]json.RawMessage
+		}
+	}
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		t.Fatal(err)
+	}
+	for path, methods := range map[string][]string{
+		"/groups":              {"get"},
+		"/groups/{group}":      {"get", "put"},
+		"/themes":              {"get"},
+		"/themes/{letter}":     {"get", "put"},
+		"/themes/{letter}/on":  {"post"},
+		"/themes/{letter}/off": {"post"},
+		"/all/off":             {"post"},
+	} {
+		for _, m := range methods {
+			if _, ok := doc.Paths[path][m]; !ok {
+				t.Errorf("missing %s %s", m, path)
+			}
+		}
+	}
+	for _, name := range []string{"Group", "GroupUpdate", "Theme", "ThemeDetail", "ThemeUpdate", "ThemeGroup", "Error"} {
+		if _, ok := doc.Components.Schemas[name]; !ok {
+			t.Errorf("missing schema %s", name)
+		}
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	for status, want := range map[int]int{
+		protocol.StatusGroupNameInUse:       http.StatusConflict,
+		protocol.StatusGroupNumberInUse:     http.StatusConflict,
+		protocol.StatusPreconditionFailed:   http.StatusNotFound,
+		protocol.StatusThemeIndexOutOfRange: http.StatusNotFound,
+		protocol.StatusInvalidRequest:       http.StatusForbidden,
+		protocol.StatusUnknownMethod:        http.StatusNotImplemented,
+		999:                                 http.StatusBadGateway,
+	} {
+		if got := rest.HTTPStatus(protocol.ErrorForStatus(status)); got != want {
+			t.Errorf("status %d: got %d; want %d", status, got, want)
+		}
+	}
+}
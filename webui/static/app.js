@@ -0,0 +1,266 @@
+'use strict';
+
+// Messages for the controller's application-level statuses; see the
+// protocol package.
+const statusMessages = {
+  1: 'unknown method',
+  101: 'unparseable request',
+  102: 'invalid request (are themes restricted in the setup menu?)',
+  201: 'precondition failed',
+  202: 'group name in use',
+  205: 'group number in use',
+  243: 'theme index out of range',
+};
+
+const pollIntervalMs = 5000;
+
+// Minimum time between IlluminateGroup calls while dragging a slider.
+const sliderIntervalMs = 150;
+
+let groups = [];
+let themes = [];
+
+// busy suppresses polling while the user is mid-gesture, so a refresh
+// doesn't yank a slider or list item out from under them. Every increment
+// must be matched by exactly one decrement however the gesture ends, or
+// polling stops for good.
+let busy = 0;
+
+async function call(method, request) {
+  const resp = await fetch('rpc/' + method + '.json', {
+    method: 'POST',
+    headers: {'Content-Type': 'application/json'},
+    body: JSON.stringify(request || {}),
+  });
+  if (!resp.ok) {
+    throw new Error(method + ': ' + (await resp.text()).trim());
+  }
+  const body = await resp.json();
+  if (body.Status) {
+    throw new Error(method + ': ' + (statusMessages[body.Status] || 'status ' + body.Status));
+  }
+  return body;
+}
+
+function showError(err) {
+  const e = document.getElementById('error');
+  if (err) {
+    e.textContent = err.message || String(err);
+    e.hidden = false;
+  } else {
+    e.hidden = true;
+  }
+}
+
+// run calls fn, showing any failure, then refreshes.
+async function run(fn) {
+  try {
+    await fn();
+    showError(null);
+  } catch (err) {
+    showError(err);
+  }
+  await refresh();
+}
+
+function letter(themeIndex) {
+  return String.fromCharCode(65 + themeIndex);
+}
+
+async function refresh() {
+  if (busy > 0) {
+    return;
+  }
+  try {
+    const [g, t] = await Promise.all([call('GroupListGet'), call('ThemeListGet')]);
+    groups = g.GroupList || [];
+    themes = t.ThemeList || [];
+    if (busy === 0) {
+      render();
+    }
+  } catch (err) {
+    showError(err);
+  }
+}
+
+function render() {
+  const groupList = document.getElementById('groups');
+  groupList.replaceChildren(...groups.map(groupRow));
+  const themeList = document.getElementById('themes');
+  themeList.replaceChildren(...themes.map(themeRow));
+}
+
+function groupRow(g) {
+  const li = document.getElementById('group-row').content.firstElementChild.cloneNode(true);
+  li.dataset.id = g.GroupNumber;
+  li.querySelector('.name').textContent = g.Name;
+  const slider = li.querySelector('input');
+  const output = li.querySelector('output');
+  slider.value = g.Intensity;
+  output.textContent = g.Intensity;
+
+  // Send updates while dragging, at most once per sliderIntervalMs, always
+  // finishing with the final value.
+  let last = 0;
+  let timer = null;
+  const send = () => {
+    timer = null;
+    last = Date.now();
+    call('IlluminateGroup', {GroupNumber: g.GroupNumber, Intensity: Number(slider.value)})
+      .then(() => showError(null), showError);
+  };
+  // Each press holds busy from pointerdown until the pointer is released
+  // anywhere or the press is cancelled, whether or not the value changed, so
+  // the counter always balances.
+  let pressed = false;
+  const release = () => {
+    if (pressed) {
+      pressed = false;
+      busy = Math.max(0, busy - 1);
+    }
+  };
+  slider.addEventListener('pointerdown', () => {
+    if (!pressed) {
+      pressed = true;
+      busy++;
+      window.addEventListener('pointerup', release, {once: true});
+      window.addEventListener('pointercancel', release, {once: true});
+    }
+  });
+  slider.addEventListener('lostpointercapture', release);
+  slider.addEventListener('input', () => {
+    output.textContent = slider.value;
+    if (timer === null) {
+      timer = setTimeout(send, Math.max(0, last + sliderIntervalMs - Date.now()));
+    }
+  });
+  slider.addEventListener('change', () => {
+    if (timer !== null) {
+      clearTimeout(timer);
+    }
+    send();
+  });
+  return li;
+}
+
+function themeRow(t) {
+  const li = document.getElementById('theme-row').content.firstElementChild.cloneNode(true);
+  li.dataset.id = t.ThemeIndex;
+  li.querySelector('.letter').textContent = letter(t.ThemeIndex);
+  li.querySelector('.name').textContent = t.Name;
+  const button = li.querySelector('.theme');
+  button.classList.toggle('on', t.OnOff !== 0);
+  button.addEventListener('click', () => run(() =>
+    call('IlluminateTheme', {ThemeIndex: t.ThemeIndex, OnOff: t.OnOff ? 0 : 1})));
+  li.querySelector('.edit').addEventListener('click', () => edit(t).catch(showError));
+  return li;
+}
+
+// makeSortable lets the items of list be reordered by drag-and-drop,
+// calling onReorder with the items' data-id values in their new order.
+function makeSortable(list, onReorder) {
+  let dragged = null;
+  list.addEventListener('dragstart', (e) => {
+    dragged = e.target.closest('li');
+    if (!dragged) {
+      return;
+    }
+    busy++;
+    dragged.classList.add('dragging');
+    e.dataTransfer.effectAllowed = 'move';
+  });
+  list.addEventListener('dragover', (e) => {
+    if (!dragged) {
+      return;
+    }
+    e.preventDefault();
+    const over = e.target.closest('li');
+    if (!over || over === dragged) {
+      return;
+    }
+    const rect = over.getBoundingClientRect();
+    const after = e.clientY > rect.top + rect.height / 2;
+    list.insertBefore(dragged, after ? over.nextSibling : over);
+  });
+  list.addEventListener('dragend', () => {
+    if (!dragged) {
+      return;
+    }
+    dragged.classList.remove('dragging');
+    dragged = null;
+    busy = Math.max(0, busy - 1);
+    const order = Array.from(list.children, (li) => Number(li.dataset.id));
+    run(() => onReorder(order));
+  });
+}
+
+// edit opens the theme editor, which builds a ThemeSet request from the
+// checked groups.
+async function edit(t) {
+  const def = await call('ThemeGet', {ThemeIndex: t.ThemeIndex});
+  // Entries keep their Color, which the editor doesn't change.
+  const entries = new Map();
+  for (const tg of def.Groups || []) {
+    entries.set(tg.GroupNumber, tg);
+  }
+  document.getElementById('editor-title').textContent =
+    'Theme ' + letter(t.ThemeIndex) + ': ' + t.Name;
+  const rows = groups.map((g) => {
+    const tr = document.createElement('tr');
+    const include = document.createElement('input');
+    include.type = 'checkbox';
+    const entry = entries.get(g.GroupNumber);
+    include.checked = entry !== undefined;
+    const name = document.createElement('td');
+    name.textContent = g.Name;
+    const slider = document.createElement('input');
+    slider.type = 'range';
+    slider.min = 0;
+    slider.max = 100;
+    slider.value = entry !== undefined ? entry.Intensity : 50;
+    const output = document.createElement('output');
+    output.textContent = slider.value;
+    slider.addEventListener('input', () => {
+      output.textContent = slider.value;
+      include.checked = true;
+    });
+    const includeCell = document.createElement('td');
+    includeCell.append(include);
+    const sliderCell = document.createElement('td');
+    sliderCell.append(slider, ' ', output);
+    tr.append(includeCell, name, sliderCell);
+    return {group: g, color: entry !== undefined ? entry.Color : undefined, include, slider, tr};
+  });
+  document.getElementById('editor-groups').replaceChildren(...rows.map((r) => r.tr));
+  const dialog = document.getElementById('editor');
+  busy++;
+  dialog.addEventListener('close', () => {
+    busy = Math.max(0, busy - 1);
+    if (dialog.returnValue !== 'save') {
+      return;
+    }
+    const request = {
+      ThemeIndex: t.ThemeIndex,
+      Groups: rows.filter((r) => r.include.checked).map((r) => ({
+        GroupNumber: r.group.GroupNumber,
+        Intensity: Number(r.slider.value),
+        Color: r.color,
+      })),
+    };
+    run(() => call('ThemeSet', request));
+  }, {once: true});
+  dialog.returnValue = '';
+  dialog.showModal();
+}
+
+document.getElementById('all-off').addEventListener('click', () => run(() => call('ExtinguishAll')));
+makeSortable(document.getElementById('groups'),
+  (order) => call('GroupListReorder', {GroupNumbers: order}));
+makeSortable(document.getElementById('themes'),
+  (order) => call('ThemeListReorder', {ThemeIndexes: order}));
+call('ControllerName').then((resp) => {
+  document.getElementById('controller').textContent = resp.Controller;
+  document.title = resp.Controller;
+}, showError);
+refresh();
+setInterval(refresh, pollIntervalMs);
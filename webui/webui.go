@@ -0,0 +1,69 @@
+// Package webui serves a browser control panel for any protocol.Controller,
+// independent of the vendor's phone app. The page and its script are
+// embedded in the binary; the script calls the controller's methods through
+// the same JSON-over-HTTP protocol the wi-fi module speaks, relayed at
+// rpc/Method.json. Only the methods the script uses are relayed, and only
+// for same-origin JSON requests, so other sites can't drive the controller
+// through a visitor's browser. All URLs are relative, so the handler may be
+// mounted under a prefix with http.StripPrefix.
+package webui
+
+import (
+	"embed"
+	"github.com/scottlamb/luxor/emulator"
+	"github.com/scottlamb/luxor/protocol"
+	"io/fs"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+//go:embed static
+var static embed.FS
+
+// rpcMethods are the methods app.js calls.
+var rpcMethods = map[string]bool{
+	"ControllerName":   true,
+	"ExtinguishAll":    true,
+	"GroupListGet":     true,
+	"GroupListReorder": true,
+	"IlluminateGroup":  true,
+	"IlluminateTheme":  true,
+	"ThemeGet":         true,
+	"ThemeListGet":     true,
+	"ThemeListReorder": true,
+	"ThemeSet":         true,
+}
+
+// rpcGuard passes on requests for rpcMethods with a JSON body. Browsers
+// won't send that content type cross-origin without a CORS preflight, which
+// goes unanswered, and http.CrossOriginProtection refuses the rest.
+type rpcGuard struct {
+	next http.Handler
+}
+
+func (g rpcGuard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+	name, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/"), ".json")
+	if !ok || !rpcMethods[name] {
+		http.Error(w, "method not available", http.StatusNotFound)
+		return
+	}
+	g.next.ServeHTTP(w, r)
+}
+
+// New returns a handler serving the control panel for controller.
+func New(controller protocol.Controller) http.Handler {
+	assets, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err) // the embedded directory always exists.
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/", http.FileServer(http.FS(assets)))
+	rpc := rpcGuard{next: &emulator.Handler{Controller: controller}}
+	mux.Handle("/rpc/", http.StripPrefix("/rpc", http.NewCrossOriginProtection().Handler(rpc)))
+	return mux
+}
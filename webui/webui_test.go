@@ -0,0 +1,114 @@
+package webui_test
+
+import (
+	"context"
+	"github.com/scottlamb/luxor/client"
+	"github.com/scottlamb/luxor/emulator"
+	"github.com/scottlamb/luxor/protocol"
+	"github.com/scottlamb/luxor/webui"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func get(t *testing.T, url string) (string, string) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s: status %d", url, resp.StatusCode)
+	}
+	return resp.Header.Get("Content-Type"), string(body)
+}
+
+func TestAssets(t *testing.T) {
+	server := httptest.NewServer(http.StripPrefix("/ui", webui.New(emulator.New("test"))))
+	defer server.Close()
+	for path, want := range map[string]string{
+		"/ui/":          "text/html",
+		"/ui/app.js":    "text/javascript",
+		"/ui/style.css": "text/css",
+	} {
+		contentType, body := get(t, server.URL+path)
+		if !strings.HasPrefix(contentType, want) || body == "" {
+			t.Errorf("%s: got %s, %d bytes", path, contentType, len(body))
+		}
+	}
+}
+
+func TestRPC(t *testing.T) {
+	ctx := context.Background()
+	e := emulator.New("test")
+	server := httptest.NewServer(webui.New(e))
+	defer server.Close()
+
+	// The script speaks the controller's own protocol, so a client.Controller
+	// exercises the same path.
+	c := &client.Controller{BaseURL: server.URL + "/rpc"}
+	for _, g := range []protocol.GroupListAddRequest{{GroupNumber: 1, Name: "One"}, {GroupNumber: 2, Name: "Two"}} {
+		if _, err := e.GroupListAdd(ctx, &g); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := c.GroupListReorder(ctx, &protocol.GroupListReorderRequest{GroupNumbers: []uint8{2, 1}}); err != nil {
+		t.Fatal(err)
+	}
+	groups, err := e.GroupListGet(ctx, &protocol.GroupListGetRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups.GroupList) != 2 || groups.GroupList[0].GroupNumber != 2 {
+		t.Errorf("unexpected groups %+v", groups.GroupList)
+	}
+	if _, err := c.ThemeSet(ctx, &protocol.ThemeSetRequest{ThemeIndex: 3}); protocol.StatusOf(err) != protocol.StatusPreconditionFailed {
+		t.Errorf("ThemeSet of missing theme: got %v", err)
+	}
+}
+
+func TestRPCGuard(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(webui.New(emulator.New("test")))
+	defer server.Close()
+
+	// Methods the page doesn't use aren't relayed.
+	c := &client.Controller{BaseURL: server.URL + "/rpc"}
+	if _, err := c.GroupListAdd(ctx, &protocol.GroupListAddRequest{GroupNumber: 1, Name: "One"}); err == nil {
+		t.Error("GroupListAdd: no error")
+	}
+
+	for _, test := range []struct {
+		name       string
+		header     map[string]string
+		wantStatus int
+	}{
+		{"same origin", map[string]string{"Content-Type": "application/json", "Sec-Fetch-Site": "same-origin"}, http.StatusOK},
+		{"form post", map[string]string{"Content-Type": "text/plain"}, http.StatusUnsupportedMediaType},
+		{"cross site", map[string]string{"Content-Type": "application/json", "Sec-Fetch-Site": "cross-site"}, http.StatusForbidden},
+		{"other origin", map[string]string{"Content-Type": "application/json", "Origin": "http://example.com"}, http.StatusForbidden},
+	} {
+		req, err := http.NewRequest("POST", server.URL+"/rpc/ExtinguishAll.json", strings.NewReader("{}"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range test.header {
+			req.Header.Set(k, v)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != test.wantStatus {
+			t.Errorf("%s: got status %d; want %d", test.name, resp.StatusCode, test.wantStatus)
+		}
+	}
+}